@@ -17,6 +17,7 @@ import (
 	"github.com/joao-moreira/doctor-tracker/internal/domain/auth"
 	"github.com/joao-moreira/doctor-tracker/internal/domain/finance"
 	"github.com/joao-moreira/doctor-tracker/internal/domain/schedule"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
 	"github.com/joao-moreira/doctor-tracker/internal/domain/workplace"
 )
 
@@ -59,7 +60,17 @@ func main() {
 		calSyncer = gcalService
 	}
 	scheduleService := schedule.NewService(scheduleRepo, workplaceRepo, calSyncer)
-	financeService := finance.NewService(financeRepo, workplaceRepo, scheduleRepo)
+	financeService := finance.NewService(financeRepo, workplaceRepo, scheduleRepo, authRepo, tax.DefaultRegistry())
+
+	// Nightly reminder about past shifts that still need reviewing
+	go runDaily(ctx, 2, func(ctx context.Context) {
+		count, err := scheduleService.RemindPendingReview(ctx, time.Now())
+		if err != nil {
+			slog.Error("pending review job failed", "error", err)
+			return
+		}
+		slog.Info("pending review job finished", "shifts", count)
+	})
 
 	// HTTP Server
 	router := httpAdapter.NewServer(cfg, authService, workplaceService, scheduleService, financeService, gcalService, scheduleRepo)
@@ -96,3 +107,23 @@ func main() {
 
 	slog.Info("server stopped")
 }
+
+// runDaily calls job every day at the given local hour until ctx is done.
+func runDaily(ctx context.Context, hour int, job func(context.Context)) {
+	for {
+		now := time.Now()
+		next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, now.Location())
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			job(ctx)
+		}
+	}
+}
@@ -0,0 +1 @@
+ALTER TABLE workplaces ADD COLUMN nif VARCHAR(9);
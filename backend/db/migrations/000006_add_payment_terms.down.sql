@@ -0,0 +1,2 @@
+ALTER TABLE invoices DROP COLUMN IF EXISTS due_at;
+ALTER TABLE workplaces DROP COLUMN IF EXISTS payment_terms_days;
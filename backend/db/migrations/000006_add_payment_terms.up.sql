@@ -0,0 +1,3 @@
+ALTER TABLE workplaces ADD COLUMN payment_terms_days INT NOT NULL DEFAULT 30;
+ALTER TABLE invoices ADD COLUMN due_at DATE;
+UPDATE invoices SET due_at = issued_at + 30 WHERE issued_at IS NOT NULL;
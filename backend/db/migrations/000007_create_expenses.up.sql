@@ -0,0 +1,22 @@
+CREATE TABLE expenses (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id         UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    workplace_id    UUID REFERENCES workplaces(id) ON DELETE SET NULL,
+
+    date            DATE NOT NULL,
+    category        VARCHAR(50) NOT NULL,
+    description     TEXT,
+    supplier_nif    VARCHAR(9),
+
+    amount_cents    BIGINT NOT NULL,
+    iva_cents       BIGINT NOT NULL DEFAULT 0,
+
+    in_efatura      BOOLEAN NOT NULL DEFAULT true,
+    attachment_ref  TEXT,
+
+    notes           TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_expenses_user_date ON expenses(user_id, date);
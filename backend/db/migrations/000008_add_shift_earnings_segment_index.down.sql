@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_shift_earnings_segment;
@@ -0,0 +1 @@
+CREATE INDEX idx_shift_earnings_segment ON shift_earnings(segment_start, segment_end);
@@ -0,0 +1 @@
+ALTER TABLE workplaces DROP COLUMN IF EXISTS commute_minutes;
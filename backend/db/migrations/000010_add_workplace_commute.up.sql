@@ -0,0 +1 @@
+ALTER TABLE workplaces ADD COLUMN commute_minutes INT NOT NULL DEFAULT 0;
@@ -0,0 +1,15 @@
+CREATE TABLE income_goals (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id         UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    workplace_id    UUID REFERENCES workplaces(id) ON DELETE CASCADE,
+
+    year            INT NOT NULL,
+    month           INT CHECK (month BETWEEN 1 AND 12),
+    basis           VARCHAR(10) NOT NULL DEFAULT 'gross',
+    target_cents    BIGINT NOT NULL,
+
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_income_goals_user_year ON income_goals(user_id, year);
@@ -0,0 +1,4 @@
+ALTER TABLE shift_earnings DROP COLUMN IF EXISTS quantity;
+ALTER TABLE shift_earnings DROP COLUMN IF EXISTS rate_multiplier;
+ALTER TABLE shift_earnings DROP COLUMN IF EXISTS rule_name;
+ALTER TABLE shift_earnings DROP COLUMN IF EXISTS kind;
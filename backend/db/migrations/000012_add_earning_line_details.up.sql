@@ -0,0 +1,4 @@
+ALTER TABLE shift_earnings ADD COLUMN kind VARCHAR(20) NOT NULL DEFAULT 'time';
+ALTER TABLE shift_earnings ADD COLUMN rule_name VARCHAR(255);
+ALTER TABLE shift_earnings ADD COLUMN rate_multiplier NUMERIC(6,3);
+ALTER TABLE shift_earnings ADD COLUMN quantity INT;
@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN IF EXISTS ifici_start_year;
+ALTER TABLE users DROP COLUMN IF EXISTS irs_jovem_first_year;
+ALTER TABLE users DROP COLUMN IF EXISTS birth_year;
@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN birth_year INT;
+ALTER TABLE users ADD COLUMN irs_jovem_first_year INT;
+ALTER TABLE users ADD COLUMN ifici_start_year INT;
@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN IF EXISTS ss_base_adjustment;
+ALTER TABLE users DROP COLUMN IF EXISTS activity_start_date;
@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN activity_start_date DATE;
+ALTER TABLE users ADD COLUMN ss_base_adjustment INT NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+ALTER TABLE users DROP COLUMN IF EXISTS withholding_dispensed;
+
+UPDATE workplaces SET withholding_rate = 0.25 WHERE withholding_rate IS NULL;
+ALTER TABLE workplaces ALTER COLUMN withholding_rate SET DEFAULT 0.25;
+ALTER TABLE workplaces ALTER COLUMN withholding_rate SET NOT NULL;
+ALTER TABLE workplaces DROP COLUMN IF EXISTS private_client;
@@ -0,0 +1,13 @@
+-- Private individuals do not withhold. Existing workplaces are not flagged:
+-- a zero rate may have been set for other reasons, so users mark their
+-- private clients themselves.
+ALTER TABLE workplaces ADD COLUMN private_client BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- A NULL rate applies the fiscal year's default; a stored rate, 0 included,
+-- is the one agreed with the client. Workplaces still on the old 25% column
+-- default never chose it, so they move to the year's default.
+ALTER TABLE workplaces ALTER COLUMN withholding_rate DROP NOT NULL;
+ALTER TABLE workplaces ALTER COLUMN withholding_rate DROP DEFAULT;
+UPDATE workplaces SET withholding_rate = NULL WHERE withholding_rate = 0.25;
+
+ALTER TABLE users ADD COLUMN withholding_dispensed BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,13 @@
+CREATE TABLE tax_scenarios (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id         UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+    year            INT NOT NULL,
+    name            VARCHAR(100) NOT NULL,
+    overrides       JSONB NOT NULL DEFAULT '{}'::jsonb,
+
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_tax_scenarios_user_year ON tax_scenarios(user_id, year);
@@ -0,0 +1,2 @@
+ALTER TABLE workplaces DROP COLUMN IF EXISTS tax_country;
+ALTER TABLE users DROP COLUMN IF EXISTS tax_country;
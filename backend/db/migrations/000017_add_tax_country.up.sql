@@ -0,0 +1,4 @@
+-- Jurisdiction whose tax rules apply: the user's tax residence, and for a
+-- workplace the country its income is sourced in when that differs
+ALTER TABLE users ADD COLUMN tax_country CHAR(2) NOT NULL DEFAULT 'PT';
+ALTER TABLE workplaces ADD COLUMN tax_country CHAR(2);
@@ -0,0 +1,14 @@
+CREATE TABLE payments_on_account (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id         UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+    fiscal_year     INT NOT NULL,
+    instalment      SMALLINT NOT NULL CHECK (instalment BETWEEN 1 AND 3),
+    amount_cents    BIGINT NOT NULL CHECK (amount_cents > 0),
+    paid_on         DATE NOT NULL,
+    reference       VARCHAR(50),
+
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_payments_on_account_user_year ON payments_on_account(user_id, fiscal_year);
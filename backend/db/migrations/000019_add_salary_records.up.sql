@@ -0,0 +1,16 @@
+CREATE TABLE salary_records (
+    id                      UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id                 UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+    employer                VARCHAR(255) NOT NULL,
+    period                  DATE NOT NULL, -- first day of the month paid
+    gross_cents             BIGINT NOT NULL CHECK (gross_cents > 0),
+    withholding_cents       BIGINT NOT NULL DEFAULT 0 CHECK (withholding_cents >= 0),
+    social_security_cents   BIGINT NOT NULL DEFAULT 0 CHECK (social_security_cents >= 0),
+    notes                   TEXT,
+
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_salary_records_user_period ON salary_records(user_id, period);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS review_reminders;
+DROP TABLE IF EXISTS review_runs;
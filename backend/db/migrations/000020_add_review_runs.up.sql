@@ -0,0 +1,17 @@
+-- Nightly review of past shifts still scheduled or confirmed. Each run is
+-- recorded; the reminders are replaced by every run that succeeds, so the
+-- app can prompt the user whichever instance ran the job.
+CREATE TABLE review_runs (
+    id          UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ran_at      TIMESTAMPTZ NOT NULL,
+    succeeded   BOOLEAN NOT NULL
+);
+
+CREATE INDEX idx_review_runs_ran_at ON review_runs(ran_at);
+
+CREATE TABLE review_reminders (
+    user_id         UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    ran_at          TIMESTAMPTZ NOT NULL,
+    pending         INT NOT NULL CHECK (pending > 0),
+    oldest_start    TIMESTAMPTZ NOT NULL
+);
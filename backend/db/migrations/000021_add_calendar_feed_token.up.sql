@@ -0,0 +1,3 @@
+-- Calendar applications subscribe to feeds by URL, without a bearer token.
+-- The feed token in the URL is stored hashed and can be replaced or revoked.
+ALTER TABLE users ADD COLUMN calendar_feed_token_hash VARCHAR(64) UNIQUE;
@@ -105,3 +105,56 @@ func (h *AuthHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 
 	dto.JSON(w, http.StatusOK, user)
 }
+
+func (h *AuthHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	var input auth.UpdateProfileInput
+	if err := dto.Decode(r, &input); err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := h.service.UpdateProfile(r.Context(), userID, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrInvalidTimezone), errors.Is(err, auth.ErrInvalidTaxRegime),
+			errors.Is(err, auth.ErrInvalidTaxProfile):
+			dto.Error(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, auth.ErrUserNotFound):
+			dto.Error(w, http.StatusNotFound, "user not found")
+		default:
+			dto.Error(w, http.StatusInternalServerError, "failed to update profile")
+		}
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, user)
+}
+
+// CreateCalendarFeed issues a new feed token, revoking the previous one, and
+// returns the tax calendar's subscription URL.
+func (h *AuthHandler) CreateCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	token, err := h.service.CreateCalendarFeedToken(r.Context(), userID)
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to create calendar feed")
+		return
+	}
+
+	dto.JSON(w, http.StatusCreated, map[string]string{
+		"token":            token,
+		"tax_calendar_url": "/api/v1/feeds/" + token + "/tax-calendar.ics",
+	})
+}
+
+func (h *AuthHandler) RevokeCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	if err := h.service.RevokeCalendarFeedToken(r.Context(), userID); err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to revoke calendar feed")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,6 +1,10 @@
 package handler
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -11,18 +15,15 @@ import (
 	"github.com/joao-moreira/doctor-tracker/internal/adapter/http/middleware"
 	"github.com/joao-moreira/doctor-tracker/internal/domain/finance"
 	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/workplace"
 )
 
 type FinanceHandler struct {
-	service   *finance.Service
-	taxEngine tax.Engine
+	service *finance.Service
 }
 
 func NewFinanceHandler(service *finance.Service) *FinanceHandler {
-	return &FinanceHandler{
-		service:   service,
-		taxEngine: tax.NewPortugalEngine(),
-	}
+	return &FinanceHandler{service: service}
 }
 
 func (h *FinanceHandler) GetSummary(w http.ResponseWriter, r *http.Request) {
@@ -120,116 +121,954 @@ func (h *FinanceHandler) GetProjections(w http.ResponseWriter, r *http.Request)
 	dto.JSON(w, http.StatusOK, projections)
 }
 
-func (h *FinanceHandler) GetTaxEstimate(w http.ResponseWriter, r *http.Request) {
+func (h *FinanceHandler) GetCashFlowForecast(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
-	year, _ := strconv.Atoi(chi.URLParam(r, "year"))
+	year := time.Now().Year()
+	if y := r.URL.Query().Get("year"); y != "" {
+		year, _ = strconv.Atoi(y)
+	}
+
+	forecast, err := h.service.GetCashFlowForecast(r.Context(), userID, year)
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to get cash-flow forecast")
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, forecast)
+}
+
+func (h *FinanceHandler) ComparePeriods(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	current := r.URL.Query().Get("current")
+	if current == "" {
+		current = strconv.Itoa(time.Now().Year())
+	}
+
+	comparison, err := h.service.ComparePeriods(r.Context(), userID, current, r.URL.Query().Get("previous"))
+	if err != nil {
+		if errors.Is(err, finance.ErrInvalidPeriod) {
+			dto.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		dto.Error(w, http.StatusInternalServerError, "failed to compare periods")
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, comparison)
+}
+
+func (h *FinanceHandler) GetProfitability(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	start, _ := time.Parse("2006-01-02", r.URL.Query().Get("start"))
+	end, _ := time.Parse("2006-01-02", r.URL.Query().Get("end"))
+	if start.IsZero() {
+		start = time.Date(time.Now().Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if end.IsZero() {
+		end = start.AddDate(1, 0, 0)
+	} else {
+		end = end.AddDate(0, 0, 1) // end date is inclusive
+	}
+	if !end.After(start) {
+		dto.Error(w, http.StatusBadRequest, "end must not be before start")
+		return
+	}
+
+	report, err := h.service.GetProfitability(r.Context(), userID, start, end)
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to get profitability report")
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, report)
+}
 
+func (h *FinanceHandler) GetGoals(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	year := time.Now().Year()
+	if y := r.URL.Query().Get("year"); y != "" {
+		year, _ = strconv.Atoi(y)
+	}
 	if year == 0 {
 		dto.Error(w, http.StatusBadRequest, "invalid year")
 		return
 	}
 
-	// Get yearly earnings
-	summary, err := h.service.GetYearlySummary(r.Context(), userID, year)
+	progress, err := h.service.GetGoalProgress(r.Context(), userID, year, time.Now())
 	if err != nil {
-		dto.Error(w, http.StatusInternalServerError, "failed to get earnings for tax calculation")
+		dto.Error(w, http.StatusInternalServerError, "failed to get goals")
 		return
 	}
 
-	// Get tax config for the year
-	var taxConfig tax.YearConfig
-	switch year {
-	case 2026:
-		taxConfig = tax.Portugal2026Config()
-	case 2025:
-		taxConfig = tax.Portugal2025Config()
-	default:
-		taxConfig = tax.Portugal2026Config()
-		taxConfig.FiscalYear = year
+	dto.JSON(w, http.StatusOK, progress)
+}
+
+func (h *FinanceHandler) CreateGoal(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	var input finance.CreateGoalInput
+	if err := dto.Decode(r, &input); err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid request body")
+		return
 	}
 
-	annualSummary := h.taxEngine.CalculateAnnualSummary(taxConfig, summary.GrossEarnings)
+	goal, err := h.service.CreateGoal(r.Context(), userID, input)
+	if err != nil {
+		if errors.Is(err, finance.ErrInvalidGoal) {
+			dto.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		dto.Error(w, http.StatusInternalServerError, "failed to create goal")
+		return
+	}
 
-	dto.JSON(w, http.StatusOK, annualSummary)
+	dto.JSON(w, http.StatusCreated, goal)
 }
 
-func (h *FinanceHandler) ListInvoices(w http.ResponseWriter, r *http.Request) {
+func (h *FinanceHandler) UpdateGoal(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid goal id")
+		return
+	}
 
-	startStr := r.URL.Query().Get("start")
-	endStr := r.URL.Query().Get("end")
-	start, _ := time.Parse("2006-01-02", startStr)
-	end, _ := time.Parse("2006-01-02", endStr)
+	var input finance.UpdateGoalInput
+	if err := dto.Decode(r, &input); err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
 
-	if start.IsZero() {
-		start = time.Date(time.Now().Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	goal, err := h.service.UpdateGoal(r.Context(), userID, id, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, finance.ErrGoalNotFound):
+			dto.Error(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, finance.ErrInvalidGoal):
+			dto.Error(w, http.StatusBadRequest, err.Error())
+		default:
+			dto.Error(w, http.StatusInternalServerError, "failed to update goal")
+		}
+		return
 	}
-	if end.IsZero() {
-		end = time.Date(time.Now().Year(), 12, 31, 23, 59, 59, 0, time.UTC)
+
+	dto.JSON(w, http.StatusOK, goal)
+}
+
+func (h *FinanceHandler) DeleteGoal(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid goal id")
+		return
 	}
 
-	var wpID *uuid.UUID
-	if wp := r.URL.Query().Get("workplace_id"); wp != "" {
-		id, err := uuid.Parse(wp)
-		if err == nil {
-			wpID = &id
+	if err := h.service.DeleteGoal(r.Context(), userID, id); err != nil {
+		if errors.Is(err, finance.ErrGoalNotFound) {
+			dto.Error(w, http.StatusNotFound, err.Error())
+			return
 		}
+		dto.Error(w, http.StatusInternalServerError, "failed to delete goal")
+		return
 	}
 
-	invoices, err := h.service.ListInvoices(r.Context(), userID, wpID, start, end)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *FinanceHandler) GetPaymentsOnAccount(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	year := time.Now().Year()
+	if y := r.URL.Query().Get("year"); y != "" {
+		year, _ = strconv.Atoi(y)
+	}
+	if year == 0 {
+		dto.Error(w, http.StatusBadRequest, "invalid year")
+		return
+	}
+
+	report, err := h.service.GetPaymentsOnAccount(r.Context(), userID, year)
 	if err != nil {
-		dto.Error(w, http.StatusInternalServerError, "failed to list invoices")
+		dto.Error(w, http.StatusInternalServerError, "failed to get payments on account")
 		return
 	}
 
-	dto.JSON(w, http.StatusOK, invoices)
+	dto.JSON(w, http.StatusOK, report)
 }
 
-func (h *FinanceHandler) CreateInvoice(w http.ResponseWriter, r *http.Request) {
+func (h *FinanceHandler) RecordPaymentOnAccount(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 
-	var input finance.CreateInvoiceInput
+	var input finance.RecordPaymentOnAccountInput
 	if err := dto.Decode(r, &input); err != nil {
 		dto.Error(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
-	invoice, err := h.service.CreateInvoice(r.Context(), userID, input)
+	payment, err := h.service.RecordPaymentOnAccount(r.Context(), userID, input)
 	if err != nil {
-		dto.Error(w, http.StatusInternalServerError, "failed to create invoice")
+		if errors.Is(err, finance.ErrInvalidPaymentOnAccount) {
+			dto.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		dto.Error(w, http.StatusInternalServerError, "failed to record payment on account")
 		return
 	}
 
-	dto.JSON(w, http.StatusCreated, invoice)
+	dto.JSON(w, http.StatusCreated, payment)
 }
 
-func (h *FinanceHandler) GetInvoice(w http.ResponseWriter, r *http.Request) {
+func (h *FinanceHandler) DeletePaymentOnAccount(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
-		dto.Error(w, http.StatusBadRequest, "invalid invoice id")
+		dto.Error(w, http.StatusBadRequest, "invalid payment id")
 		return
 	}
 
-	invoice, err := h.service.GetInvoice(r.Context(), id)
+	if err := h.service.DeletePaymentOnAccount(r.Context(), userID, id); err != nil {
+		if errors.Is(err, finance.ErrPaymentOnAccountNotFound) {
+			dto.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		dto.Error(w, http.StatusInternalServerError, "failed to delete payment on account")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *FinanceHandler) GetSalaryRecords(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	year := time.Now().Year()
+	if y := r.URL.Query().Get("year"); y != "" {
+		year, _ = strconv.Atoi(y)
+	}
+	if year == 0 {
+		dto.Error(w, http.StatusBadRequest, "invalid year")
+		return
+	}
+
+	records, err := h.service.ListSalaryRecords(r.Context(), userID, year)
 	if err != nil {
-		dto.Error(w, http.StatusNotFound, "invoice not found")
+		dto.Error(w, http.StatusInternalServerError, "failed to list salary records")
 		return
 	}
 
-	dto.JSON(w, http.StatusOK, invoice)
+	dto.JSON(w, http.StatusOK, records)
 }
 
-func (h *FinanceHandler) DeleteInvoice(w http.ResponseWriter, r *http.Request) {
+func (h *FinanceHandler) CreateSalaryRecord(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	var input finance.CreateSalaryRecordInput
+	if err := dto.Decode(r, &input); err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	record, err := h.service.CreateSalaryRecord(r.Context(), userID, input)
+	if err != nil {
+		if errors.Is(err, finance.ErrInvalidSalaryRecord) {
+			dto.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		dto.Error(w, http.StatusInternalServerError, "failed to create salary record")
+		return
+	}
+
+	dto.JSON(w, http.StatusCreated, record)
+}
+
+func (h *FinanceHandler) UpdateSalaryRecord(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
-		dto.Error(w, http.StatusBadRequest, "invalid invoice id")
+		dto.Error(w, http.StatusBadRequest, "invalid salary record id")
 		return
 	}
 
-	if err := h.service.DeleteInvoice(r.Context(), id); err != nil {
-		dto.Error(w, http.StatusInternalServerError, "failed to delete invoice")
+	var input finance.UpdateSalaryRecordInput
+	if err := dto.Decode(r, &input); err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	record, err := h.service.UpdateSalaryRecord(r.Context(), userID, id, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, finance.ErrSalaryRecordNotFound):
+			dto.Error(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, finance.ErrInvalidSalaryRecord):
+			dto.Error(w, http.StatusBadRequest, err.Error())
+		default:
+			dto.Error(w, http.StatusInternalServerError, "failed to update salary record")
+		}
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, record)
+}
+
+func (h *FinanceHandler) DeleteSalaryRecord(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid salary record id")
+		return
+	}
+
+	if err := h.service.DeleteSalaryRecord(r.Context(), userID, id); err != nil {
+		if errors.Is(err, finance.ErrSalaryRecordNotFound) {
+			dto.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		dto.Error(w, http.StatusInternalServerError, "failed to delete salary record")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *FinanceHandler) GetScenarios(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	year := time.Now().Year()
+	if y := r.URL.Query().Get("year"); y != "" {
+		year, _ = strconv.Atoi(y)
+	}
+	if year == 0 {
+		dto.Error(w, http.StatusBadRequest, "invalid year")
+		return
+	}
+
+	scenarios, err := h.service.ListScenarios(r.Context(), userID, year)
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to list scenarios")
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, scenarios)
+}
+
+func (h *FinanceHandler) CreateScenario(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	var input finance.CreateScenarioInput
+	if err := dto.Decode(r, &input); err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	scenario, err := h.service.CreateScenario(r.Context(), userID, input)
+	if err != nil {
+		if errors.Is(err, finance.ErrInvalidScenario) {
+			dto.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		dto.Error(w, http.StatusInternalServerError, "failed to create scenario")
+		return
+	}
+
+	dto.JSON(w, http.StatusCreated, scenario)
+}
+
+func (h *FinanceHandler) UpdateScenario(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid scenario id")
+		return
+	}
+
+	var input finance.UpdateScenarioInput
+	if err := dto.Decode(r, &input); err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	scenario, err := h.service.UpdateScenario(r.Context(), userID, id, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, finance.ErrScenarioNotFound):
+			dto.Error(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, finance.ErrInvalidScenario):
+			dto.Error(w, http.StatusBadRequest, err.Error())
+		default:
+			dto.Error(w, http.StatusInternalServerError, "failed to update scenario")
+		}
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, scenario)
+}
+
+func (h *FinanceHandler) DeleteScenario(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid scenario id")
+		return
+	}
+
+	if err := h.service.DeleteScenario(r.Context(), userID, id); err != nil {
+		if errors.Is(err, finance.ErrScenarioNotFound) {
+			dto.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		dto.Error(w, http.StatusInternalServerError, "failed to delete scenario")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (h *FinanceHandler) CompareScenarios(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	year := time.Now().Year()
+	if y := r.URL.Query().Get("year"); y != "" {
+		year, _ = strconv.Atoi(y)
+	}
+	if year == 0 {
+		dto.Error(w, http.StatusBadRequest, "invalid year")
+		return
+	}
+
+	comparison, err := h.service.CompareScenarios(r.Context(), userID, year)
+	if err != nil {
+		if errors.Is(err, finance.ErrInvalidScenario) {
+			dto.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		dto.Error(w, http.StatusInternalServerError, "failed to compare scenarios")
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, comparison)
+}
+
+func (h *FinanceHandler) GetTaxEstimate(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	year, _ := strconv.Atoi(chi.URLParam(r, "year"))
+
+	if year == 0 {
+		dto.Error(w, http.StatusBadRequest, "invalid year")
+		return
+	}
+
+	estimate, err := h.service.GetTaxEstimate(r.Context(), userID, year, time.Now())
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to calculate tax estimate")
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, estimate)
+}
+
+func (h *FinanceHandler) GetMarginalAnalysis(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	year, _ := strconv.Atoi(chi.URLParam(r, "year"))
+
+	if year == 0 {
+		dto.Error(w, http.StatusBadRequest, "invalid year")
+		return
+	}
+
+	analysis, err := h.service.GetMarginalAnalysis(r.Context(), userID, year)
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to calculate marginal tax")
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, analysis)
+}
+
+func (h *FinanceHandler) GetTaxJurisdictions(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	year, _ := strconv.Atoi(chi.URLParam(r, "year"))
+
+	if year == 0 {
+		dto.Error(w, http.StatusBadRequest, "invalid year")
+		return
+	}
+
+	report, err := h.service.GetJurisdictionReport(r.Context(), userID, year)
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to calculate tax by jurisdiction")
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, report)
+}
+
+func (h *FinanceHandler) GetSocialSecurity(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	year, _ := strconv.Atoi(chi.URLParam(r, "year"))
+
+	if year == 0 {
+		dto.Error(w, http.StatusBadRequest, "invalid year")
+		return
+	}
+
+	report, err := h.service.GetSocialSecurity(r.Context(), userID, year, time.Now())
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to get social security declarations")
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, report)
+}
+
+func (h *FinanceHandler) GetTaxCalendar(w http.ResponseWriter, r *http.Request) {
+	year, _ := strconv.Atoi(chi.URLParam(r, "year"))
+	calendar, ok := h.taxCalendar(w, r, year)
+	if !ok {
+		return
+	}
+	dto.JSON(w, http.StatusOK, calendar)
+}
+
+// GetTaxCalendarICS exports a year's deadlines as an iCalendar file to
+// import into a calendar application.
+func (h *FinanceHandler) GetTaxCalendarICS(w http.ResponseWriter, r *http.Request) {
+	year, _ := strconv.Atoi(chi.URLParam(r, "year"))
+	calendar, ok := h.taxCalendar(w, r, year)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="tax-calendar-%d.ics"`, calendar.Year))
+	writeICS(w, calendar)
+}
+
+// GetTaxCalendarFeed serves the current year's deadlines to calendar
+// applications subscribed to the feed. It is authenticated by the feed token
+// in its URL rather than the bearer token.
+func (h *FinanceHandler) GetTaxCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	calendar, ok := h.taxCalendar(w, r, time.Now().Year())
+	if !ok {
+		return
+	}
+	writeICS(w, calendar)
+}
+
+func writeICS(w http.ResponseWriter, calendar *tax.Calendar) {
+	var buf bytes.Buffer
+	if err := calendar.WriteICS(&buf, time.Now()); err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to render tax calendar")
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	buf.WriteTo(w)
+}
+
+func (h *FinanceHandler) taxCalendar(w http.ResponseWriter, r *http.Request, year int) (*tax.Calendar, bool) {
+	userID := middleware.GetUserID(r.Context())
+
+	if year == 0 {
+		dto.Error(w, http.StatusBadRequest, "invalid year")
+		return nil, false
+	}
+
+	calendar, err := h.service.GetTaxCalendar(r.Context(), userID, year, time.Now())
+	if err != nil {
+		if errors.Is(err, finance.ErrCalendarUnavailable) {
+			dto.Error(w, http.StatusBadRequest, err.Error())
+			return nil, false
+		}
+		dto.Error(w, http.StatusInternalServerError, "failed to get tax calendar")
+		return nil, false
+	}
+	return calendar, true
+}
+
+func (h *FinanceHandler) GetAnnualReportPDF(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	year, _ := strconv.Atoi(chi.URLParam(r, "year"))
+
+	if year == 0 {
+		dto.Error(w, http.StatusBadRequest, "invalid year")
+		return
+	}
+
+	report, err := h.service.GetAnnualReport(r.Context(), userID, year, time.Now())
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to get annual report")
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := report.WritePDF(&buf); err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to render annual report")
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="annual-report-%d.pdf"`, year))
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	buf.WriteTo(w)
+}
+
+func (h *FinanceHandler) GetWithholdingStatus(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	year, _ := strconv.Atoi(chi.URLParam(r, "year"))
+
+	if year == 0 {
+		dto.Error(w, http.StatusBadRequest, "invalid year")
+		return
+	}
+
+	status, err := h.service.GetWithholdingStatus(r.Context(), userID, year, time.Now())
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to get withholding status")
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, status)
+}
+
+func (h *FinanceHandler) CompareRegimes(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	year, _ := strconv.Atoi(chi.URLParam(r, "year"))
+
+	if year == 0 {
+		dto.Error(w, http.StatusBadRequest, "invalid year")
+		return
+	}
+
+	comparison, err := h.service.CompareRegimes(r.Context(), userID, year)
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to compare tax regimes")
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, comparison)
+}
+
+func (h *FinanceHandler) SimulateSettlement(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	year, _ := strconv.Atoi(chi.URLParam(r, "year"))
+
+	if year == 0 {
+		dto.Error(w, http.StatusBadRequest, "invalid year")
+		return
+	}
+
+	var input finance.SimulateSettlementInput
+	if err := dto.Decode(r, &input); err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	settlement, err := h.service.SimulateSettlement(r.Context(), userID, year, input)
+	if err != nil {
+		if errors.Is(err, finance.ErrInvalidSettlement) {
+			dto.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		dto.Error(w, http.StatusInternalServerError, "failed to simulate tax settlement")
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, settlement)
+}
+
+func (h *FinanceHandler) GetReceivables(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	asOf := time.Now()
+	if d := r.URL.Query().Get("as_of"); d != "" {
+		parsed, err := time.Parse("2006-01-02", d)
+		if err != nil {
+			dto.Error(w, http.StatusBadRequest, "invalid as_of date")
+			return
+		}
+		asOf = parsed
+	}
+
+	report, err := h.service.GetReceivables(r.Context(), userID, asOf)
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to get receivables")
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, report)
+}
+
+func (h *FinanceHandler) ListInvoices(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	startStr := r.URL.Query().Get("start")
+	endStr := r.URL.Query().Get("end")
+	start, _ := time.Parse("2006-01-02", startStr)
+	end, _ := time.Parse("2006-01-02", endStr)
+
+	if start.IsZero() {
+		start = time.Date(time.Now().Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if end.IsZero() {
+		end = time.Date(time.Now().Year(), 12, 31, 23, 59, 59, 0, time.UTC)
+	}
+
+	var wpID *uuid.UUID
+	if wp := r.URL.Query().Get("workplace_id"); wp != "" {
+		id, err := uuid.Parse(wp)
+		if err == nil {
+			wpID = &id
+		}
+	}
+
+	invoices, err := h.service.ListInvoices(r.Context(), userID, wpID, start, end)
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to list invoices")
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, invoices)
+}
+
+func (h *FinanceHandler) CreateInvoice(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	var input finance.CreateInvoiceInput
+	if err := dto.Decode(r, &input); err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	invoice, err := h.service.CreateInvoice(r.Context(), userID, input)
+	if err != nil {
+		if errors.Is(err, workplace.ErrWorkplaceNotFound) {
+			dto.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		dto.Error(w, http.StatusInternalServerError, "failed to create invoice")
+		return
+	}
+
+	dto.JSON(w, http.StatusCreated, invoice)
+}
+
+func (h *FinanceHandler) GetInvoice(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid invoice id")
+		return
+	}
+
+	invoice, err := h.service.GetInvoice(r.Context(), id)
+	if err != nil {
+		dto.Error(w, http.StatusNotFound, "invoice not found")
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, invoice)
+}
+
+func (h *FinanceHandler) DeleteInvoice(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid invoice id")
+		return
+	}
+
+	if err := h.service.DeleteInvoice(r.Context(), id); err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to delete invoice")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *FinanceHandler) ImportBankStatement(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid multipart form")
+		return
+	}
+
+	file, _, err := r.FormFile("statement")
+	if err != nil {
+		dto.Error(w, http.StatusBadRequest, "missing statement file")
+		return
+	}
+	defer file.Close()
+
+	var mapping *finance.CSVColumnMapping
+	if m := r.FormValue("mapping"); m != "" {
+		mapping = &finance.CSVColumnMapping{}
+		if err := json.Unmarshal([]byte(m), mapping); err != nil {
+			dto.Error(w, http.StatusBadRequest, "invalid column mapping")
+			return
+		}
+	}
+
+	format := finance.StatementFormat(r.FormValue("format"))
+	result, err := h.service.ProposePaymentMatches(r.Context(), userID, format, file, mapping)
+	if err != nil {
+		if errors.Is(err, finance.ErrInvalidStatement) || errors.Is(err, finance.ErrUnsupportedStatementFormat) {
+			dto.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		dto.Error(w, http.StatusInternalServerError, "failed to reconcile statement")
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, result)
+}
+
+func (h *FinanceHandler) ConfirmPayments(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	var body struct {
+		Payments []finance.ConfirmPaymentInput `json:"payments"`
+	}
+	if err := dto.Decode(r, &body); err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	invoices, err := h.service.ConfirmPayments(r.Context(), userID, body.Payments)
+	if err != nil {
+		if errors.Is(err, finance.ErrInvoiceNotFound) {
+			dto.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		dto.Error(w, http.StatusInternalServerError, "failed to confirm payments")
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, invoices)
+}
+
+func (h *FinanceHandler) ListExpenseCategories(w http.ResponseWriter, r *http.Request) {
+	dto.JSON(w, http.StatusOK, finance.ExpenseCategoryRules)
+}
+
+func (h *FinanceHandler) ListExpenses(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	start, _ := time.Parse("2006-01-02", r.URL.Query().Get("start"))
+	end, _ := time.Parse("2006-01-02", r.URL.Query().Get("end"))
+	if start.IsZero() {
+		start = time.Date(time.Now().Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if end.IsZero() {
+		end = start.AddDate(1, 0, 0)
+	}
+
+	expenses, err := h.service.ListExpenses(r.Context(), userID, start, end)
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to list expenses")
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, expenses)
+}
+
+func (h *FinanceHandler) CreateExpense(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	var input finance.CreateExpenseInput
+	if err := dto.Decode(r, &input); err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	expense, err := h.service.CreateExpense(r.Context(), userID, input)
+	if err != nil {
+		if errors.Is(err, finance.ErrInvalidExpenseCategory) || errors.Is(err, workplace.ErrWorkplaceNotFound) {
+			dto.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		dto.Error(w, http.StatusInternalServerError, "failed to create expense")
+		return
+	}
+
+	dto.JSON(w, http.StatusCreated, expense)
+}
+
+func (h *FinanceHandler) GetExpense(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid expense id")
+		return
+	}
+
+	expense, err := h.service.GetExpense(r.Context(), userID, id)
+	if err != nil {
+		dto.Error(w, http.StatusNotFound, "expense not found")
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, expense)
+}
+
+func (h *FinanceHandler) UpdateExpense(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid expense id")
+		return
+	}
+
+	var input finance.UpdateExpenseInput
+	if err := dto.Decode(r, &input); err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	expense, err := h.service.UpdateExpense(r.Context(), userID, id, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, finance.ErrExpenseNotFound):
+			dto.Error(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, finance.ErrInvalidExpenseCategory), errors.Is(err, workplace.ErrWorkplaceNotFound):
+			dto.Error(w, http.StatusBadRequest, err.Error())
+		default:
+			dto.Error(w, http.StatusInternalServerError, "failed to update expense")
+		}
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, expense)
+}
+
+func (h *FinanceHandler) DeleteExpense(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid expense id")
+		return
+	}
+
+	if err := h.service.DeleteExpense(r.Context(), userID, id); err != nil {
+		if errors.Is(err, finance.ErrExpenseNotFound) {
+			dto.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		dto.Error(w, http.StatusInternalServerError, "failed to delete expense")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *FinanceHandler) GetExpenseReport(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	year, _ := strconv.Atoi(chi.URLParam(r, "year"))
+
+	if year == 0 {
+		dto.Error(w, http.StatusBadRequest, "invalid year")
+		return
+	}
+
+	report, err := h.service.GetExpenseReport(r.Context(), userID, year)
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to get expense report")
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, report)
+}
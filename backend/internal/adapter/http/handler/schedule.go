@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
@@ -108,6 +109,10 @@ func (h *ScheduleHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	shift, err := h.service.UpdateShift(r.Context(), id, input)
 	if err != nil {
+		if errors.Is(err, schedule.ErrInvalidTransition) || errors.Is(err, schedule.ErrShiftNotEnded) {
+			dto.Error(w, http.StatusConflict, err.Error())
+			return
+		}
 		dto.Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -115,6 +120,85 @@ func (h *ScheduleHandler) Update(w http.ResponseWriter, r *http.Request) {
 	dto.JSON(w, http.StatusOK, shift)
 }
 
+func (h *ScheduleHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid shift id")
+		return
+	}
+
+	var input schedule.UpdateShiftStatusInput
+	if err := dto.Decode(r, &input); err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	shift, err := h.service.UpdateShiftStatus(r.Context(), userID, id, input.Status)
+	if err != nil {
+		writeStatusError(w, err)
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, shift)
+}
+
+func (h *ScheduleHandler) ConfirmEarnings(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid shift id")
+		return
+	}
+
+	shift, err := h.service.ConfirmEarnings(r.Context(), userID, id)
+	if err != nil {
+		writeStatusError(w, err)
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, shift)
+}
+
+func (h *ScheduleHandler) ListPendingReview(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	shifts, err := h.service.ListPendingReview(r.Context(), userID, time.Now())
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to list shifts pending review")
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, shifts)
+}
+
+func (h *ScheduleHandler) GetLastReviewRun(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	run, err := h.service.LastReviewRun(r.Context(), userID)
+	if errors.Is(err, schedule.ErrNoReviewRun) {
+		dto.Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to get the last review run")
+		return
+	}
+
+	dto.JSON(w, http.StatusOK, run)
+}
+
+func writeStatusError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, schedule.ErrShiftNotFound):
+		dto.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, schedule.ErrInvalidTransition), errors.Is(err, schedule.ErrShiftNotEnded):
+		dto.Error(w, http.StatusConflict, err.Error())
+	default:
+		dto.Error(w, http.StatusInternalServerError, "failed to update shift status")
+	}
+}
+
 func (h *ScheduleHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/joao-moreira/doctor-tracker/internal/domain/auth"
 )
@@ -46,6 +47,22 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 	})
 }
 
+// AuthenticateFeed authenticates calendar feeds by the feed token in the
+// route's {token} parameter, as calendar applications cannot send a bearer
+// token.
+func (m *AuthMiddleware) AuthenticateFeed(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, err := m.authService.ValidateCalendarFeedToken(r.Context(), chi.URLParam(r, "token"))
+		if err != nil {
+			http.Error(w, `{"error":"invalid or revoked feed token"}`, http.StatusUnauthorized)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), UserIDKey, userID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // GetUserID extracts the authenticated user ID from the request context.
 func GetUserID(ctx context.Context) uuid.UUID {
 	id, _ := ctx.Value(UserIDKey).(uuid.UUID)
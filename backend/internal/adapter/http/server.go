@@ -62,6 +62,14 @@ func NewServer(
 			r.Post("/auth/refresh", authHandler.RefreshToken)
 		})
 
+		// Calendar feeds, authenticated by the feed token in the URL so that
+		// calendar applications can subscribe to them
+		r.Group(func(r chi.Router) {
+			r.Use(authMiddleware.AuthenticateFeed)
+
+			r.Get("/feeds/{token}/tax-calendar.ics", financeHandler.GetTaxCalendarFeed)
+		})
+
 		// Protected routes
 		r.Group(func(r chi.Router) {
 			r.Use(authMiddleware.Authenticate)
@@ -69,6 +77,9 @@ func NewServer(
 			// Auth
 			r.Post("/auth/logout", authHandler.Logout)
 			r.Get("/auth/me", authHandler.GetMe)
+			r.Put("/auth/me", authHandler.UpdateMe)
+			r.Post("/auth/me/calendar-feed", authHandler.CreateCalendarFeed)
+			r.Delete("/auth/me/calendar-feed", authHandler.RevokeCalendarFeed)
 
 			// Workplaces
 			r.Get("/workplaces", workplaceHandler.List)
@@ -85,11 +96,15 @@ func NewServer(
 			// Shifts
 			r.Get("/shifts", scheduleHandler.List)
 			r.Post("/shifts", scheduleHandler.Create)
+			r.Get("/shifts/pending-review", scheduleHandler.ListPendingReview)
+			r.Get("/shifts/pending-review/last-run", scheduleHandler.GetLastReviewRun)
 			r.Get("/shifts/{id}", scheduleHandler.Get)
 			r.Put("/shifts/{id}", scheduleHandler.Update)
 			r.Delete("/shifts/{id}", scheduleHandler.Delete)
+			r.Patch("/shifts/{id}/status", scheduleHandler.UpdateStatus)
 			r.Post("/shifts/bulk", scheduleHandler.BulkCreate)
 			r.Get("/shifts/{id}/earnings", scheduleHandler.GetEarnings)
+			r.Post("/shifts/{id}/earnings/confirm", scheduleHandler.ConfirmEarnings)
 
 			// Finance
 			r.Get("/finance/summary", financeHandler.GetSummary)
@@ -97,7 +112,39 @@ func NewServer(
 			r.Get("/finance/summary/yearly/{year}", financeHandler.GetYearlySummary)
 			r.Get("/finance/monthly-breakdown/{year}", financeHandler.GetMonthlyBreakdown)
 			r.Get("/finance/projections", financeHandler.GetProjections)
+			r.Get("/finance/cash-flow", financeHandler.GetCashFlowForecast)
+			r.Get("/finance/compare", financeHandler.ComparePeriods)
+			r.Get("/finance/profitability", financeHandler.GetProfitability)
+			r.Get("/finance/goals", financeHandler.GetGoals)
+			r.Post("/finance/goals", financeHandler.CreateGoal)
+			r.Put("/finance/goals/{id}", financeHandler.UpdateGoal)
+			r.Delete("/finance/goals/{id}", financeHandler.DeleteGoal)
+			r.Get("/finance/payments-on-account", financeHandler.GetPaymentsOnAccount)
+			r.Post("/finance/payments-on-account", financeHandler.RecordPaymentOnAccount)
+			r.Delete("/finance/payments-on-account/{id}", financeHandler.DeletePaymentOnAccount)
+			r.Get("/finance/salaries", financeHandler.GetSalaryRecords)
+			r.Post("/finance/salaries", financeHandler.CreateSalaryRecord)
+			r.Put("/finance/salaries/{id}", financeHandler.UpdateSalaryRecord)
+			r.Delete("/finance/salaries/{id}", financeHandler.DeleteSalaryRecord)
+			r.Get("/finance/scenarios", financeHandler.GetScenarios)
+			r.Post("/finance/scenarios", financeHandler.CreateScenario)
+			r.Get("/finance/scenarios/compare", financeHandler.CompareScenarios)
+			r.Put("/finance/scenarios/{id}", financeHandler.UpdateScenario)
+			r.Delete("/finance/scenarios/{id}", financeHandler.DeleteScenario)
 			r.Get("/finance/tax-estimate/{year}", financeHandler.GetTaxEstimate)
+			r.Get("/finance/tax-estimate/{year}/marginal", financeHandler.GetMarginalAnalysis)
+			r.Get("/finance/tax-estimate/{year}/jurisdictions", financeHandler.GetTaxJurisdictions)
+			r.Post("/finance/tax-settlement/{year}", financeHandler.SimulateSettlement)
+			r.Get("/finance/tax-regimes/{year}", financeHandler.CompareRegimes)
+			r.Get("/finance/social-security/{year}", financeHandler.GetSocialSecurity)
+			r.Get("/finance/tax-calendar/{year}", financeHandler.GetTaxCalendar)
+			r.Get("/finance/tax-calendar/{year}.ics", financeHandler.GetTaxCalendarICS)
+			r.Get("/finance/withholding/{year}", financeHandler.GetWithholdingStatus)
+			r.Get("/finance/receivables", financeHandler.GetReceivables)
+			r.Get("/finance/expense-report/{year}", financeHandler.GetExpenseReport)
+			r.Get("/finance/reports/{year}.pdf", financeHandler.GetAnnualReportPDF)
+			r.Post("/finance/reconciliation/import", financeHandler.ImportBankStatement)
+			r.Post("/finance/reconciliation/confirm", financeHandler.ConfirmPayments)
 
 			// Invoices
 			r.Get("/invoices", financeHandler.ListInvoices)
@@ -105,6 +152,14 @@ func NewServer(
 			r.Get("/invoices/{id}", financeHandler.GetInvoice)
 			r.Delete("/invoices/{id}", financeHandler.DeleteInvoice)
 
+			// Expenses
+			r.Get("/expenses", financeHandler.ListExpenses)
+			r.Post("/expenses", financeHandler.CreateExpense)
+			r.Get("/expenses/categories", financeHandler.ListExpenseCategories)
+			r.Get("/expenses/{id}", financeHandler.GetExpense)
+			r.Put("/expenses/{id}", financeHandler.UpdateExpense)
+			r.Delete("/expenses/{id}", financeHandler.DeleteExpense)
+
 			// Google Calendar
 			r.Get("/gcal/auth-url", gcalHandler.GetAuthURL)
 			r.Post("/gcal/callback", gcalHandler.HandleCallback)
@@ -20,23 +20,27 @@ func NewAuthRepository(db *DB) *AuthRepository {
 
 func (r *AuthRepository) CreateUser(ctx context.Context, user *auth.User) error {
 	_, err := r.db.Pool.Exec(ctx, `
-		INSERT INTO users (id, email, password_hash, full_name, nif, tax_regime, activity_code, irs_category, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO users (id, email, password_hash, full_name, nif, tax_regime, activity_code, irs_category, timezone, tax_country, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`, user.ID, user.Email, user.PasswordHash, user.FullName, user.NIF,
-		user.TaxRegime, user.ActivityCode, user.IRSCategory, user.CreatedAt, user.UpdatedAt)
+		user.TaxRegime, user.ActivityCode, user.IRSCategory, user.Timezone, user.TaxCountry, user.CreatedAt, user.UpdatedAt)
 	return err
 }
 
 func (r *AuthRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*auth.User, error) {
 	user := &auth.User{}
 	err := r.db.Pool.QueryRow(ctx, `
-		SELECT id, email, password_hash, full_name, nif, tax_regime, activity_code, irs_category,
+		SELECT id, email, password_hash, full_name, nif, tax_regime, activity_code, irs_category, timezone,
+		       birth_year, irs_jovem_first_year, ifici_start_year, activity_start_date, ss_base_adjustment,
+		       withholding_dispensed, tax_country,
 		       gcal_access_token, gcal_refresh_token, gcal_token_expiry, gcal_calendar_id,
 		       created_at, updated_at
 		FROM users WHERE id = $1
 	`, id).Scan(
 		&user.ID, &user.Email, &user.PasswordHash, &user.FullName, &user.NIF,
-		&user.TaxRegime, &user.ActivityCode, &user.IRSCategory,
+		&user.TaxRegime, &user.ActivityCode, &user.IRSCategory, &user.Timezone,
+		&user.BirthYear, &user.IRSJovemFirstYear, &user.IFICIStartYear, &user.ActivityStartDate, &user.SSBaseAdjustment,
+		&user.WithholdingDispensed, &user.TaxCountry,
 		&user.GCalAccessToken, &user.GCalRefreshToken, &user.GCalTokenExpiry, &user.GCalCalendarID,
 		&user.CreatedAt, &user.UpdatedAt,
 	)
@@ -49,12 +53,16 @@ func (r *AuthRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*auth.U
 func (r *AuthRepository) GetUserByEmail(ctx context.Context, email string) (*auth.User, error) {
 	user := &auth.User{}
 	err := r.db.Pool.QueryRow(ctx, `
-		SELECT id, email, password_hash, full_name, nif, tax_regime, activity_code, irs_category,
+		SELECT id, email, password_hash, full_name, nif, tax_regime, activity_code, irs_category, timezone,
+		       birth_year, irs_jovem_first_year, ifici_start_year, activity_start_date, ss_base_adjustment,
+		       withholding_dispensed, tax_country,
 		       created_at, updated_at
 		FROM users WHERE email = $1
 	`, email).Scan(
 		&user.ID, &user.Email, &user.PasswordHash, &user.FullName, &user.NIF,
-		&user.TaxRegime, &user.ActivityCode, &user.IRSCategory,
+		&user.TaxRegime, &user.ActivityCode, &user.IRSCategory, &user.Timezone,
+		&user.BirthYear, &user.IRSJovemFirstYear, &user.IFICIStartYear, &user.ActivityStartDate, &user.SSBaseAdjustment,
+		&user.WithholdingDispensed, &user.TaxCountry,
 		&user.CreatedAt, &user.UpdatedAt,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -66,13 +74,17 @@ func (r *AuthRepository) GetUserByEmail(ctx context.Context, email string) (*aut
 func (r *AuthRepository) UpdateUser(ctx context.Context, user *auth.User) error {
 	_, err := r.db.Pool.Exec(ctx, `
 		UPDATE users SET
-			full_name = $2, nif = $3, tax_regime = $4, activity_code = $5, irs_category = $6,
-			gcal_access_token = $7, gcal_refresh_token = $8, gcal_token_expiry = $9, gcal_calendar_id = $10,
-			updated_at = $11
+			full_name = $2, nif = $3, tax_regime = $4, activity_code = $5, irs_category = $6, timezone = $7,
+			gcal_access_token = $8, gcal_refresh_token = $9, gcal_token_expiry = $10, gcal_calendar_id = $11,
+			birth_year = $12, irs_jovem_first_year = $13, ifici_start_year = $14,
+			activity_start_date = $15, ss_base_adjustment = $16, withholding_dispensed = $17,
+			updated_at = $18, tax_country = $19
 		WHERE id = $1
-	`, user.ID, user.FullName, user.NIF, user.TaxRegime, user.ActivityCode, user.IRSCategory,
+	`, user.ID, user.FullName, user.NIF, user.TaxRegime, user.ActivityCode, user.IRSCategory, user.Timezone,
 		user.GCalAccessToken, user.GCalRefreshToken, user.GCalTokenExpiry, user.GCalCalendarID,
-		time.Now())
+		user.BirthYear, user.IRSJovemFirstYear, user.IFICIStartYear,
+		user.ActivityStartDate, user.SSBaseAdjustment, user.WithholdingDispensed,
+		time.Now(), user.TaxCountry)
 	return err
 }
 
@@ -112,3 +124,21 @@ func (r *AuthRepository) RevokeAllUserRefreshTokens(ctx context.Context, userID
 	`, userID)
 	return err
 }
+
+func (r *AuthRepository) SetCalendarFeedTokenHash(ctx context.Context, userID uuid.UUID, hash *string) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE users SET calendar_feed_token_hash = $2, updated_at = NOW() WHERE id = $1
+	`, userID, hash)
+	return err
+}
+
+func (r *AuthRepository) GetUserIDByCalendarFeedTokenHash(ctx context.Context, hash string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT id FROM users WHERE calendar_feed_token_hash = $1
+	`, hash).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, auth.ErrInvalidToken
+	}
+	return userID, err
+}
@@ -23,12 +23,12 @@ func (r *FinanceRepository) CreateInvoice(ctx context.Context, invoice *finance.
 	_, err := r.db.Pool.Exec(ctx, `
 		INSERT INTO invoices (id, user_id, workplace_id, period_start, period_end,
 			gross_amount_cents, withholding_rate, withholding_cents, iva_rate, iva_cents,
-			net_amount_cents, invoice_number, issued_at, paid_at, notes, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+			net_amount_cents, invoice_number, issued_at, due_at, paid_at, notes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 	`, invoice.ID, invoice.UserID, invoice.WorkplaceID, invoice.PeriodStart, invoice.PeriodEnd,
 		int64(invoice.GrossAmountCents), invoice.WithholdingRate, int64(invoice.WithholdingCents),
 		invoice.IVARate, int64(invoice.IVACents), int64(invoice.NetAmountCents),
-		invoice.InvoiceNumber, invoice.IssuedAt, invoice.PaidAt, invoice.Notes,
+		invoice.InvoiceNumber, invoice.IssuedAt, invoice.DueAt, invoice.PaidAt, invoice.Notes,
 		invoice.CreatedAt, invoice.UpdatedAt)
 	return err
 }
@@ -39,12 +39,12 @@ func (r *FinanceRepository) GetInvoiceByID(ctx context.Context, id uuid.UUID) (*
 	err := r.db.Pool.QueryRow(ctx, `
 		SELECT id, user_id, workplace_id, period_start, period_end,
 			gross_amount_cents, withholding_rate, withholding_cents, iva_rate, iva_cents,
-			net_amount_cents, invoice_number, issued_at, paid_at, notes, created_at, updated_at
+			net_amount_cents, invoice_number, issued_at, due_at, paid_at, notes, created_at, updated_at
 		FROM invoices WHERE id = $1
 	`, id).Scan(
 		&inv.ID, &inv.UserID, &inv.WorkplaceID, &inv.PeriodStart, &inv.PeriodEnd,
 		&gross, &inv.WithholdingRate, &withholding, &inv.IVARate, &iva,
-		&net, &inv.InvoiceNumber, &inv.IssuedAt, &inv.PaidAt, &inv.Notes,
+		&net, &inv.InvoiceNumber, &inv.IssuedAt, &inv.DueAt, &inv.PaidAt, &inv.Notes,
 		&inv.CreatedAt, &inv.UpdatedAt,
 	)
 	inv.GrossAmountCents = money.Cents(gross)
@@ -62,7 +62,7 @@ func (r *FinanceRepository) ListInvoices(ctx context.Context, userID uuid.UUID,
 	query := `
 		SELECT id, user_id, workplace_id, period_start, period_end,
 			gross_amount_cents, withholding_rate, withholding_cents, iva_rate, iva_cents,
-			net_amount_cents, invoice_number, issued_at, paid_at, notes, created_at, updated_at
+			net_amount_cents, invoice_number, issued_at, due_at, paid_at, notes, created_at, updated_at
 		FROM invoices WHERE user_id = $1 AND period_start < $3 AND period_end > $2`
 
 	args := []interface{}{userID, start, end}
@@ -78,6 +78,42 @@ func (r *FinanceRepository) ListInvoices(ctx context.Context, userID uuid.UUID,
 	}
 	defer rows.Close()
 
+	return scanInvoices(rows)
+}
+
+func (r *FinanceRepository) ListUnpaidInvoices(ctx context.Context, userID uuid.UUID) ([]*finance.Invoice, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id, user_id, workplace_id, period_start, period_end,
+			gross_amount_cents, withholding_rate, withholding_cents, iva_rate, iva_cents,
+			net_amount_cents, invoice_number, issued_at, due_at, paid_at, notes, created_at, updated_at
+		FROM invoices WHERE user_id = $1 AND paid_at IS NULL
+		ORDER BY COALESCE(issued_at, period_end)
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanInvoices(rows)
+}
+
+func (r *FinanceRepository) ListPaidInvoices(ctx context.Context, userID uuid.UUID) ([]*finance.Invoice, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id, user_id, workplace_id, period_start, period_end,
+			gross_amount_cents, withholding_rate, withholding_cents, iva_rate, iva_cents,
+			net_amount_cents, invoice_number, issued_at, due_at, paid_at, notes, created_at, updated_at
+		FROM invoices WHERE user_id = $1 AND paid_at IS NOT NULL
+		ORDER BY paid_at
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanInvoices(rows)
+}
+
+func scanInvoices(rows pgx.Rows) ([]*finance.Invoice, error) {
 	var invoices []*finance.Invoice
 	for rows.Next() {
 		inv := &finance.Invoice{}
@@ -85,7 +121,7 @@ func (r *FinanceRepository) ListInvoices(ctx context.Context, userID uuid.UUID,
 		if err := rows.Scan(
 			&inv.ID, &inv.UserID, &inv.WorkplaceID, &inv.PeriodStart, &inv.PeriodEnd,
 			&gross, &inv.WithholdingRate, &withholding, &inv.IVARate, &iva,
-			&net, &inv.InvoiceNumber, &inv.IssuedAt, &inv.PaidAt, &inv.Notes,
+			&net, &inv.InvoiceNumber, &inv.IssuedAt, &inv.DueAt, &inv.PaidAt, &inv.Notes,
 			&inv.CreatedAt, &inv.UpdatedAt,
 		); err != nil {
 			return nil, err
@@ -104,47 +140,402 @@ func (r *FinanceRepository) UpdateInvoice(ctx context.Context, invoice *finance.
 		UPDATE invoices SET
 			period_start = $2, period_end = $3, gross_amount_cents = $4,
 			withholding_rate = $5, withholding_cents = $6, iva_rate = $7, iva_cents = $8,
-			net_amount_cents = $9, invoice_number = $10, issued_at = $11, paid_at = $12,
-			notes = $13, updated_at = $14
+			net_amount_cents = $9, invoice_number = $10, issued_at = $11, due_at = $12, paid_at = $13,
+			notes = $14, updated_at = $15
 		WHERE id = $1
 	`, invoice.ID, invoice.PeriodStart, invoice.PeriodEnd, int64(invoice.GrossAmountCents),
 		invoice.WithholdingRate, int64(invoice.WithholdingCents), invoice.IVARate, int64(invoice.IVACents),
-		int64(invoice.NetAmountCents), invoice.InvoiceNumber, invoice.IssuedAt, invoice.PaidAt,
+		int64(invoice.NetAmountCents), invoice.InvoiceNumber, invoice.IssuedAt, invoice.DueAt, invoice.PaidAt,
 		invoice.Notes, time.Now())
 	return err
 }
 
+func (r *FinanceRepository) RecordPayments(ctx context.Context, invoices []*finance.Invoice, shiftIDs []uuid.UUID) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, invoice := range invoices {
+		if _, err := tx.Exec(ctx, `
+			UPDATE invoices SET paid_at = $2, updated_at = $3 WHERE id = $1
+		`, invoice.ID, invoice.PaidAt, invoice.UpdatedAt); err != nil {
+			return err
+		}
+	}
+	if len(shiftIDs) > 0 {
+		if _, err := tx.Exec(ctx, `
+			UPDATE shift_earnings SET status = 'paid', updated_at = NOW()
+			WHERE shift_id = ANY($1) AND status != 'paid'
+		`, shiftIDs); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
 func (r *FinanceRepository) DeleteInvoice(ctx context.Context, id uuid.UUID) error {
 	_, err := r.db.Pool.Exec(ctx, `DELETE FROM invoices WHERE id = $1`, id)
 	return err
 }
 
+func (r *FinanceRepository) CreateExpense(ctx context.Context, expense *finance.Expense) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO expenses (id, user_id, workplace_id, date, category, description, supplier_nif,
+			amount_cents, iva_cents, in_efatura, attachment_ref, notes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`, expense.ID, expense.UserID, expense.WorkplaceID, expense.Date, expense.Category,
+		expense.Description, expense.SupplierNIF, int64(expense.AmountCents), int64(expense.IVACents),
+		expense.InEFatura, expense.AttachmentRef, expense.Notes, expense.CreatedAt, expense.UpdatedAt)
+	return err
+}
+
+func (r *FinanceRepository) GetExpenseByID(ctx context.Context, id uuid.UUID) (*finance.Expense, error) {
+	e := &finance.Expense{}
+	var amount, iva int64
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT id, user_id, workplace_id, date, category, description, supplier_nif,
+			amount_cents, iva_cents, in_efatura, attachment_ref, notes, created_at, updated_at
+		FROM expenses WHERE id = $1
+	`, id).Scan(
+		&e.ID, &e.UserID, &e.WorkplaceID, &e.Date, &e.Category, &e.Description, &e.SupplierNIF,
+		&amount, &iva, &e.InEFatura, &e.AttachmentRef, &e.Notes, &e.CreatedAt, &e.UpdatedAt,
+	)
+	e.AmountCents = money.Cents(amount)
+	e.IVACents = money.Cents(iva)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, finance.ErrExpenseNotFound
+	}
+	return e, err
+}
+
+func (r *FinanceRepository) ListExpenses(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]*finance.Expense, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id, user_id, workplace_id, date, category, description, supplier_nif,
+			amount_cents, iva_cents, in_efatura, attachment_ref, notes, created_at, updated_at
+		FROM expenses WHERE user_id = $1 AND date >= $2 AND date < $3
+		ORDER BY date DESC
+	`, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var expenses []*finance.Expense
+	for rows.Next() {
+		e := &finance.Expense{}
+		var amount, iva int64
+		if err := rows.Scan(
+			&e.ID, &e.UserID, &e.WorkplaceID, &e.Date, &e.Category, &e.Description, &e.SupplierNIF,
+			&amount, &iva, &e.InEFatura, &e.AttachmentRef, &e.Notes, &e.CreatedAt, &e.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		e.AmountCents = money.Cents(amount)
+		e.IVACents = money.Cents(iva)
+		expenses = append(expenses, e)
+	}
+	return expenses, nil
+}
+
+func (r *FinanceRepository) UpdateExpense(ctx context.Context, expense *finance.Expense) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE expenses SET
+			workplace_id = $2, date = $3, category = $4, description = $5, supplier_nif = $6,
+			amount_cents = $7, iva_cents = $8, in_efatura = $9, attachment_ref = $10,
+			notes = $11, updated_at = $12
+		WHERE id = $1
+	`, expense.ID, expense.WorkplaceID, expense.Date, expense.Category, expense.Description,
+		expense.SupplierNIF, int64(expense.AmountCents), int64(expense.IVACents), expense.InEFatura,
+		expense.AttachmentRef, expense.Notes, expense.UpdatedAt)
+	return err
+}
+
+func (r *FinanceRepository) DeleteExpense(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Pool.Exec(ctx, `DELETE FROM expenses WHERE id = $1`, id)
+	return err
+}
+
+func (r *FinanceRepository) CreateGoal(ctx context.Context, goal *finance.IncomeGoal) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO income_goals (id, user_id, workplace_id, year, month, basis, target_cents, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, goal.ID, goal.UserID, goal.WorkplaceID, goal.Year, goal.Month, goal.Basis,
+		int64(goal.TargetCents), goal.CreatedAt, goal.UpdatedAt)
+	return err
+}
+
+func (r *FinanceRepository) GetGoalByID(ctx context.Context, id uuid.UUID) (*finance.IncomeGoal, error) {
+	g := &finance.IncomeGoal{}
+	var target int64
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT id, user_id, workplace_id, year, month, basis, target_cents, created_at, updated_at
+		FROM income_goals WHERE id = $1
+	`, id).Scan(&g.ID, &g.UserID, &g.WorkplaceID, &g.Year, &g.Month, &g.Basis, &target, &g.CreatedAt, &g.UpdatedAt)
+	g.TargetCents = money.Cents(target)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, finance.ErrGoalNotFound
+	}
+	return g, err
+}
+
+func (r *FinanceRepository) ListGoals(ctx context.Context, userID uuid.UUID, year int) ([]*finance.IncomeGoal, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id, user_id, workplace_id, year, month, basis, target_cents, created_at, updated_at
+		FROM income_goals WHERE user_id = $1 AND year = $2
+		ORDER BY month NULLS FIRST, created_at
+	`, userID, year)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var goals []*finance.IncomeGoal
+	for rows.Next() {
+		g := &finance.IncomeGoal{}
+		var target int64
+		if err := rows.Scan(&g.ID, &g.UserID, &g.WorkplaceID, &g.Year, &g.Month, &g.Basis, &target, &g.CreatedAt, &g.UpdatedAt); err != nil {
+			return nil, err
+		}
+		g.TargetCents = money.Cents(target)
+		goals = append(goals, g)
+	}
+	return goals, nil
+}
+
+func (r *FinanceRepository) UpdateGoal(ctx context.Context, goal *finance.IncomeGoal) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE income_goals SET basis = $2, target_cents = $3, updated_at = $4 WHERE id = $1
+	`, goal.ID, goal.Basis, int64(goal.TargetCents), goal.UpdatedAt)
+	return err
+}
+
+func (r *FinanceRepository) DeleteGoal(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Pool.Exec(ctx, `DELETE FROM income_goals WHERE id = $1`, id)
+	return err
+}
+
+func (r *FinanceRepository) CreateScenario(ctx context.Context, scenario *finance.Scenario) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO tax_scenarios (id, user_id, year, name, overrides, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, scenario.ID, scenario.UserID, scenario.Year, scenario.Name, scenario.Overrides,
+		scenario.CreatedAt, scenario.UpdatedAt)
+	return err
+}
+
+func (r *FinanceRepository) GetScenarioByID(ctx context.Context, id uuid.UUID) (*finance.Scenario, error) {
+	sc := &finance.Scenario{}
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT id, user_id, year, name, overrides, created_at, updated_at
+		FROM tax_scenarios WHERE id = $1
+	`, id).Scan(&sc.ID, &sc.UserID, &sc.Year, &sc.Name, &sc.Overrides, &sc.CreatedAt, &sc.UpdatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, finance.ErrScenarioNotFound
+	}
+	return sc, err
+}
+
+func (r *FinanceRepository) ListScenarios(ctx context.Context, userID uuid.UUID, year int) ([]*finance.Scenario, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id, user_id, year, name, overrides, created_at, updated_at
+		FROM tax_scenarios WHERE user_id = $1 AND year = $2
+		ORDER BY created_at
+	`, userID, year)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scenarios []*finance.Scenario
+	for rows.Next() {
+		sc := &finance.Scenario{}
+		if err := rows.Scan(&sc.ID, &sc.UserID, &sc.Year, &sc.Name, &sc.Overrides, &sc.CreatedAt, &sc.UpdatedAt); err != nil {
+			return nil, err
+		}
+		scenarios = append(scenarios, sc)
+	}
+	return scenarios, nil
+}
+
+func (r *FinanceRepository) UpdateScenario(ctx context.Context, scenario *finance.Scenario) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE tax_scenarios SET name = $2, overrides = $3, updated_at = $4 WHERE id = $1
+	`, scenario.ID, scenario.Name, scenario.Overrides, scenario.UpdatedAt)
+	return err
+}
+
+func (r *FinanceRepository) DeleteScenario(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Pool.Exec(ctx, `DELETE FROM tax_scenarios WHERE id = $1`, id)
+	return err
+}
+
+func (r *FinanceRepository) CreatePaymentOnAccount(ctx context.Context, payment *finance.PaymentOnAccount) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO payments_on_account (id, user_id, fiscal_year, instalment, amount_cents, paid_on, reference, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, payment.ID, payment.UserID, payment.FiscalYear, payment.Instalment, int64(payment.AmountCents),
+		payment.PaidOn, payment.Reference, payment.CreatedAt)
+	return err
+}
+
+func (r *FinanceRepository) GetPaymentOnAccountByID(ctx context.Context, id uuid.UUID) (*finance.PaymentOnAccount, error) {
+	p := &finance.PaymentOnAccount{}
+	var amount int64
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT id, user_id, fiscal_year, instalment, amount_cents, paid_on, reference, created_at
+		FROM payments_on_account WHERE id = $1
+	`, id).Scan(&p.ID, &p.UserID, &p.FiscalYear, &p.Instalment, &amount, &p.PaidOn, &p.Reference, &p.CreatedAt)
+	p.AmountCents = money.Cents(amount)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, finance.ErrPaymentOnAccountNotFound
+	}
+	return p, err
+}
+
+func (r *FinanceRepository) ListPaymentsOnAccount(ctx context.Context, userID uuid.UUID, fiscalYear int) ([]*finance.PaymentOnAccount, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id, user_id, fiscal_year, instalment, amount_cents, paid_on, reference, created_at
+		FROM payments_on_account WHERE user_id = $1 AND fiscal_year = $2
+		ORDER BY instalment, paid_on
+	`, userID, fiscalYear)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []*finance.PaymentOnAccount
+	for rows.Next() {
+		p := &finance.PaymentOnAccount{}
+		var amount int64
+		if err := rows.Scan(&p.ID, &p.UserID, &p.FiscalYear, &p.Instalment, &amount, &p.PaidOn, &p.Reference, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		p.AmountCents = money.Cents(amount)
+		payments = append(payments, p)
+	}
+	return payments, nil
+}
+
+func (r *FinanceRepository) DeletePaymentOnAccount(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Pool.Exec(ctx, `DELETE FROM payments_on_account WHERE id = $1`, id)
+	return err
+}
+
+func (r *FinanceRepository) CreateSalaryRecord(ctx context.Context, record *finance.SalaryRecord) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO salary_records (id, user_id, employer, period, gross_cents, withholding_cents,
+			social_security_cents, notes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, record.ID, record.UserID, record.Employer, record.Period, int64(record.GrossCents),
+		int64(record.WithholdingCents), int64(record.SocialSecurityCents), record.Notes,
+		record.CreatedAt, record.UpdatedAt)
+	return err
+}
+
+func (r *FinanceRepository) GetSalaryRecordByID(ctx context.Context, id uuid.UUID) (*finance.SalaryRecord, error) {
+	rec := &finance.SalaryRecord{}
+	var gross, withholding, ss int64
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT id, user_id, employer, period, gross_cents, withholding_cents, social_security_cents,
+			notes, created_at, updated_at
+		FROM salary_records WHERE id = $1
+	`, id).Scan(&rec.ID, &rec.UserID, &rec.Employer, &rec.Period, &gross, &withholding, &ss,
+		&rec.Notes, &rec.CreatedAt, &rec.UpdatedAt)
+	rec.GrossCents = money.Cents(gross)
+	rec.WithholdingCents = money.Cents(withholding)
+	rec.SocialSecurityCents = money.Cents(ss)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, finance.ErrSalaryRecordNotFound
+	}
+	return rec, err
+}
+
+func (r *FinanceRepository) ListSalaryRecords(ctx context.Context, userID uuid.UUID, year int) ([]*finance.SalaryRecord, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id, user_id, employer, period, gross_cents, withholding_cents, social_security_cents,
+			notes, created_at, updated_at
+		FROM salary_records
+		WHERE user_id = $1 AND period >= make_date($2, 1, 1) AND period < make_date($2 + 1, 1, 1)
+		ORDER BY period, employer
+	`, userID, year)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*finance.SalaryRecord
+	for rows.Next() {
+		rec := &finance.SalaryRecord{}
+		var gross, withholding, ss int64
+		if err := rows.Scan(&rec.ID, &rec.UserID, &rec.Employer, &rec.Period, &gross, &withholding, &ss,
+			&rec.Notes, &rec.CreatedAt, &rec.UpdatedAt); err != nil {
+			return nil, err
+		}
+		rec.GrossCents = money.Cents(gross)
+		rec.WithholdingCents = money.Cents(withholding)
+		rec.SocialSecurityCents = money.Cents(ss)
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+func (r *FinanceRepository) UpdateSalaryRecord(ctx context.Context, record *finance.SalaryRecord) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE salary_records SET employer = $2, period = $3, gross_cents = $4, withholding_cents = $5,
+			social_security_cents = $6, notes = $7, updated_at = $8
+		WHERE id = $1
+	`, record.ID, record.Employer, record.Period, int64(record.GrossCents), int64(record.WithholdingCents),
+		int64(record.SocialSecurityCents), record.Notes, record.UpdatedAt)
+	return err
+}
+
+func (r *FinanceRepository) DeleteSalaryRecord(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Pool.Exec(ctx, `DELETE FROM salary_records WHERE id = $1`, id)
+	return err
+}
+
 func (r *FinanceRepository) GetEarningsSummary(ctx context.Context, userID uuid.UUID, start, end time.Time) (*finance.EarningsSummary, error) {
 	summary := &finance.EarningsSummary{
 		Period: start.Format("2006-01"),
 	}
 
 	// Get pro-rated earnings from shift_earnings segments that overlap the date range.
-	// Each segment's amount is scaled by the fraction of the segment that falls within [start, end).
-	var totalCents int64
+	// Each segment's amount is scaled by the fraction of the segment that falls within [start, end),
+	// and split like getEarningsByMonth into what is still projected and the rest.
+	var totalCents, projectedCents, actualCents int64
 	var shiftCount int
 	err := r.db.Pool.QueryRow(ctx, `
-		SELECT COALESCE(SUM(
-			ROUND(se.amount_cents *
-				EXTRACT(EPOCH FROM (LEAST(se.segment_end, $3) - GREATEST(se.segment_start, $2))) /
-				NULLIF(EXTRACT(EPOCH FROM (se.segment_end - se.segment_start)), 0)
-			)
-		), 0),
-		COUNT(DISTINCT s.id)
-		FROM shift_earnings se
-		JOIN shifts s ON se.shift_id = s.id
-		WHERE s.user_id = $1 AND se.segment_start < $3 AND se.segment_end > $2 AND s.status != 'cancelled'
-	`, userID, start, end).Scan(&totalCents, &shiftCount)
+		WITH segments AS (
+			SELECT s.id AS shift_id, se.status,
+				ROUND(se.amount_cents *
+					EXTRACT(EPOCH FROM (LEAST(se.segment_end, $3) - GREATEST(se.segment_start, $2))) /
+					NULLIF(EXTRACT(EPOCH FROM (se.segment_end - se.segment_start)), 0)
+				) AS amount
+			FROM shift_earnings se
+			JOIN shifts s ON se.shift_id = s.id
+			WHERE s.user_id = $1 AND se.segment_start < $3 AND se.segment_end > $2 AND s.status != 'cancelled'
+		)
+		SELECT COALESCE(SUM(amount), 0)::bigint,
+			COALESCE(SUM(amount) FILTER (WHERE status = 'projected'), 0)::bigint,
+			COALESCE(SUM(amount) FILTER (WHERE status != 'projected'), 0)::bigint,
+			COUNT(DISTINCT shift_id)
+		FROM segments
+	`, userID, start, end).Scan(&totalCents, &projectedCents, &actualCents, &shiftCount)
 	if err != nil {
 		return nil, err
 	}
 
 	summary.GrossEarnings = money.Cents(totalCents)
+	summary.ProjectedEarnings = money.Cents(projectedCents)
+	summary.ActualEarnings = money.Cents(actualCents)
 	summary.ShiftCount = shiftCount
 
 	// Get pro-rated per-workplace breakdown
@@ -194,46 +585,164 @@ func (r *FinanceRepository) GetEarningsSummary(ctx context.Context, userID uuid.
 	return summary, nil
 }
 
-func (r *FinanceRepository) GetMonthlyEarnings(ctx context.Context, userID uuid.UUID, year int) ([]finance.EarningsSummary, error) {
-	var summaries []finance.EarningsSummary
-	for month := 1; month <= 12; month++ {
-		start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-		end := start.AddDate(0, 1, 0).Add(-time.Nanosecond)
-		s, err := r.GetEarningsSummary(ctx, userID, start, end)
-		if err != nil {
+// ListEarningSegments returns the earning segments overlapping [start, end),
+// clipped to it with hours and amount pro-rated like GetEarningsSummary.
+func (r *FinanceRepository) ListEarningSegments(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]finance.EarningSegment, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT s.id, s.workplace_id, s.start_time, COALESCE(s.patients_seen, 0),
+			GREATEST(se.segment_start, $2), LEAST(se.segment_end, $3),
+			COALESCE(se.hours *
+				EXTRACT(EPOCH FROM (LEAST(se.segment_end, $3) - GREATEST(se.segment_start, $2))) /
+				NULLIF(EXTRACT(EPOCH FROM (se.segment_end - se.segment_start)), 0), 0),
+			COALESCE(ROUND(se.amount_cents *
+				EXTRACT(EPOCH FROM (LEAST(se.segment_end, $3) - GREATEST(se.segment_start, $2))) /
+				NULLIF(EXTRACT(EPOCH FROM (se.segment_end - se.segment_start)), 0)
+			), 0)::BIGINT
+		FROM shift_earnings se
+		JOIN shifts s ON se.shift_id = s.id
+		WHERE s.user_id = $1 AND se.segment_start < $3 AND se.segment_end > $2 AND s.status != 'cancelled'
+		ORDER BY se.segment_start
+	`, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var segments []finance.EarningSegment
+	for rows.Next() {
+		var seg finance.EarningSegment
+		var amountCents int64
+		if err := rows.Scan(&seg.ShiftID, &seg.WorkplaceID, &seg.ShiftStart, &seg.PatientsSeen,
+			&seg.Start, &seg.End, &seg.Hours, &amountCents); err != nil {
 			return nil, err
 		}
-		summaries = append(summaries, *s)
+		seg.Amount = money.Cents(amountCents)
+		segments = append(segments, seg)
 	}
-	return summaries, nil
+	return segments, rows.Err()
 }
 
-func (r *FinanceRepository) GetYearlyEarnings(ctx context.Context, userID uuid.UUID, year int) (*finance.EarningsSummary, error) {
-	start := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(year, 12, 31, 23, 59, 59, 0, time.UTC)
-	return r.GetEarningsSummary(ctx, userID, start, end)
+func (r *FinanceRepository) GetMonthlyEarnings(ctx context.Context, userID uuid.UUID, year int, loc *time.Location) ([]finance.EarningsSummary, error) {
+	return r.getEarningsByMonth(ctx, userID, year, loc)
 }
 
-func (r *FinanceRepository) GetProjections(ctx context.Context, userID uuid.UUID, year int) ([]finance.Projection, error) {
-	now := time.Now()
-	var projections []finance.Projection
+// getEarningsByMonth aggregates a whole year in one query. Month boundaries are
+// local midnights in loc; segments that cross a boundary are split pro-rata,
+// while patients and visits are attributed to the month the shift starts in.
+func (r *FinanceRepository) getEarningsByMonth(ctx context.Context, userID uuid.UUID, year int, loc *time.Location) ([]finance.EarningsSummary, error) {
+	yearStart := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
 
-	for month := 1; month <= 12; month++ {
-		start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-		end := start.AddDate(0, 1, 0).Add(-time.Nanosecond)
-		isActual := start.Before(now) && end.Before(now)
+	rows, err := r.db.Pool.Query(ctx, `
+		WITH months AS (
+			SELECT m AS month_start,
+				m AT TIME ZONE $3 AS lo,
+				(m + INTERVAL '1 month') AT TIME ZONE $3 AS hi
+			FROM generate_series($2::timestamp, $2::timestamp + INTERVAL '11 months', INTERVAL '1 month') m
+		),
+		segments AS (
+			SELECT mo.month_start, s.workplace_id, s.id AS shift_id, se.status,
+				ROUND(se.amount_cents *
+					EXTRACT(EPOCH FROM (LEAST(se.segment_end, mo.hi) - GREATEST(se.segment_start, mo.lo))) /
+					NULLIF(EXTRACT(EPOCH FROM (se.segment_end - se.segment_start)), 0)
+				) AS amount,
+				se.hours *
+					EXTRACT(EPOCH FROM (LEAST(se.segment_end, mo.hi) - GREATEST(se.segment_start, mo.lo))) /
+					NULLIF(EXTRACT(EPOCH FROM (se.segment_end - se.segment_start)), 0) AS hours
+			FROM shift_earnings se
+			JOIN shifts s ON se.shift_id = s.id
+			JOIN months mo ON se.segment_start < mo.hi AND se.segment_end > mo.lo
+			WHERE s.user_id = $1 AND s.status != 'cancelled'
+		),
+		earned AS (
+			SELECT month_start, workplace_id,
+				SUM(amount) AS gross,
+				SUM(amount) FILTER (WHERE status = 'projected') AS projected,
+				SUM(amount) FILTER (WHERE status != 'projected') AS actual,
+				COUNT(DISTINCT shift_id) AS shift_count,
+				SUM(hours) AS hours
+			FROM segments
+			GROUP BY month_start, workplace_id
+		),
+		activity AS (
+			SELECT date_trunc('month', s.start_time AT TIME ZONE $3) AS month_start, s.workplace_id,
+				SUM(COALESCE(s.patients_seen, 0)) AS patients,
+				SUM(COALESCE(s.outside_visits, 0)) AS visits
+			FROM shifts s
+			WHERE s.user_id = $1 AND s.status != 'cancelled'
+				AND s.start_time >= $2::timestamp AT TIME ZONE $3
+				AND s.start_time < ($2::timestamp + INTERVAL '1 year') AT TIME ZONE $3
+			GROUP BY 1, 2
+		)
+		SELECT COALESCE(e.month_start, a.month_start), w.id, w.name, COALESCE(w.color, '#3B82F6'),
+			COALESCE(e.gross, 0)::bigint, COALESCE(e.projected, 0)::bigint, COALESCE(e.actual, 0)::bigint,
+			COALESCE(e.shift_count, 0), COALESCE(e.hours, 0)::float8,
+			COALESCE(a.patients, 0), COALESCE(a.visits, 0)
+		FROM earned e
+		FULL OUTER JOIN activity a ON a.month_start = e.month_start AND a.workplace_id = e.workplace_id
+		JOIN workplaces w ON w.id = COALESCE(e.workplace_id, a.workplace_id)
+		ORDER BY 1, 5 DESC
+	`, userID, yearStart, loc.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-		summary, err := r.GetEarningsSummary(ctx, userID, start, end)
-		if err != nil {
+	summaries := make([]finance.EarningsSummary, 12)
+	for i := range summaries {
+		summaries[i].Period = yearStart.AddDate(0, i, 0).Format("2006-01")
+	}
+
+	for rows.Next() {
+		var month time.Time
+		var we finance.WorkplaceEarnings
+		var gross, projected, actual int64
+		if err := rows.Scan(&month, &we.WorkplaceID, &we.WorkplaceName, &we.Color,
+			&gross, &projected, &actual, &we.ShiftCount, &we.Hours, &we.PatientsSeen, &we.OutsideVisits); err != nil {
 			return nil, err
 		}
+		if month.Year() != year {
+			continue
+		}
+		we.Gross = money.Cents(gross)
+
+		s := &summaries[month.Month()-1]
+		s.GrossEarnings += we.Gross
+		s.ProjectedEarnings += money.Cents(projected)
+		s.ActualEarnings += money.Cents(actual)
+		s.ShiftCount += we.ShiftCount
+		s.ByWorkplace = append(s.ByWorkplace, we)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
+func (r *FinanceRepository) GetYearlyEarnings(ctx context.Context, userID uuid.UUID, year int, loc *time.Location) (*finance.EarningsSummary, error) {
+	start, end := finance.YearBounds(year, loc)
+	return r.GetEarningsSummary(ctx, userID, start, end)
+}
+
+func (r *FinanceRepository) GetProjections(ctx context.Context, userID uuid.UUID, year int, loc *time.Location) ([]finance.Projection, error) {
+	summaries, err := r.getEarningsByMonth(ctx, userID, year, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	projections := make([]finance.Projection, 0, len(summaries))
+	for i, summary := range summaries {
+		end := time.Date(year, time.Month(i+2), 1, 0, 0, 0, 0, loc)
 
+		// Projected is what is still only projected; actual is all the
+		// month's earnings, whatever their status.
 		projections = append(projections, finance.Projection{
-			Month:          start.Format("2006-01"),
+			Month:          summary.Period,
 			ProjectedGross: summary.ProjectedEarnings,
 			ActualGross:    summary.GrossEarnings,
 			Difference:     summary.GrossEarnings - summary.ProjectedEarnings,
-			IsActual:       isActual,
+			IsActual:       !end.After(now),
 		})
 	}
 
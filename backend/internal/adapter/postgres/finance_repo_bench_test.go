@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/testutil"
+)
+
+// seedEarnings inserts three years of overnight shifts (one per day, rotating
+// across three workplaces) with two earning segments each. Shifts start at
+// 20:00 and end at 08:00, so month boundaries regularly split a segment.
+func seedEarnings(ctx context.Context, b testing.TB, db *DB) uuid.UUID {
+	b.Helper()
+
+	userID := uuid.New()
+	wps := []string{uuid.NewString(), uuid.NewString(), uuid.NewString()}
+
+	if _, err := db.Pool.Exec(ctx, `
+		INSERT INTO users (id, email, password_hash, full_name) VALUES ($1, 'bench@example.com', 'x', 'Bench')
+	`, userID); err != nil {
+		b.Fatalf("seeding user: %v", err)
+	}
+	for i, id := range wps {
+		if _, err := db.Pool.Exec(ctx, `
+			INSERT INTO workplaces (id, user_id, name, pay_model, base_rate_cents) VALUES ($1, $2, $3, 'hourly', 3000)
+		`, id, userID, []string{"Hospital A", "Hospital B", "Clinic C"}[i]); err != nil {
+			b.Fatalf("seeding workplace: %v", err)
+		}
+	}
+
+	if _, err := db.Pool.Exec(ctx, `
+		INSERT INTO shifts (id, user_id, workplace_id, start_time, end_time, status, patients_seen, outside_visits)
+		SELECT uuid_generate_v4(), $1, ($2::uuid[])[1 + n % 3],
+			('2024-01-01'::timestamp + n * INTERVAL '1 day' + INTERVAL '20 hours') AT TIME ZONE 'Europe/Lisbon',
+			('2024-01-01'::timestamp + n * INTERVAL '1 day' + INTERVAL '32 hours') AT TIME ZONE 'Europe/Lisbon',
+			'completed', n % 25, n % 3
+		FROM generate_series(0, 3 * 365) n
+	`, userID, wps); err != nil {
+		b.Fatalf("seeding shifts: %v", err)
+	}
+
+	if _, err := db.Pool.Exec(ctx, `
+		INSERT INTO shift_earnings (shift_id, segment_start, segment_end, hours, rate_cents, amount_cents, status)
+		SELECT s.id, s.start_time, s.start_time + INTERVAL '4 hours', 4, 3000, 12000, 'confirmed'
+		FROM shifts s WHERE s.user_id = $1
+		UNION ALL
+		SELECT s.id, s.start_time + INTERVAL '4 hours', s.end_time, 8, 4500, 36000, 'projected'
+		FROM shifts s WHERE s.user_id = $1
+	`, userID); err != nil {
+		b.Fatalf("seeding earnings: %v", err)
+	}
+
+	if _, err := db.Pool.Exec(ctx, `ANALYZE`); err != nil {
+		b.Fatalf("analyzing: %v", err)
+	}
+	return userID
+}
+
+func setupEarningsBenchmark(b *testing.B) (context.Context, *FinanceRepository, uuid.UUID) {
+	b.Helper()
+	ctx := context.Background()
+
+	pool, cleanup, err := testutil.NewTestDB(ctx)
+	if err != nil {
+		b.Skipf("test database unavailable: %v", err)
+	}
+	b.Cleanup(cleanup)
+
+	db := &DB{Pool: pool}
+	return ctx, NewFinanceRepository(db), seedEarnings(ctx, b, db)
+}
+
+// BenchmarkMonthlyEarnings_PerMonth is the previous approach: one
+// GetEarningsSummary call (two queries) per month.
+func BenchmarkMonthlyEarnings_PerMonth(b *testing.B) {
+	ctx, repo, userID := setupEarningsBenchmark(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for month := 1; month <= 12; month++ {
+			start := time.Date(2025, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+			if _, err := repo.GetEarningsSummary(ctx, userID, start, start.AddDate(0, 1, 0)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkMonthlyEarnings_SinglePass(b *testing.B) {
+	ctx, repo, userID := setupEarningsBenchmark(b)
+	loc, err := time.LoadLocation("Europe/Lisbon")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.GetMonthlyEarnings(ctx, userID, 2025, loc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
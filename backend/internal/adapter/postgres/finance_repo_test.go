@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/joao-moreira/doctor-tracker/internal/domain/finance"
+	"github.com/joao-moreira/doctor-tracker/internal/testutil"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+// TestGetProjections checks the single-pass aggregation against the seeded
+// shifts: every day has a confirmed 20:00-00:00 segment (120 EUR) and a
+// projected 00:00-08:00 one (360 EUR), so each month holds as many of each as
+// it has days.
+func TestGetProjections(t *testing.T) {
+	ctx := context.Background()
+	pool, cleanup, err := testutil.NewTestDB(ctx)
+	if err != nil {
+		t.Skipf("test database unavailable: %v", err)
+	}
+	t.Cleanup(cleanup)
+	db := &DB{Pool: pool}
+	repo := NewFinanceRepository(db)
+	userID := seedEarnings(ctx, t, db)
+
+	loc, err := time.LoadLocation("Europe/Lisbon")
+	if err != nil {
+		t.Skipf("timezone not available: %v", err)
+	}
+	projections, err := repo.GetProjections(ctx, userID, 2025, loc)
+	if err != nil {
+		t.Fatalf("GetProjections returned unexpected error: %v", err)
+	}
+	if len(projections) != 12 {
+		t.Fatalf("expected 12 months, got %d", len(projections))
+	}
+
+	for i, p := range projections {
+		start, end := finance.MonthBounds(2025, time.Month(i+1), loc)
+		days := money.Cents(end.Sub(start).Round(24*time.Hour) / (24 * time.Hour))
+
+		// Projected is the part still projected; actual is every status
+		if p.ProjectedGross != days*36000 || p.ActualGross != days*48000 || p.Difference != days*12000 {
+			t.Errorf("%s: expected projected %d and actual %d, got %+v", p.Month, days*36000, days*48000, p)
+		}
+
+		// The same totals as the per-month summary
+		summary, err := repo.GetEarningsSummary(ctx, userID, start, end)
+		if err != nil {
+			t.Fatalf("GetEarningsSummary returned unexpected error: %v", err)
+		}
+		if summary.GrossEarnings != p.ActualGross {
+			t.Errorf("%s: expected actual gross %d to match the summary, got %d", p.Month, summary.GrossEarnings, p.ActualGross)
+		}
+	}
+}
+
+// TestGetEarningsSummary_SplitsByStatus checks that the summary endpoints get
+// the same projected and actual split as the monthly aggregation.
+func TestGetEarningsSummary_SplitsByStatus(t *testing.T) {
+	ctx := context.Background()
+	pool, cleanup, err := testutil.NewTestDB(ctx)
+	if err != nil {
+		t.Skipf("test database unavailable: %v", err)
+	}
+	t.Cleanup(cleanup)
+	db := &DB{Pool: pool}
+	repo := NewFinanceRepository(db)
+	userID := seedEarnings(ctx, t, db)
+
+	loc, err := time.LoadLocation("Europe/Lisbon")
+	if err != nil {
+		t.Skipf("timezone not available: %v", err)
+	}
+	monthly, err := repo.GetMonthlyEarnings(ctx, userID, 2025, loc)
+	if err != nil {
+		t.Fatalf("GetMonthlyEarnings returned unexpected error: %v", err)
+	}
+	for i, m := range monthly {
+		start, end := finance.MonthBounds(2025, time.Month(i+1), loc)
+		days := money.Cents(end.Sub(start).Round(24*time.Hour) / (24 * time.Hour))
+
+		summary, err := repo.GetEarningsSummary(ctx, userID, start, end)
+		if err != nil {
+			t.Fatalf("GetEarningsSummary returned unexpected error: %v", err)
+		}
+		// Each day has a confirmed 120 EUR segment and a projected 360 EUR one
+		if summary.ActualEarnings != days*12000 || summary.ProjectedEarnings != days*36000 {
+			t.Errorf("%s: expected actual %d and projected %d, got %+v", m.Period, days*12000, days*36000, summary)
+		}
+		if summary.ActualEarnings != m.ActualEarnings || summary.ProjectedEarnings != m.ProjectedEarnings {
+			t.Errorf("%s: expected the monthly split %d/%d, got %d/%d", m.Period,
+				m.ActualEarnings, m.ProjectedEarnings, summary.ActualEarnings, summary.ProjectedEarnings)
+		}
+	}
+
+	yearly, err := repo.GetYearlyEarnings(ctx, userID, 2025, loc)
+	if err != nil {
+		t.Fatalf("GetYearlyEarnings returned unexpected error: %v", err)
+	}
+	if yearly.ActualEarnings == 0 || yearly.ActualEarnings+yearly.ProjectedEarnings != yearly.GrossEarnings {
+		t.Errorf("expected the year's actual and projected earnings to add up to its gross, got %+v", yearly)
+	}
+}
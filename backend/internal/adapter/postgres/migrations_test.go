@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/testutil"
+)
+
+// TestMigration015_WithholdingRate checks that an explicit 0% survives the
+// move to a nullable rate and that the old 25% column default becomes the
+// year's default.
+func TestMigration015_WithholdingRate(t *testing.T) {
+	ctx := context.Background()
+	pool, cleanup, err := testutil.NewEmptyTestDB(ctx)
+	if err != nil {
+		t.Skipf("test database unavailable: %v", err)
+	}
+	t.Cleanup(cleanup)
+
+	if err := testutil.Migrate(ctx, pool, 1, 14); err != nil {
+		t.Fatal(err)
+	}
+	userID := uuid.New()
+	if _, err := pool.Exec(ctx, `
+		INSERT INTO users (id, email, password_hash, full_name) VALUES ($1, 'migration@example.com', 'x', 'Migration')
+	`, userID); err != nil {
+		t.Fatalf("seeding user: %v", err)
+	}
+	rates := map[string]float64{"No withholding": 0, "Old default": 0.25, "Agreed": 0.2}
+	ids := map[string]uuid.UUID{}
+	for name, rate := range rates {
+		ids[name] = uuid.New()
+		if _, err := pool.Exec(ctx, `
+			INSERT INTO workplaces (id, user_id, name, pay_model, base_rate_cents, withholding_rate) VALUES ($1, $2, $3, 'hourly', 3000, $4)
+		`, ids[name], userID, name, rate); err != nil {
+			t.Fatalf("seeding workplace: %v", err)
+		}
+	}
+
+	if err := testutil.Migrate(ctx, pool, 15, 15); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]*float64{"No withholding": ptrTo(0.0), "Old default": nil, "Agreed": ptrTo(0.2)}
+	for name, id := range ids {
+		var got *float64
+		if err := pool.QueryRow(ctx, `SELECT withholding_rate FROM workplaces WHERE id = $1`, id).Scan(&got); err != nil {
+			t.Fatal(err)
+		}
+		if (got == nil) != (want[name] == nil) || (got != nil && *got != *want[name]) {
+			t.Errorf("%s: expected rate %v, got %v", name, want[name], got)
+		}
+	}
+
+	// New workplaces start on the year's default
+	var rate *float64
+	if err := pool.QueryRow(ctx, `
+		INSERT INTO workplaces (user_id, name, pay_model, base_rate_cents) VALUES ($1, 'New', 'hourly', 3000) RETURNING withholding_rate
+	`, userID).Scan(&rate); err != nil {
+		t.Fatal(err)
+	}
+	if rate != nil {
+		t.Errorf("expected a new workplace to have no rate, got %v", *rate)
+	}
+}
+
+func ptrTo[T any](v T) *T { return &v }
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -94,6 +95,98 @@ func (r *ScheduleRepository) ListShifts(ctx context.Context, filter schedule.Shi
 	return shifts, nil
 }
 
+func (r *ScheduleRepository) ListUnreviewedShifts(ctx context.Context, userID *uuid.UUID, endedBefore time.Time) ([]*schedule.Shift, error) {
+	query := `
+		SELECT id, user_id, workplace_id, start_time, end_time, timezone, status,
+			recurrence_rule_id, original_start_time, is_recurrence_exception,
+			gcal_event_id, gcal_etag, last_synced_at, title, notes, patients_seen, outside_visits,
+			created_at, updated_at
+		FROM shifts WHERE status IN ('scheduled', 'confirmed') AND end_time < $1`
+
+	args := []interface{}{endedBefore}
+	if userID != nil {
+		query += ` AND user_id = $2`
+		args = append(args, *userID)
+	}
+	query += ` ORDER BY user_id, start_time`
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shifts []*schedule.Shift
+	for rows.Next() {
+		shift := &schedule.Shift{}
+		if err := rows.Scan(
+			&shift.ID, &shift.UserID, &shift.WorkplaceID, &shift.StartTime, &shift.EndTime,
+			&shift.Timezone, &shift.Status, &shift.RecurrenceRuleID, &shift.OriginalStartTime,
+			&shift.IsRecurrenceException, &shift.GCalEventID, &shift.GCalEtag, &shift.LastSyncedAt,
+			&shift.Title, &shift.Notes, &shift.PatientsSeen, &shift.OutsideVisits,
+			&shift.CreatedAt, &shift.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		shifts = append(shifts, shift)
+	}
+	return shifts, nil
+}
+
+func (r *ScheduleRepository) SaveReviewRun(ctx context.Context, ranAt time.Time, succeeded bool, reminders []schedule.ReviewReminder) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO review_runs (ran_at, succeeded) VALUES ($1, $2)
+	`, ranAt, succeeded); err != nil {
+		return err
+	}
+	if succeeded {
+		if _, err := tx.Exec(ctx, `DELETE FROM review_reminders`); err != nil {
+			return err
+		}
+		for _, rem := range reminders {
+			if _, err := tx.Exec(ctx, `
+				INSERT INTO review_reminders (user_id, ran_at, pending, oldest_start) VALUES ($1, $2, $3, $4)
+			`, rem.UserID, ranAt, rem.Pending, rem.OldestStart); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *ScheduleRepository) GetLastReviewRun(ctx context.Context, userID uuid.UUID) (*schedule.ReviewRun, error) {
+	run := &schedule.ReviewRun{}
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT ran_at, succeeded FROM review_runs ORDER BY ran_at DESC LIMIT 1
+	`).Scan(&run.RanAt, &run.Succeeded)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var oldest time.Time
+	err = r.db.Pool.QueryRow(ctx, `
+		SELECT pending, oldest_start FROM review_reminders WHERE user_id = $1
+	`, userID).Scan(&run.Pending, &oldest)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return run, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	run.OldestStart = &oldest
+	return run, nil
+}
+
 func (r *ScheduleRepository) UpdateShift(ctx context.Context, shift *schedule.Shift) error {
 	_, err := r.db.Pool.Exec(ctx, `
 		UPDATE shifts SET
@@ -191,11 +284,13 @@ func (r *ScheduleRepository) CreateShiftEarnings(ctx context.Context, earnings [
 
 	for _, e := range earnings {
 		_, err := tx.Exec(ctx, `
-			INSERT INTO shift_earnings (id, shift_id, pricing_rule_id, segment_start, segment_end,
-				hours, rate_cents, amount_cents, status, notes, created_at, updated_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-		`, e.ID, e.ShiftID, e.PricingRuleID, e.SegmentStart, e.SegmentEnd,
-			e.Hours, int64(e.RateCents), int64(e.AmountCents), e.Status, e.Notes,
+			INSERT INTO shift_earnings (id, shift_id, pricing_rule_id, kind, segment_start, segment_end,
+				hours, rate_cents, amount_cents, status, rule_name, rate_multiplier, quantity,
+				notes, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		`, e.ID, e.ShiftID, e.PricingRuleID, e.Kind, e.SegmentStart, e.SegmentEnd,
+			e.Hours, int64(e.RateCents), int64(e.AmountCents), e.Status,
+			e.RuleName, e.RateMultiplier, e.Quantity, e.Notes,
 			e.CreatedAt, e.UpdatedAt)
 		if err != nil {
 			return err
@@ -207,9 +302,11 @@ func (r *ScheduleRepository) CreateShiftEarnings(ctx context.Context, earnings [
 
 func (r *ScheduleRepository) GetShiftEarnings(ctx context.Context, shiftID uuid.UUID) ([]*schedule.ShiftEarning, error) {
 	rows, err := r.db.Pool.Query(ctx, `
-		SELECT id, shift_id, pricing_rule_id, segment_start, segment_end,
-			hours, rate_cents, amount_cents, status, notes, created_at, updated_at
-		FROM shift_earnings WHERE shift_id = $1 ORDER BY segment_start
+		SELECT id, shift_id, pricing_rule_id, kind, segment_start, segment_end,
+			hours, rate_cents, amount_cents, status, rule_name, rate_multiplier, quantity,
+			notes, created_at, updated_at
+		FROM shift_earnings WHERE shift_id = $1
+		ORDER BY kind != 'time', segment_start, kind
 	`, shiftID)
 	if err != nil {
 		return nil, err
@@ -221,9 +318,9 @@ func (r *ScheduleRepository) GetShiftEarnings(ctx context.Context, shiftID uuid.
 		e := &schedule.ShiftEarning{}
 		var rateCents, amountCents int64
 		if err := rows.Scan(
-			&e.ID, &e.ShiftID, &e.PricingRuleID, &e.SegmentStart, &e.SegmentEnd,
-			&e.Hours, &rateCents, &amountCents, &e.Status, &e.Notes,
-			&e.CreatedAt, &e.UpdatedAt,
+			&e.ID, &e.ShiftID, &e.PricingRuleID, &e.Kind, &e.SegmentStart, &e.SegmentEnd,
+			&e.Hours, &rateCents, &amountCents, &e.Status, &e.RuleName, &e.RateMultiplier, &e.Quantity,
+			&e.Notes, &e.CreatedAt, &e.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -239,9 +336,10 @@ func (r *ScheduleRepository) DeleteShiftEarnings(ctx context.Context, shiftID uu
 	return err
 }
 
-func (r *ScheduleRepository) UpdateEarningStatus(ctx context.Context, shiftID uuid.UUID, status schedule.EarningStatus) error {
+func (r *ScheduleRepository) ConfirmProjectedEarnings(ctx context.Context, shiftID uuid.UUID) error {
 	_, err := r.db.Pool.Exec(ctx, `
-		UPDATE shift_earnings SET status = $2, updated_at = NOW() WHERE shift_id = $1
-	`, shiftID, status)
+		UPDATE shift_earnings SET status = 'confirmed', updated_at = NOW()
+		WHERE shift_id = $1 AND status = 'projected'
+	`, shiftID)
 	return err
 }
@@ -22,12 +22,13 @@ func (r *WorkplaceRepository) CreateWorkplace(ctx context.Context, w *workplace.
 	_, err := r.db.Pool.Exec(ctx, `
 		INSERT INTO workplaces (id, user_id, name, address, color, pay_model, base_rate_cents, currency,
 			monthly_expected_hours, has_consultation_pay, has_outside_visit_pay, withholding_rate,
-			contact_name, contact_phone, contact_email, notes, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+			private_client, nif, payment_terms_days, commute_minutes, contact_name, contact_phone, contact_email, notes, is_active, created_at, updated_at,
+			tax_country)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
 	`, w.ID, w.UserID, w.Name, w.Address, w.Color, w.PayModel, int64(w.BaseRateCents), w.Currency,
 		w.MonthlyExpectedHours, w.HasConsultationPay, w.HasOutsideVisitPay, w.WithholdingRate,
-		w.ContactName, w.ContactPhone, w.ContactEmail, w.Notes,
-		w.IsActive, w.CreatedAt, w.UpdatedAt)
+		w.PrivateClient, w.NIF, w.PaymentTermsDays, w.CommuteMinutes, w.ContactName, w.ContactPhone, w.ContactEmail, w.Notes,
+		w.IsActive, w.CreatedAt, w.UpdatedAt, w.TaxCountry)
 	return err
 }
 
@@ -37,14 +38,14 @@ func (r *WorkplaceRepository) GetWorkplaceByID(ctx context.Context, id uuid.UUID
 	err := r.db.Pool.QueryRow(ctx, `
 		SELECT id, user_id, name, address, color, pay_model, base_rate_cents, currency,
 			monthly_expected_hours, has_consultation_pay, has_outside_visit_pay, withholding_rate,
-			contact_name, contact_phone, contact_email, notes,
-			is_active, created_at, updated_at
+			private_client, nif, payment_terms_days, commute_minutes, contact_name, contact_phone, contact_email, notes,
+			is_active, created_at, updated_at, tax_country
 		FROM workplaces WHERE id = $1
 	`, id).Scan(
 		&w.ID, &w.UserID, &w.Name, &w.Address, &w.Color, &w.PayModel, &baseRateCents, &w.Currency,
 		&w.MonthlyExpectedHours, &w.HasConsultationPay, &w.HasOutsideVisitPay, &w.WithholdingRate,
-		&w.ContactName, &w.ContactPhone, &w.ContactEmail, &w.Notes,
-		&w.IsActive, &w.CreatedAt, &w.UpdatedAt,
+		&w.PrivateClient, &w.NIF, &w.PaymentTermsDays, &w.CommuteMinutes, &w.ContactName, &w.ContactPhone, &w.ContactEmail, &w.Notes,
+		&w.IsActive, &w.CreatedAt, &w.UpdatedAt, &w.TaxCountry,
 	)
 	w.BaseRateCents = money.Cents(baseRateCents)
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -57,8 +58,8 @@ func (r *WorkplaceRepository) ListWorkplacesByUser(ctx context.Context, userID u
 	query := `
 		SELECT id, user_id, name, address, color, pay_model, base_rate_cents, currency,
 			monthly_expected_hours, has_consultation_pay, has_outside_visit_pay, withholding_rate,
-			contact_name, contact_phone, contact_email, notes,
-			is_active, created_at, updated_at
+			private_client, nif, payment_terms_days, commute_minutes, contact_name, contact_phone, contact_email, notes,
+			is_active, created_at, updated_at, tax_country
 		FROM workplaces WHERE user_id = $1`
 	if activeOnly {
 		query += ` AND is_active = true`
@@ -78,8 +79,8 @@ func (r *WorkplaceRepository) ListWorkplacesByUser(ctx context.Context, userID u
 		if err := rows.Scan(
 			&w.ID, &w.UserID, &w.Name, &w.Address, &w.Color, &w.PayModel, &baseRateCents, &w.Currency,
 			&w.MonthlyExpectedHours, &w.HasConsultationPay, &w.HasOutsideVisitPay, &w.WithholdingRate,
-			&w.ContactName, &w.ContactPhone, &w.ContactEmail, &w.Notes,
-			&w.IsActive, &w.CreatedAt, &w.UpdatedAt,
+			&w.PrivateClient, &w.NIF, &w.PaymentTermsDays, &w.CommuteMinutes, &w.ContactName, &w.ContactPhone, &w.ContactEmail, &w.Notes,
+			&w.IsActive, &w.CreatedAt, &w.UpdatedAt, &w.TaxCountry,
 		); err != nil {
 			return nil, err
 		}
@@ -94,12 +95,14 @@ func (r *WorkplaceRepository) UpdateWorkplace(ctx context.Context, w *workplace.
 		UPDATE workplaces SET
 			name = $2, address = $3, color = $4, pay_model = $5, base_rate_cents = $6,
 			monthly_expected_hours = $7, has_consultation_pay = $8, has_outside_visit_pay = $9,
-			withholding_rate = $10,
-			contact_name = $11, contact_phone = $12, contact_email = $13, notes = $14, updated_at = $15
+			withholding_rate = $10, nif = $11, payment_terms_days = $12, commute_minutes = $13,
+			contact_name = $14, contact_phone = $15, contact_email = $16, notes = $17, updated_at = $18,
+			private_client = $19, tax_country = $20
 		WHERE id = $1
 	`, w.ID, w.Name, w.Address, w.Color, w.PayModel, int64(w.BaseRateCents),
 		w.MonthlyExpectedHours, w.HasConsultationPay, w.HasOutsideVisitPay, w.WithholdingRate,
-		w.ContactName, w.ContactPhone, w.ContactEmail, w.Notes, w.UpdatedAt)
+		w.NIF, w.PaymentTermsDays, w.CommuteMinutes, w.ContactName, w.ContactPhone, w.ContactEmail, w.Notes, w.UpdatedAt,
+		w.PrivateClient, w.TaxCountry)
 	return err
 }
 
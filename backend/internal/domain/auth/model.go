@@ -17,6 +17,25 @@ type User struct {
 	TaxRegime    string `json:"tax_regime"`    // "simplified" or "organized"
 	ActivityCode *string `json:"activity_code,omitempty"` // CAE/CIRS article 151
 	IRSCategory  string `json:"irs_category"`  // Default "B" for independent
+	TaxCountry   string `json:"tax_country"`   // tax residence, whose engine estimates the year's tax
+
+	// Special regimes, each lasting ten years; they cannot be combined
+	BirthYear         *int `json:"birth_year,omitempty"`           // IRS Jovem is limited to taxpayers up to 35
+	IRSJovemFirstYear *int `json:"irs_jovem_first_year,omitempty"` // first year with income after finishing studies
+	IFICIStartYear    *int `json:"ifici_start_year,omitempty"`     // year the IFICI status was granted
+
+	// Social Security: contributions are exempt for the first twelve months
+	// of activity, and the worker may move the contribution base by up to
+	// 25% either way, in steps of 5%
+	ActivityStartDate *time.Time `json:"activity_start_date,omitempty"`
+	SSBaseAdjustment  int        `json:"ss_base_adjustment"` // percent
+
+	// Dispensed from IRS withholding (art. 101-B CIRS) while the year's
+	// income stays under the threshold
+	WithholdingDispensed bool `json:"withholding_dispensed"`
+
+	// IANA timezone used to draw day, month and year boundaries
+	Timezone string `json:"timezone"`
 
 	// Google Calendar OAuth (stored encrypted, never returned in JSON)
 	GCalAccessToken  *string    `json:"-"`
@@ -28,6 +47,48 @@ type User struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// MaxSSBaseAdjustment is how far, in percent, the Social Security base may be
+// moved from the declared relevant income; it moves in steps of
+// SSBaseAdjustmentStep.
+const (
+	MaxSSBaseAdjustment  = 25
+	SSBaseAdjustmentStep = 5
+)
+
+// Tax regimes for Category B income.
+const (
+	TaxRegimeSimplified = "simplified"
+	TaxRegimeOrganized  = "organized" // contabilidade organizada
+)
+
+// Tax residences with a tax engine, as ISO 3166 country codes.
+const (
+	TaxCountryPortugal = "PT"
+	TaxCountrySpain    = "ES"
+)
+
+// DefaultTimezone is used for users who have not chosen a timezone.
+const DefaultTimezone = "Europe/Lisbon"
+
+// DefaultLocation loads DefaultTimezone, or UTC if the tz database is missing.
+func DefaultLocation() *time.Location {
+	loc, err := time.LoadLocation(DefaultTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Location returns the user's timezone, falling back to DefaultTimezone.
+func (u *User) Location() *time.Location {
+	if u.Timezone != "" {
+		if loc, err := time.LoadLocation(u.Timezone); err == nil {
+			return loc
+		}
+	}
+	return DefaultLocation()
+}
+
 type TokenPair struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
@@ -55,3 +116,21 @@ type LoginInput struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
 }
+
+type UpdateProfileInput struct {
+	FullName  *string `json:"full_name"`
+	NIF       *string `json:"nif" validate:"omitempty,len=9,numeric"`
+	TaxRegime *string `json:"tax_regime" validate:"omitempty,oneof=simplified organized"`
+	TaxCountry *string `json:"tax_country" validate:"omitempty,oneof=PT ES"`
+	Timezone  *string `json:"timezone"`
+
+	// Zero clears the setting
+	BirthYear         *int `json:"birth_year"`
+	IRSJovemFirstYear *int `json:"irs_jovem_first_year"`
+	IFICIStartYear    *int `json:"ifici_start_year"`
+
+	ActivityStartDate *time.Time `json:"activity_start_date"`
+	SSBaseAdjustment  *int       `json:"ss_base_adjustment" validate:"omitempty,min=-25,max=25"`
+
+	WithholdingDispensed *bool `json:"withholding_dispensed"`
+}
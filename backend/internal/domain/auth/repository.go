@@ -16,4 +16,8 @@ type Repository interface {
 	GetRefreshTokenByHash(ctx context.Context, hash string) (*RefreshToken, error)
 	RevokeRefreshToken(ctx context.Context, id uuid.UUID) error
 	RevokeAllUserRefreshTokens(ctx context.Context, userID uuid.UUID) error
+
+	// Calendar feed tokens, stored hashed; a nil hash revokes the user's token.
+	SetCalendarFeedTokenHash(ctx context.Context, userID uuid.UUID, hash *string) error
+	GetUserIDByCalendarFeedTokenHash(ctx context.Context, hash string) (uuid.UUID, error)
 }
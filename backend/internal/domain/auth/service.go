@@ -2,7 +2,9 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"errors"
 	"time"
@@ -18,6 +20,9 @@ var (
 	ErrUserAlreadyExists  = errors.New("user with this email already exists")
 	ErrInvalidToken       = errors.New("invalid or expired token")
 	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidTimezone    = errors.New("invalid timezone")
+	ErrInvalidTaxRegime   = errors.New("invalid tax regime")
+	ErrInvalidTaxProfile  = errors.New("invalid tax profile")
 )
 
 type Service struct {
@@ -45,8 +50,10 @@ func (s *Service) Register(ctx context.Context, input RegisterInput) (*User, *To
 		Email:        input.Email,
 		PasswordHash: string(hash),
 		FullName:     input.FullName,
-		TaxRegime:    "simplified",
+		TaxRegime:    TaxRegimeSimplified,
 		IRSCategory:  "B",
+		TaxCountry:   TaxCountryPortugal,
+		Timezone:     DefaultTimezone,
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 	}
@@ -118,6 +125,79 @@ func (s *Service) GetUserByID(ctx context.Context, id uuid.UUID) (*User, error)
 	return s.repo.GetUserByID(ctx, id)
 }
 
+func (s *Service) UpdateProfile(ctx context.Context, id uuid.UUID, input UpdateProfileInput) (*User, error) {
+	user, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.FullName != nil {
+		user.FullName = *input.FullName
+	}
+	if input.NIF != nil {
+		user.NIF = input.NIF
+	}
+	if input.TaxRegime != nil {
+		if *input.TaxRegime != TaxRegimeSimplified && *input.TaxRegime != TaxRegimeOrganized {
+			return nil, ErrInvalidTaxRegime
+		}
+		user.TaxRegime = *input.TaxRegime
+	}
+	if input.TaxCountry != nil {
+		if *input.TaxCountry != TaxCountryPortugal && *input.TaxCountry != TaxCountrySpain {
+			return nil, ErrInvalidTaxProfile
+		}
+		user.TaxCountry = *input.TaxCountry
+	}
+	if input.Timezone != nil {
+		if _, err := time.LoadLocation(*input.Timezone); err != nil || *input.Timezone == "" {
+			return nil, ErrInvalidTimezone
+		}
+		user.Timezone = *input.Timezone
+	}
+	for _, field := range []struct {
+		input *int
+		dest  **int
+	}{
+		{input.BirthYear, &user.BirthYear},
+		{input.IRSJovemFirstYear, &user.IRSJovemFirstYear},
+		{input.IFICIStartYear, &user.IFICIStartYear},
+	} {
+		if field.input == nil {
+			continue
+		}
+		switch year := *field.input; {
+		case year == 0:
+			*field.dest = nil
+		case year < 1900 || year > 2100:
+			return nil, ErrInvalidTaxProfile
+		default:
+			*field.dest = &year
+		}
+	}
+	if user.IRSJovemFirstYear != nil && user.IFICIStartYear != nil {
+		return nil, ErrInvalidTaxProfile
+	}
+	if input.ActivityStartDate != nil {
+		user.ActivityStartDate = input.ActivityStartDate
+	}
+	if adj := input.SSBaseAdjustment; adj != nil {
+		if *adj < -MaxSSBaseAdjustment || *adj > MaxSSBaseAdjustment || *adj%SSBaseAdjustmentStep != 0 {
+			return nil, ErrInvalidTaxProfile
+		}
+		user.SSBaseAdjustment = *adj
+	}
+	if input.WithholdingDispensed != nil {
+		user.WithholdingDispensed = *input.WithholdingDispensed
+	}
+	user.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
 func (s *Service) ValidateAccessToken(tokenStr string) (uuid.UUID, error) {
 	token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -184,6 +264,39 @@ func (s *Service) generateTokens(ctx context.Context, user *User) (*TokenPair, e
 	}, nil
 }
 
+// CreateCalendarFeedToken issues the token that lets calendar applications
+// subscribe to the user's feeds, replacing any earlier one. Only its hash is
+// stored, so the token is returned this once.
+func (s *Service) CreateCalendarFeedToken(ctx context.Context, userID uuid.UUID) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	hash := hashToken(token)
+	if err := s.repo.SetCalendarFeedTokenHash(ctx, userID, &hash); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// RevokeCalendarFeedToken stops the user's calendar feeds from being served.
+func (s *Service) RevokeCalendarFeedToken(ctx context.Context, userID uuid.UUID) error {
+	return s.repo.SetCalendarFeedTokenHash(ctx, userID, nil)
+}
+
+// ValidateCalendarFeedToken returns the user a calendar feed token belongs to.
+func (s *Service) ValidateCalendarFeedToken(ctx context.Context, token string) (uuid.UUID, error) {
+	if token == "" {
+		return uuid.Nil, ErrInvalidToken
+	}
+	userID, err := s.repo.GetUserIDByCalendarFeedTokenHash(ctx, hashToken(token))
+	if err != nil {
+		return uuid.Nil, ErrInvalidToken
+	}
+	return userID, nil
+}
+
 func hashToken(token string) string {
 	h := sha256.Sum256([]byte(token))
 	return hex.EncodeToString(h[:])
@@ -20,6 +20,7 @@ type mockAuthRepo struct {
 	users         map[uuid.UUID]*User
 	usersByEmail  map[string]*User
 	refreshTokens map[string]*RefreshToken // keyed by token hash
+	feedTokens    map[uuid.UUID]string     // calendar feed token hash per user
 }
 
 func newMockAuthRepo() *mockAuthRepo {
@@ -27,6 +28,7 @@ func newMockAuthRepo() *mockAuthRepo {
 		users:         make(map[uuid.UUID]*User),
 		usersByEmail:  make(map[string]*User),
 		refreshTokens: make(map[string]*RefreshToken),
+		feedTokens:    make(map[uuid.UUID]string),
 	}
 }
 
@@ -111,6 +113,28 @@ func (m *mockAuthRepo) RevokeAllUserRefreshTokens(_ context.Context, userID uuid
 	return nil
 }
 
+func (m *mockAuthRepo) SetCalendarFeedTokenHash(_ context.Context, userID uuid.UUID, hash *string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if hash == nil {
+		delete(m.feedTokens, userID)
+	} else {
+		m.feedTokens[userID] = *hash
+	}
+	return nil
+}
+
+func (m *mockAuthRepo) GetUserIDByCalendarFeedTokenHash(_ context.Context, hash string) (uuid.UUID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for userID, h := range m.feedTokens {
+		if h == hash {
+			return userID, nil
+		}
+	}
+	return uuid.Nil, ErrInvalidToken
+}
+
 // ---------------------------------------------------------------------------
 // Helper to build a test service
 // ---------------------------------------------------------------------------
@@ -323,3 +347,162 @@ func TestValidateAccessToken_WrongSecret(t *testing.T) {
 		t.Fatal("expected error when validating token with wrong secret, got nil")
 	}
 }
+
+func TestUpdateProfile_Timezone(t *testing.T) {
+	svc, _ := newTestService()
+	ctx := context.Background()
+
+	user, _, err := svc.Register(ctx, RegisterInput{
+		Email:    "tz@example.com",
+		Password: "secure123",
+		FullName: "Dr. Azores",
+	})
+	if err != nil {
+		t.Fatalf("Register returned unexpected error: %v", err)
+	}
+	if user.Timezone != DefaultTimezone {
+		t.Errorf("expected default timezone %q, got %q", DefaultTimezone, user.Timezone)
+	}
+
+	tz := "Atlantic/Azores"
+	updated, err := svc.UpdateProfile(ctx, user.ID, UpdateProfileInput{Timezone: &tz})
+	if err != nil {
+		t.Fatalf("UpdateProfile returned unexpected error: %v", err)
+	}
+	if updated.Timezone != tz {
+		t.Errorf("expected timezone %q, got %q", tz, updated.Timezone)
+	}
+
+	bad := "Mars/Olympus"
+	if _, err := svc.UpdateProfile(ctx, user.ID, UpdateProfileInput{Timezone: &bad}); !errors.Is(err, ErrInvalidTimezone) {
+		t.Errorf("expected ErrInvalidTimezone, got %v", err)
+	}
+}
+
+func TestUpdateProfile_TaxRegime(t *testing.T) {
+	svc, _ := newTestService()
+	ctx := context.Background()
+
+	user, _, err := svc.Register(ctx, RegisterInput{
+		Email:    "regime@example.com",
+		Password: "secure123",
+		FullName: "Dr. Organized",
+	})
+	if err != nil {
+		t.Fatalf("Register returned unexpected error: %v", err)
+	}
+
+	regime := TaxRegimeOrganized
+	updated, err := svc.UpdateProfile(ctx, user.ID, UpdateProfileInput{TaxRegime: &regime})
+	if err != nil {
+		t.Fatalf("UpdateProfile returned unexpected error: %v", err)
+	}
+	if updated.TaxRegime != TaxRegimeOrganized {
+		t.Errorf("expected tax regime %q, got %q", TaxRegimeOrganized, updated.TaxRegime)
+	}
+
+	bad := "flat"
+	if _, err := svc.UpdateProfile(ctx, user.ID, UpdateProfileInput{TaxRegime: &bad}); !errors.Is(err, ErrInvalidTaxRegime) {
+		t.Errorf("expected ErrInvalidTaxRegime, got %v", err)
+	}
+}
+
+func TestUpdateProfile_TaxCountry(t *testing.T) {
+	svc, _ := newTestService()
+	ctx := context.Background()
+
+	user, _, err := svc.Register(ctx, RegisterInput{
+		Email:    "country@example.com",
+		Password: "secure123",
+		FullName: "Dr. Abroad",
+	})
+	if err != nil {
+		t.Fatalf("Register returned unexpected error: %v", err)
+	}
+	if user.TaxCountry != TaxCountryPortugal {
+		t.Errorf("expected new users resident in Portugal, got %q", user.TaxCountry)
+	}
+
+	spain := TaxCountrySpain
+	updated, err := svc.UpdateProfile(ctx, user.ID, UpdateProfileInput{TaxCountry: &spain})
+	if err != nil {
+		t.Fatalf("UpdateProfile returned unexpected error: %v", err)
+	}
+	if updated.TaxCountry != TaxCountrySpain {
+		t.Errorf("expected tax country %q, got %q", TaxCountrySpain, updated.TaxCountry)
+	}
+
+	france := "FR"
+	if _, err := svc.UpdateProfile(ctx, user.ID, UpdateProfileInput{TaxCountry: &france}); !errors.Is(err, ErrInvalidTaxProfile) {
+		t.Errorf("expected ErrInvalidTaxProfile, got %v", err)
+	}
+}
+
+func TestUpdateProfile_SpecialRegimesAreExclusive(t *testing.T) {
+	svc, _ := newTestService()
+	ctx := context.Background()
+
+	user, _, err := svc.Register(ctx, RegisterInput{
+		Email:    "jovem@example.com",
+		Password: "secure123",
+		FullName: "Dr. Junior",
+	})
+	if err != nil {
+		t.Fatalf("Register returned unexpected error: %v", err)
+	}
+
+	first := 2024
+	updated, err := svc.UpdateProfile(ctx, user.ID, UpdateProfileInput{IRSJovemFirstYear: &first})
+	if err != nil {
+		t.Fatalf("UpdateProfile returned unexpected error: %v", err)
+	}
+	if updated.IRSJovemFirstYear == nil || *updated.IRSJovemFirstYear != first {
+		t.Errorf("expected IRS Jovem from %d, got %v", first, updated.IRSJovemFirstYear)
+	}
+
+	if _, err := svc.UpdateProfile(ctx, user.ID, UpdateProfileInput{IFICIStartYear: &first}); !errors.Is(err, ErrInvalidTaxProfile) {
+		t.Errorf("expected ErrInvalidTaxProfile, got %v", err)
+	}
+
+	none := 0
+	updated, err = svc.UpdateProfile(ctx, user.ID, UpdateProfileInput{IRSJovemFirstYear: &none, IFICIStartYear: &first})
+	if err != nil {
+		t.Fatalf("UpdateProfile returned unexpected error: %v", err)
+	}
+	if updated.IRSJovemFirstYear != nil || updated.IFICIStartYear == nil {
+		t.Errorf("expected IRS Jovem cleared and IFICI set, got %v and %v", updated.IRSJovemFirstYear, updated.IFICIStartYear)
+	}
+}
+
+func TestCalendarFeedToken(t *testing.T) {
+	svc, _ := newTestService()
+	ctx := context.Background()
+	userID := uuid.New()
+
+	first, err := svc.CreateCalendarFeedToken(ctx, userID)
+	if err != nil {
+		t.Fatalf("CreateCalendarFeedToken failed: %v", err)
+	}
+	if got, err := svc.ValidateCalendarFeedToken(ctx, first); err != nil || got != userID {
+		t.Fatalf("expected the token to belong to the user, got %v (err %v)", got, err)
+	}
+
+	// A new token replaces the old one
+	second, err := svc.CreateCalendarFeedToken(ctx, userID)
+	if err != nil || second == first {
+		t.Fatalf("expected a fresh token, got %q (err %v)", second, err)
+	}
+	if _, err := svc.ValidateCalendarFeedToken(ctx, first); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected the replaced token to be rejected, got %v", err)
+	}
+
+	if err := svc.RevokeCalendarFeedToken(ctx, userID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.ValidateCalendarFeedToken(ctx, second); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected the revoked token to be rejected, got %v", err)
+	}
+	if _, err := svc.ValidateCalendarFeedToken(ctx, ""); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected an empty token to be rejected, got %v", err)
+	}
+}
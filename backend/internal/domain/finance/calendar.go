@@ -0,0 +1,53 @@
+package finance
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+// BuildTaxCalendar lays out the Portuguese deadlines falling within a year.
+// ssReports are the Social Security reports whose declarations and
+// contributions may fall due in it, the previous year's and the year's own;
+// settlement is the IRS expected to be due on the previous year's return and
+// instalments the year's payments on account. Months with nothing to pay,
+// exempt or without income, have no payment deadline, but every quarter is
+// still declared.
+func BuildTaxCalendar(year int, ssReports []*SocialSecurityReport, settlement money.Cents, instalments [3]tax.Instalment, loc *time.Location) *tax.Calendar {
+	calendar := tax.NewCalendar(year, tax.Portugal().Code)
+
+	for _, report := range ssReports {
+		for _, d := range report.Declarations {
+			calendar.Add(tax.Deadline{
+				Kind:      tax.DeadlineSSDeclaration,
+				Title:     fmt.Sprintf("Social Security declaration for Q%d %d", d.Quarter, report.Year),
+				Period:    fmt.Sprintf("%d-Q%d", report.Year, d.Quarter),
+				Due:       d.DeclarationDue,
+				Income:    d.Gross,
+				Estimated: d.Estimated,
+			})
+
+			for _, p := range d.Payments {
+				if p.Exempt || p.Amount <= 0 {
+					continue
+				}
+				opens := p.DueFrom
+				month, _ := time.Parse("2006-01", p.Month)
+				calendar.Add(tax.Deadline{
+					Kind:      tax.DeadlineSSPayment,
+					Title:     "Social Security contributions for " + month.Format("January 2006"),
+					Period:    p.Month,
+					Opens:     &opens,
+					Due:       p.DueBy,
+					Amount:    p.Amount,
+					Estimated: d.Estimated,
+				})
+			}
+		}
+	}
+
+	calendar.Add(tax.IRSDeadlines(year, settlement, instalments, loc)...)
+	return calendar
+}
@@ -0,0 +1,54 @@
+package finance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+func TestBuildTaxCalendar(t *testing.T) {
+	loc := time.UTC
+	asOf := time.Date(2026, 5, 15, 0, 0, 0, 0, loc)
+	quarters := [4]money.Cents{money.FromEuros(9000), money.FromEuros(9000), money.FromEuros(9000), money.FromEuros(9000)}
+	prior := BuildSocialSecurityReport(2025, quarters, asOf, nil, 0, tax.Portugal(), loc)
+	current := BuildSocialSecurityReport(2026, [4]money.Cents{money.FromEuros(9000), 0, 0, 0}, asOf, nil, 0, tax.Portugal(), loc)
+
+	instalments := [3]tax.Instalment{{Amount: money.FromEuros(400)}, {Amount: money.FromEuros(400)}, {Amount: money.FromEuros(400)}}
+	calendar := BuildTaxCalendar(2026, []*SocialSecurityReport{prior, current}, money.FromEuros(800), instalments, loc)
+
+	count := map[tax.DeadlineKind]int{}
+	for i, d := range calendar.Deadlines {
+		count[d.Kind]++
+		if d.Due.Year() != 2026 {
+			t.Errorf("deadline outside the year: %+v", d)
+		}
+		if i > 0 && d.Due.Before(calendar.Deadlines[i-1].Due) {
+			t.Errorf("deadlines out of order at %d", i)
+		}
+	}
+
+	// 2025's Q4 in January and 2026's first three quarters
+	if count[tax.DeadlineSSDeclaration] != 4 {
+		t.Errorf("expected four declarations, got %d", count[tax.DeadlineSSDeclaration])
+	}
+	// December 2025 to March 2026 from 2025's declarations and April to June
+	// from 2026's Q1; the quarters without income set nothing to pay
+	if count[tax.DeadlineSSPayment] != 7 {
+		t.Errorf("expected seven contribution payments, got %d", count[tax.DeadlineSSPayment])
+	}
+	if count[tax.DeadlineIRSReturn] != 1 || count[tax.DeadlineIRSPayment] != 1 || count[tax.DeadlinePaymentOnAccount] != 3 {
+		t.Errorf("unexpected IRS deadlines %v", count)
+	}
+
+	first := calendar.Deadlines[0]
+	if first.Kind != tax.DeadlineSSPayment || first.Period != "2025-12" || first.Due.Day() != 20 || first.Opens.Day() != 10 {
+		t.Errorf("expected December's contributions first, got %+v", first)
+	}
+	for _, d := range calendar.Deadlines {
+		if d.Kind == tax.DeadlineSSDeclaration && d.Period == "2026-Q2" && (!d.Estimated || d.Income != 0) {
+			t.Errorf("expected the running quarter declared as an estimate, got %+v", d)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package finance
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/workplace"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+// IRS for a year is assessed after the spring filing: refunds usually arrive
+// in July and amounts due must be paid by the end of August.
+const (
+	irsRefundMonth  = time.July
+	irsPaymentMonth = time.August
+)
+
+// CashFlowLookbackMonths is how many months of earnings before the forecast
+// year still produce cash movements within it.
+func CashFlowLookbackMonths(maxTermsDays int) int {
+	// July-September contributions are paid until January
+	lookback := 6
+	if terms := (maxTermsDays+29)/30 + 1; terms > lookback {
+		lookback = terms
+	}
+	return lookback
+}
+
+// CashReceipt is money expected from one workplace for one month of work.
+type CashReceipt struct {
+	WorkplaceID   uuid.UUID   `json:"workplace_id"`
+	WorkplaceName string      `json:"workplace_name"`
+	EarnedMonth   string      `json:"earned_month"` // YYYY-MM the work was done
+	Gross         money.Cents `json:"gross"`
+	Withholding   money.Cents `json:"withholding"`
+	Net           money.Cents `json:"net"`
+}
+
+type CashFlowMonth struct {
+	Month string `json:"month"` // YYYY-MM
+
+	GrossReceipts  money.Cents `json:"gross_receipts"`
+	Withholding    money.Cents `json:"withholding"`
+	NetReceipts    money.Cents `json:"net_receipts"`
+	SocialSecurity money.Cents `json:"social_security"`
+	IRSSettlement  money.Cents `json:"irs_settlement"` // positive when tax is due, negative for a refund
+	// Instalments paid on account of the year's own IRS
+	PaymentsOnAccount money.Cents `json:"payments_on_account"`
+
+	NetCashFlow money.Cents `json:"net_cash_flow"`
+	Cumulative  money.Cents `json:"cumulative"`
+
+	Receipts []CashReceipt `json:"receipts"`
+}
+
+type CashFlowForecast struct {
+	Year   int             `json:"year"`
+	Months []CashFlowMonth `json:"months"`
+
+	TotalNetReceipts       money.Cents `json:"total_net_receipts"`
+	TotalOutflows          money.Cents `json:"total_outflows"`
+	TotalNetCashFlow       money.Cents `json:"total_net_cash_flow"`
+	PriorYearIRSSettlement money.Cents `json:"prior_year_irs_settlement"`
+}
+
+// BuildCashFlowForecast turns monthly earnings into the months the money
+// actually moves. Each month's earnings are invoiced at month end and paid
+// after the workplace's payment terms, net of withholding. SS contributions
+// for a quarter are paid in the three months after it is declared, the
+// prior year's IRS settlement lands in the summer and the year's payments on
+// account in the months they fall due.
+//
+// earnings must cover enough months before the year to account for the
+// longest payment terms and the SS quarters still being paid in January;
+// see CashFlowLookbackMonths, in chronological order so that a dispensed
+// user's withholding resumes once the year's income passes the threshold.
+// Payments on account fall in the month they are due in loc.
+func BuildCashFlowForecast(year int, earnings []EarningsSummary, workplaces map[uuid.UUID]*workplace.Workplace, engine tax.Engine, config tax.YearConfig, dispensed bool, priorYearSettlement money.Cents, paymentsOnAccount [3]money.Cents, loc *time.Location) *CashFlowForecast {
+	forecast := &CashFlowForecast{Year: year, PriorYearIRSSettlement: priorYearSettlement}
+	yearStart := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	months := make([]CashFlowMonth, 12)
+	for i := range months {
+		months[i].Month = yearStart.AddDate(0, i, 0).Format("2006-01")
+	}
+	monthIndex := func(t time.Time) (int, bool) {
+		if t.Year() != year {
+			return 0, false
+		}
+		return int(t.Month()) - 1, true
+	}
+
+	quarterGross := make(map[time.Time]money.Cents)
+	yearGross := make(map[int]money.Cents)
+	for _, summary := range earnings {
+		earnedStart, err := time.Parse("2006-01", summary.Period)
+		if err != nil {
+			continue
+		}
+		quarter := time.Date(earnedStart.Year(), ((earnedStart.Month()-1)/3)*3+1, 1, 0, 0, 0, 0, time.UTC)
+		quarterGross[quarter] += summary.GrossEarnings
+
+		invoicedAt := earnedStart.AddDate(0, 1, -1)
+		for _, we := range summary.ByWorkplace {
+			if we.Gross == 0 {
+				continue
+			}
+			yearGross[earnedStart.Year()] += we.Gross
+			terms := workplace.DefaultPaymentTermsDays
+			wp, ok := workplaces[we.WorkplaceID]
+			if ok {
+				terms = wp.PaymentTermsDays
+			}
+
+			idx, ok := monthIndex(invoicedAt.AddDate(0, 0, terms))
+			if !ok {
+				continue
+			}
+			rate := tax.WithholdingRate(config, payerOf(wp), tax.Dispensed(config, dispensed, yearGross[earnedStart.Year()]))
+			withholding := engine.CalculateWithholding(we.Gross, rate)
+			m := &months[idx]
+			m.GrossReceipts += we.Gross
+			m.Withholding += withholding
+			m.NetReceipts += we.Gross - withholding
+			m.Receipts = append(m.Receipts, CashReceipt{
+				WorkplaceID:   we.WorkplaceID,
+				WorkplaceName: we.WorkplaceName,
+				EarnedMonth:   summary.Period,
+				Gross:         we.Gross,
+				Withholding:   withholding,
+				Net:           we.Gross - withholding,
+			})
+		}
+	}
+
+	// A quarter is declared in the month after it ends and its contributions
+	// are paid over the following three months. Quarters without income are
+	// not declared.
+	for quarter, gross := range quarterGross {
+		if gross <= 0 {
+			continue
+		}
+		ss := engine.CalculateSocialSecurity(config, gross)
+		for offset := 4; offset <= 6; offset++ {
+			if idx, ok := monthIndex(quarter.AddDate(0, offset, 0)); ok {
+				months[idx].SocialSecurity += ss.MonthlyContribution
+			}
+		}
+	}
+
+	if priorYearSettlement != 0 {
+		month := irsPaymentMonth
+		if priorYearSettlement < 0 {
+			month = irsRefundMonth
+		}
+		months[month-1].IRSSettlement = priorYearSettlement
+	}
+	for i, amount := range paymentsOnAccount {
+		due := tax.PaymentOnAccountDue(year, i+1, loc)
+		months[due.Month()-1].PaymentsOnAccount += amount
+	}
+
+	var cumulative money.Cents
+	for i := range months {
+		m := &months[i]
+		m.NetCashFlow = m.NetReceipts - m.SocialSecurity - m.IRSSettlement - m.PaymentsOnAccount
+		cumulative += m.NetCashFlow
+		m.Cumulative = cumulative
+
+		forecast.TotalNetReceipts += m.NetReceipts
+		forecast.TotalOutflows += m.SocialSecurity + m.IRSSettlement + m.PaymentsOnAccount
+		forecast.TotalNetCashFlow += m.NetCashFlow
+	}
+	forecast.Months = months
+
+	return forecast
+}
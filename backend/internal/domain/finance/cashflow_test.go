@@ -0,0 +1,68 @@
+package finance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/workplace"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+func TestBuildCashFlowForecast(t *testing.T) {
+	hospital := &workplace.Workplace{ID: uuid.New(), Name: "Hospital", PaymentTermsDays: 90, WithholdingRate: agreedRate(0.23)}
+	workplaces := map[uuid.UUID]*workplace.Workplace{hospital.ID: hospital}
+	engine := tax.NewPortugalEngine()
+	config := tax.Portugal2026Config()
+
+	earned := func(period string, gross money.Cents) EarningsSummary {
+		return EarningsSummary{
+			Period:        period,
+			GrossEarnings: gross,
+			ByWorkplace:   []WorkplaceEarnings{{WorkplaceID: hospital.ID, WorkplaceName: "Hospital", Gross: gross}},
+		}
+	}
+	earnings := []EarningsSummary{
+		earned("2025-11", 300000),
+		earned("2026-01", 400000),
+	}
+
+	forecast := BuildCashFlowForecast(2026, earnings, workplaces, engine, config, false, -50000, [3]money.Cents{}, time.UTC)
+
+	if len(forecast.Months) != 12 {
+		t.Fatalf("expected 12 months, got %d", len(forecast.Months))
+	}
+	// November work is invoiced on 30 Nov and paid 90 days later, in February
+	feb := forecast.Months[1]
+	if feb.GrossReceipts != 300000 || feb.Withholding != 69000 || feb.NetReceipts != 231000 {
+		t.Errorf("unexpected February receipts: %+v", feb)
+	}
+	if len(feb.Receipts) != 1 || feb.Receipts[0].EarnedMonth != "2025-11" {
+		t.Errorf("expected one receipt for November work, got %+v", feb.Receipts)
+	}
+	// January work is invoiced on 31 Jan and paid on 1 May
+	if forecast.Months[4].NetReceipts != 308000 {
+		t.Errorf("expected January work to be received in May, got %+v", forecast.Months[4])
+	}
+
+	// Q4 2025 is declared in January and paid February-April
+	q4 := engine.CalculateSocialSecurity(config, 300000).MonthlyContribution
+	for _, idx := range []int{1, 2, 3} {
+		if forecast.Months[idx].SocialSecurity != q4 {
+			t.Errorf("expected %d SS in %s, got %d", q4, forecast.Months[idx].Month, forecast.Months[idx].SocialSecurity)
+		}
+	}
+	if forecast.Months[0].SocialSecurity != 0 {
+		t.Errorf("expected no SS in January without Q3 income, got %d", forecast.Months[0].SocialSecurity)
+	}
+
+	if forecast.Months[6].IRSSettlement != -50000 || forecast.Months[6].NetCashFlow <= 0 {
+		t.Errorf("expected the prior-year refund in July, got %+v", forecast.Months[6])
+	}
+
+	last := forecast.Months[11]
+	if last.Cumulative != forecast.TotalNetCashFlow {
+		t.Errorf("expected cumulative %d to equal total %d", last.Cumulative, forecast.TotalNetCashFlow)
+	}
+}
@@ -0,0 +1,237 @@
+package finance
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+var ErrInvalidPeriod = errors.New("invalid period")
+
+// Period is a half-open [Start, End) interval with a display label.
+type Period struct {
+	Label string    `json:"label"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// ParsePeriod understands "2026" (year), "2026-Q1" (quarter), "2026-03"
+// (month) and "2026-01-15..2026-02-10" (inclusive date range), with
+// boundaries drawn in loc.
+func ParsePeriod(spec string, loc *time.Location) (Period, error) {
+	spec = strings.TrimSpace(spec)
+	invalid := fmt.Errorf("%w: %q", ErrInvalidPeriod, spec)
+
+	if from, to, ok := strings.Cut(spec, ".."); ok {
+		start, err := time.ParseInLocation("2006-01-02", from, loc)
+		if err != nil {
+			return Period{}, invalid
+		}
+		last, err := time.ParseInLocation("2006-01-02", to, loc)
+		if err != nil || last.Before(start) {
+			return Period{}, invalid
+		}
+		return Period{Label: spec, Start: start, End: last.AddDate(0, 0, 1)}, nil
+	}
+
+	if year, quarter, ok := strings.Cut(strings.ToUpper(spec), "-Q"); ok {
+		y, err := strconv.Atoi(year)
+		if err != nil {
+			return Period{}, invalid
+		}
+		q, err := strconv.Atoi(quarter)
+		if err != nil || q < 1 || q > 4 {
+			return Period{}, invalid
+		}
+		start, _ := MonthBounds(y, time.Month((q-1)*3+1), loc)
+		return Period{Label: fmt.Sprintf("%d-Q%d", y, q), Start: start, End: start.AddDate(0, 3, 0)}, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01", spec, loc); err == nil {
+		start, end := MonthBounds(t.Year(), t.Month(), loc)
+		return Period{Label: spec, Start: start, End: end}, nil
+	}
+
+	if y, err := strconv.Atoi(spec); err == nil && len(spec) == 4 {
+		start, end := YearBounds(y, loc)
+		return Period{Label: spec, Start: start, End: end}, nil
+	}
+
+	return Period{}, invalid
+}
+
+// YearEarlier returns the same period shifted back one year.
+func (p Period) YearEarlier() Period {
+	label := p.Label
+	if y, err := strconv.Atoi(label[:4]); err == nil && !strings.Contains(label, "..") {
+		label = strconv.Itoa(y-1) + label[4:]
+	} else {
+		label = p.Start.AddDate(-1, 0, 0).Format("2006-01-02") + ".." + p.End.AddDate(-1, 0, -1).Format("2006-01-02")
+	}
+	return Period{Label: label, Start: p.Start.AddDate(-1, 0, 0), End: p.End.AddDate(-1, 0, 0)}
+}
+
+// PeriodMetrics are the comparable figures for one period, overall or for a
+// single workplace.
+type PeriodMetrics struct {
+	Gross        money.Cents `json:"gross"`
+	Hours        float64     `json:"hours"`
+	HourlyRate   money.Cents `json:"hourly_rate"` // gross per hour worked
+	ShiftCount   int         `json:"shift_count"`
+	PatientsSeen int         `json:"patients_seen"`
+}
+
+// MetricsDelta is current minus previous. GrossChange is relative to the
+// previous gross and omitted when there was none.
+type MetricsDelta struct {
+	Gross        money.Cents `json:"gross"`
+	Hours        float64     `json:"hours"`
+	HourlyRate   money.Cents `json:"hourly_rate"`
+	ShiftCount   int         `json:"shift_count"`
+	PatientsSeen int         `json:"patients_seen"`
+	GrossChange  *float64    `json:"gross_change,omitempty"`
+}
+
+type WorkplaceComparison struct {
+	WorkplaceID   uuid.UUID     `json:"workplace_id"`
+	WorkplaceName string        `json:"workplace_name"`
+	Color         string        `json:"color"`
+	Current       PeriodMetrics `json:"current"`
+	Previous      PeriodMetrics `json:"previous"`
+	Delta         MetricsDelta  `json:"delta"`
+}
+
+// TrendPoint is one month of the rolling trend. Rolling12Gross sums the
+// twelve months ending with Month.
+type TrendPoint struct {
+	Month          string      `json:"month"` // YYYY-MM
+	Gross          money.Cents `json:"gross"`
+	Hours          float64     `json:"hours"`
+	ShiftCount     int         `json:"shift_count"`
+	Rolling12Gross money.Cents `json:"rolling_12_gross"`
+}
+
+type PeriodComparison struct {
+	CurrentPeriod  Period `json:"current_period"`
+	PreviousPeriod Period `json:"previous_period"`
+
+	Current  PeriodMetrics `json:"current"`
+	Previous PeriodMetrics `json:"previous"`
+	Delta    MetricsDelta  `json:"delta"`
+
+	ByWorkplace []WorkplaceComparison `json:"by_workplace"`
+	Trend       []TrendPoint          `json:"trend"`
+}
+
+func metricsFromWorkplace(we WorkplaceEarnings) PeriodMetrics {
+	m := PeriodMetrics{
+		Gross:        we.Gross,
+		Hours:        we.Hours,
+		ShiftCount:   we.ShiftCount,
+		PatientsSeen: we.PatientsSeen,
+	}
+	m.HourlyRate = hourlyRate(m.Gross, m.Hours)
+	return m
+}
+
+func metricsFromSummary(s *EarningsSummary) PeriodMetrics {
+	m := PeriodMetrics{Gross: s.GrossEarnings, ShiftCount: s.ShiftCount}
+	for _, we := range s.ByWorkplace {
+		m.Hours += we.Hours
+		m.PatientsSeen += we.PatientsSeen
+	}
+	m.HourlyRate = hourlyRate(m.Gross, m.Hours)
+	return m
+}
+
+func hourlyRate(gross money.Cents, hours float64) money.Cents {
+	if hours <= 0 {
+		return 0
+	}
+	return money.Cents(float64(gross)/hours + 0.5)
+}
+
+func deltaOf(current, previous PeriodMetrics) MetricsDelta {
+	d := MetricsDelta{
+		Gross:        current.Gross - previous.Gross,
+		Hours:        current.Hours - previous.Hours,
+		HourlyRate:   current.HourlyRate - previous.HourlyRate,
+		ShiftCount:   current.ShiftCount - previous.ShiftCount,
+		PatientsSeen: current.PatientsSeen - previous.PatientsSeen,
+	}
+	if previous.Gross != 0 {
+		change := float64(d.Gross) / float64(previous.Gross)
+		d.GrossChange = &change
+	}
+	return d
+}
+
+// ComparePeriods computes overall and per-workplace deltas between two
+// earnings summaries. Workplaces present in only one period are compared
+// against zero.
+func ComparePeriods(current, previous *EarningsSummary) (PeriodMetrics, PeriodMetrics, MetricsDelta, []WorkplaceComparison) {
+	cur := metricsFromSummary(current)
+	prev := metricsFromSummary(previous)
+
+	byID := make(map[uuid.UUID]*WorkplaceComparison)
+	entry := func(we WorkplaceEarnings) *WorkplaceComparison {
+		wc, ok := byID[we.WorkplaceID]
+		if !ok {
+			wc = &WorkplaceComparison{WorkplaceID: we.WorkplaceID, WorkplaceName: we.WorkplaceName, Color: we.Color}
+			byID[we.WorkplaceID] = wc
+		}
+		return wc
+	}
+	for _, we := range current.ByWorkplace {
+		entry(we).Current = metricsFromWorkplace(we)
+	}
+	for _, we := range previous.ByWorkplace {
+		entry(we).Previous = metricsFromWorkplace(we)
+	}
+
+	workplaces := make([]WorkplaceComparison, 0, len(byID))
+	for _, wc := range byID {
+		wc.Delta = deltaOf(wc.Current, wc.Previous)
+		workplaces = append(workplaces, *wc)
+	}
+	sort.Slice(workplaces, func(i, j int) bool {
+		if workplaces[i].Current.Gross != workplaces[j].Current.Gross {
+			return workplaces[i].Current.Gross > workplaces[j].Current.Gross
+		}
+		return workplaces[i].WorkplaceName < workplaces[j].WorkplaceName
+	})
+
+	return cur, prev, deltaOf(cur, prev), workplaces
+}
+
+// BuildTrend returns the twelve months ending with endMonth. monthly is keyed
+// by period (YYYY-MM) and should also hold the eleven months before the
+// series starts so the first rolling totals are complete.
+func BuildTrend(monthly map[string]EarningsSummary, endMonth time.Time) []TrendPoint {
+	first := time.Date(endMonth.Year(), endMonth.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -11, 0)
+
+	trend := make([]TrendPoint, 0, 12)
+	for i := 0; i < 12; i++ {
+		month := first.AddDate(0, i, 0)
+		summary := monthly[month.Format("2006-01")]
+		point := TrendPoint{
+			Month:      month.Format("2006-01"),
+			Gross:      summary.GrossEarnings,
+			ShiftCount: summary.ShiftCount,
+		}
+		for _, we := range summary.ByWorkplace {
+			point.Hours += we.Hours
+		}
+		for back := 0; back < 12; back++ {
+			point.Rolling12Gross += monthly[month.AddDate(0, -back, 0).Format("2006-01")].GrossEarnings
+		}
+		trend = append(trend, point)
+	}
+	return trend
+}
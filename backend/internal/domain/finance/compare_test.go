@@ -0,0 +1,106 @@
+package finance
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		spec       string
+		start, end time.Time
+	}{
+		{"2026", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"2026-Q2", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"2026-03", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"2026-01-15..2026-02-10", time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		p, err := ParsePeriod(tt.spec, time.UTC)
+		if err != nil {
+			t.Errorf("ParsePeriod(%q) returned unexpected error: %v", tt.spec, err)
+			continue
+		}
+		if !p.Start.Equal(tt.start) || !p.End.Equal(tt.end) {
+			t.Errorf("ParsePeriod(%q) = [%v, %v), want [%v, %v)", tt.spec, p.Start, p.End, tt.start, tt.end)
+		}
+	}
+
+	for _, spec := range []string{"", "2026-Q5", "26", "2026-02-10..2026-01-15", "last year"} {
+		if _, err := ParsePeriod(spec, time.UTC); !errors.Is(err, ErrInvalidPeriod) {
+			t.Errorf("ParsePeriod(%q) expected ErrInvalidPeriod, got %v", spec, err)
+		}
+	}
+
+	p, _ := ParsePeriod("2026-Q1", time.UTC)
+	if prev := p.YearEarlier(); prev.Label != "2025-Q1" || !prev.Start.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected year-earlier period %+v", prev)
+	}
+}
+
+func TestComparePeriods(t *testing.T) {
+	hospital, clinic := uuid.New(), uuid.New()
+	current := &EarningsSummary{
+		GrossEarnings: 360000,
+		ShiftCount:    10,
+		ByWorkplace: []WorkplaceEarnings{
+			{WorkplaceID: hospital, WorkplaceName: "Hospital", Gross: 360000, Hours: 90, ShiftCount: 10, PatientsSeen: 40},
+		},
+	}
+	previous := &EarningsSummary{
+		GrossEarnings: 300000,
+		ShiftCount:    9,
+		ByWorkplace: []WorkplaceEarnings{
+			{WorkplaceID: hospital, WorkplaceName: "Hospital", Gross: 240000, Hours: 80, ShiftCount: 8, PatientsSeen: 30},
+			{WorkplaceID: clinic, WorkplaceName: "Clinic", Gross: 60000, Hours: 12, ShiftCount: 1},
+		},
+	}
+
+	cur, prev, delta, byWorkplace := ComparePeriods(current, previous)
+
+	if cur.HourlyRate != money.Cents(4000) || prev.Hours != 92 {
+		t.Errorf("unexpected period metrics: current %+v, previous %+v", cur, prev)
+	}
+	if delta.Gross != 60000 || delta.ShiftCount != 1 || delta.PatientsSeen != 10 {
+		t.Errorf("unexpected delta %+v", delta)
+	}
+	if delta.GrossChange == nil || *delta.GrossChange != 0.2 {
+		t.Errorf("expected a 20%% gross increase, got %v", delta.GrossChange)
+	}
+
+	if len(byWorkplace) != 2 || byWorkplace[0].WorkplaceID != hospital {
+		t.Fatalf("expected hospital first, got %+v", byWorkplace)
+	}
+	if byWorkplace[0].Delta.HourlyRate != money.Cents(1000) {
+		t.Errorf("expected hospital hourly rate up 10.00, got %d", byWorkplace[0].Delta.HourlyRate)
+	}
+	if byWorkplace[1].Current.Gross != 0 || byWorkplace[1].Delta.Gross != -60000 {
+		t.Errorf("expected clinic compared against zero, got %+v", byWorkplace[1])
+	}
+}
+
+func TestBuildTrend(t *testing.T) {
+	monthly := make(map[string]EarningsSummary)
+	start := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 24; i++ {
+		period := start.AddDate(0, i, 0).Format("2006-01")
+		monthly[period] = EarningsSummary{Period: period, GrossEarnings: money.Cents(1000 * (i + 1))}
+	}
+
+	trend := BuildTrend(monthly, time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC))
+
+	if len(trend) != 12 || trend[0].Month != "2025-04" || trend[11].Month != "2026-03" {
+		t.Fatalf("expected April 2025 to March 2026, got %+v", trend)
+	}
+	// Months 2..13 of the series: 2,000 + ... + 13,000
+	if trend[0].Rolling12Gross != money.Cents(90000) {
+		t.Errorf("expected rolling total 90000, got %d", trend[0].Rolling12Gross)
+	}
+	if trend[11].Rolling12Gross != money.Cents(222000) {
+		t.Errorf("expected rolling total 222000, got %d", trend[11].Rolling12Gross)
+	}
+}
@@ -0,0 +1,171 @@
+package finance
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+type ExpenseCategory string
+
+const (
+	ExpenseCategoryEquipment        ExpenseCategory = "equipment"
+	ExpenseCategoryTraining         ExpenseCategory = "training"
+	ExpenseCategoryProfessionalFees ExpenseCategory = "professional_fees"
+	ExpenseCategoryInsurance        ExpenseCategory = "insurance"
+	ExpenseCategoryRent             ExpenseCategory = "rent"
+	ExpenseCategoryTravel           ExpenseCategory = "travel"
+	ExpenseCategoryCommunications   ExpenseCategory = "communications"
+	ExpenseCategorySoftware         ExpenseCategory = "software"
+	ExpenseCategoryAccounting       ExpenseCategory = "accounting"
+	ExpenseCategoryOther            ExpenseCategory = "other"
+)
+
+// CategoryRule describes how an expense category counts under each regime.
+// Shares are the fraction of the expense that is accepted.
+type CategoryRule struct {
+	Category        ExpenseCategory `json:"category"`
+	Label           string          `json:"label"`
+	SimplifiedShare float64         `json:"simplified_share"` // toward the 15% justification
+	OrganizedShare  float64         `json:"organized_share"`  // deductible under organized accounting
+}
+
+// ExpenseCategoryRules lists the supported categories. Mixed personal and
+// professional use (phone, internet) is accepted at 25% (art. 33(2) CIRS).
+var ExpenseCategoryRules = []CategoryRule{
+	{Category: ExpenseCategoryEquipment, Label: "Medical equipment and supplies", SimplifiedShare: 1, OrganizedShare: 1},
+	{Category: ExpenseCategoryTraining, Label: "Training, congresses and books", SimplifiedShare: 1, OrganizedShare: 1},
+	{Category: ExpenseCategoryProfessionalFees, Label: "Ordem dos Médicos and society fees", SimplifiedShare: 1, OrganizedShare: 1},
+	{Category: ExpenseCategoryInsurance, Label: "Professional liability insurance", SimplifiedShare: 1, OrganizedShare: 1},
+	{Category: ExpenseCategoryRent, Label: "Office rent", SimplifiedShare: 1, OrganizedShare: 1},
+	{Category: ExpenseCategoryTravel, Label: "Travel and accommodation", SimplifiedShare: 1, OrganizedShare: 1},
+	{Category: ExpenseCategoryCommunications, Label: "Phone and internet", SimplifiedShare: 0.25, OrganizedShare: 0.25},
+	{Category: ExpenseCategorySoftware, Label: "Software and subscriptions", SimplifiedShare: 1, OrganizedShare: 1},
+	{Category: ExpenseCategoryAccounting, Label: "Accounting and legal services", SimplifiedShare: 1, OrganizedShare: 1},
+	{Category: ExpenseCategoryOther, Label: "Other activity expenses", SimplifiedShare: 1, OrganizedShare: 1},
+}
+
+// RuleForCategory returns the rule for a category, or false if it is unknown.
+func RuleForCategory(category ExpenseCategory) (CategoryRule, bool) {
+	for _, rule := range ExpenseCategoryRules {
+		if rule.Category == category {
+			return rule, true
+		}
+	}
+	return CategoryRule{}, false
+}
+
+// Expense is a business expense. Medical services are IVA-exempt, so the IVA
+// paid cannot be recovered and counts as part of the expense.
+type Expense struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	WorkplaceID *uuid.UUID `json:"workplace_id,omitempty"`
+
+	Date        time.Time       `json:"date"`
+	Category    ExpenseCategory `json:"category"`
+	Description *string         `json:"description,omitempty"`
+	SupplierNIF *string         `json:"supplier_nif,omitempty"`
+
+	AmountCents money.Cents `json:"amount_cents"` // excluding IVA
+	IVACents    money.Cents `json:"iva_cents"`
+
+	InEFatura     bool    `json:"in_efatura"` // communicated to AT; required for the simplified regime
+	AttachmentRef *string `json:"attachment_ref,omitempty"`
+	Notes         *string `json:"notes,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (e *Expense) TotalCents() money.Cents {
+	return e.AmountCents + e.IVACents
+}
+
+type CreateExpenseInput struct {
+	WorkplaceID   *uuid.UUID      `json:"workplace_id"`
+	Date          time.Time       `json:"date" validate:"required"`
+	Category      ExpenseCategory `json:"category" validate:"required"`
+	Description   *string         `json:"description"`
+	SupplierNIF   *string         `json:"supplier_nif" validate:"omitempty,len=9,numeric"`
+	AmountCents   int64           `json:"amount_cents" validate:"min=0"`
+	IVACents      int64           `json:"iva_cents" validate:"min=0"`
+	InEFatura     *bool           `json:"in_efatura"`
+	AttachmentRef *string         `json:"attachment_ref"`
+	Notes         *string         `json:"notes"`
+}
+
+type UpdateExpenseInput struct {
+	WorkplaceID   *uuid.UUID       `json:"workplace_id"`
+	Date          *time.Time       `json:"date"`
+	Category      *ExpenseCategory `json:"category"`
+	Description   *string          `json:"description"`
+	SupplierNIF   *string          `json:"supplier_nif" validate:"omitempty,len=9,numeric"`
+	AmountCents   *int64           `json:"amount_cents" validate:"omitempty,min=0"`
+	IVACents      *int64           `json:"iva_cents" validate:"omitempty,min=0"`
+	InEFatura     *bool            `json:"in_efatura"`
+	AttachmentRef *string          `json:"attachment_ref"`
+	Notes         *string          `json:"notes"`
+}
+
+type CategoryTotal struct {
+	Category            ExpenseCategory `json:"category"`
+	Label               string          `json:"label"`
+	Total               money.Cents     `json:"total"`
+	SimplifiedEligible  money.Cents     `json:"simplified_eligible"`
+	OrganizedDeductible money.Cents     `json:"organized_deductible"`
+	Count               int             `json:"count"`
+}
+
+// ExpenseReport summarises a year's expenses and how far they go toward the
+// simplified regime's justification requirement.
+type ExpenseReport struct {
+	FiscalYear  int         `json:"fiscal_year"`
+	GrossIncome money.Cents `json:"gross_income"`
+
+	ByCategory          []CategoryTotal `json:"by_category"`
+	TotalExpenses       money.Cents     `json:"total_expenses"`
+	SimplifiedEligible  money.Cents     `json:"simplified_eligible"`
+	OrganizedDeductible money.Cents     `json:"organized_deductible"`
+
+	Justification tax.JustificationResult `json:"justification"`
+}
+
+// SummarizeExpenses totals expenses per category, applying the category rules.
+// Only expenses communicated on e-Fatura count toward the simplified regime.
+func SummarizeExpenses(expenses []*Expense) (byCategory []CategoryTotal, total, simplified, organized money.Cents) {
+	totals := make(map[ExpenseCategory]*CategoryTotal)
+	for _, e := range expenses {
+		rule, ok := RuleForCategory(e.Category)
+		if !ok {
+			rule, _ = RuleForCategory(ExpenseCategoryOther)
+		}
+		ct, ok := totals[rule.Category]
+		if !ok {
+			ct = &CategoryTotal{Category: rule.Category, Label: rule.Label}
+			totals[rule.Category] = ct
+		}
+
+		amount := e.TotalCents()
+		ct.Total += amount
+		ct.Count++
+		ct.OrganizedDeductible += money.Cents(float64(amount) * rule.OrganizedShare)
+		if e.InEFatura {
+			ct.SimplifiedEligible += money.Cents(float64(amount) * rule.SimplifiedShare)
+		}
+	}
+
+	for _, rule := range ExpenseCategoryRules {
+		ct, ok := totals[rule.Category]
+		if !ok {
+			continue
+		}
+		byCategory = append(byCategory, *ct)
+		total += ct.Total
+		simplified += ct.SimplifiedEligible
+		organized += ct.OrganizedDeductible
+	}
+	return byCategory, total, simplified, organized
+}
@@ -0,0 +1,86 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/workplace"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+func TestSummarizeExpenses(t *testing.T) {
+	expenses := []*Expense{
+		{Category: ExpenseCategoryTraining, AmountCents: 100000, IVACents: 23000, InEFatura: true},
+		{Category: ExpenseCategoryCommunications, AmountCents: 40000, InEFatura: true},
+		{Category: ExpenseCategoryEquipment, AmountCents: 50000, InEFatura: false},
+	}
+
+	byCategory, total, simplified, organized := SummarizeExpenses(expenses)
+
+	if len(byCategory) != 3 {
+		t.Fatalf("expected 3 categories, got %d", len(byCategory))
+	}
+	if total != money.Cents(213000) {
+		t.Errorf("expected total 213000, got %d", total)
+	}
+	// Training in full, communications at 25%, equipment not on e-Fatura
+	if simplified != money.Cents(133000) {
+		t.Errorf("expected 133000 eligible for the simplified regime, got %d", simplified)
+	}
+	if organized != money.Cents(183000) {
+		t.Errorf("expected 183000 deductible under organized accounting, got %d", organized)
+	}
+}
+
+func TestExpenseJustificationFeedsTaxableIncome(t *testing.T) {
+	engine := tax.NewPortugalEngine()
+	config := tax.Portugal2026Config()
+	gross := money.FromEuros(60000)
+
+	// 15% of 60,000 = 9,000; 4,104 automatic leaves 4,896 to justify
+	none := engine.CalculateExpenseJustification(config, tax.Income{Gross: gross})
+	if none.Required != money.FromEuros(9000) || none.Shortfall != money.FromEuros(4896) {
+		t.Errorf("unexpected justification without expenses: %+v", none)
+	}
+
+	partial := engine.CalculateIRS(config, tax.Income{Gross: gross, JustifiedExpenses: money.FromEuros(2896)})
+	if partial.TaxableIncome != money.FromEuros(47000) {
+		t.Errorf("expected taxable income 45,000 + 2,000 shortfall, got %d", partial.TaxableIncome)
+	}
+
+	full := engine.CalculateIRS(config, tax.Income{Gross: gross, JustifiedExpenses: money.FromEuros(6000)})
+	if full.TaxableIncome != money.FromEuros(45000) {
+		t.Errorf("expected taxable income 45,000 when fully justified, got %d", full.TaxableIncome)
+	}
+}
+
+type mockExpenseRepo struct {
+	Repository
+	created []*Expense
+}
+
+func (m *mockExpenseRepo) CreateExpense(_ context.Context, expense *Expense) error {
+	m.created = append(m.created, expense)
+	return nil
+}
+
+func TestCreateExpense_ChecksWorkplaceOwner(t *testing.T) {
+	userID := uuid.New()
+	own := &workplace.Workplace{ID: uuid.New(), UserID: userID}
+	other := &workplace.Workplace{ID: uuid.New(), UserID: uuid.New()}
+	repo := &mockExpenseRepo{}
+	svc := NewService(repo, &mockWorkplaceRepo{workplaces: []*workplace.Workplace{own, other}}, nil, nil, nil)
+
+	input := CreateExpenseInput{WorkplaceID: &other.ID, Date: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Category: ExpenseCategoryTraining, AmountCents: 10000}
+	if _, err := svc.CreateExpense(context.Background(), userID, input); !errors.Is(err, workplace.ErrWorkplaceNotFound) {
+		t.Errorf("expected another user's workplace to be rejected, got %v", err)
+	}
+	input.WorkplaceID = &own.ID
+	if _, err := svc.CreateExpense(context.Background(), userID, input); err != nil || len(repo.created) != 1 {
+		t.Errorf("expected the expense created for the user's workplace, got %v", err)
+	}
+}
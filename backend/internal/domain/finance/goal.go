@@ -0,0 +1,216 @@
+package finance
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+type GoalBasis string
+
+const (
+	GoalBasisGross GoalBasis = "gross"
+	GoalBasisNet   GoalBasis = "net" // after IRS and Social Security
+)
+
+// IncomeGoal is an income target for a year, or for one month when Month is
+// set, optionally limited to a single workplace.
+type IncomeGoal struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	WorkplaceID *uuid.UUID `json:"workplace_id,omitempty"`
+
+	Year        int         `json:"year"`
+	Month       *int        `json:"month,omitempty"`
+	Basis       GoalBasis   `json:"basis"`
+	TargetCents money.Cents `json:"target_cents"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateGoalInput struct {
+	WorkplaceID *uuid.UUID `json:"workplace_id"`
+	Year        int        `json:"year" validate:"required"`
+	Month       *int       `json:"month" validate:"omitempty,min=1,max=12"`
+	Basis       GoalBasis  `json:"basis" validate:"omitempty,oneof=gross net"`
+	TargetCents int64      `json:"target_cents" validate:"required,min=1"`
+}
+
+type UpdateGoalInput struct {
+	Basis       *GoalBasis `json:"basis" validate:"omitempty,oneof=gross net"`
+	TargetCents *int64     `json:"target_cents" validate:"omitempty,min=1"`
+}
+
+// GoalProgress compares a goal with what has been earned up to AsOf and what
+// is already scheduled for the rest of its period. Amounts are on the goal's
+// basis.
+type GoalProgress struct {
+	Goal  *IncomeGoal `json:"goal"`
+	Start time.Time   `json:"start"`
+	End   time.Time   `json:"end"`
+	AsOf  time.Time   `json:"as_of"`
+
+	Earned    money.Cents `json:"earned"`
+	Scheduled money.Cents `json:"scheduled"`
+	Remaining money.Cents `json:"remaining"` // still missing after scheduled shifts
+	Progress  float64     `json:"progress"`  // earned / target
+	Committed float64     `json:"committed"` // (earned + scheduled) / target
+
+	EffectiveHourlyRate money.Cents `json:"effective_hourly_rate"`
+	RequiredHours       float64     `json:"required_hours"` // extra hours needed at the effective rate
+
+	PaceProjection money.Cents `json:"pace_projection"` // earned plus the recent monthly pace for the time left
+	OnTrack        bool        `json:"on_track"`
+}
+
+// GoalBounds returns the goal's period, drawn in loc.
+func GoalBounds(goal *IncomeGoal, loc *time.Location) (time.Time, time.Time) {
+	if goal.Month != nil {
+		return MonthBounds(goal.Year, time.Month(*goal.Month), loc)
+	}
+	return YearBounds(goal.Year, loc)
+}
+
+// BuildGoalProgress measures a goal against earning segments covering at
+// least the goal's year. The monthly pace is the average gross of the year's
+// months completed by asOf, counted from the same segments as Earned; for a
+// workplace goal it is scaled by that workplace's share of the period's
+// earnings. netRatio converts gross into net for net goals.
+func BuildGoalProgress(goal *IncomeGoal, start, end, asOf time.Time, segments []EarningSegment, netRatio float64) GoalProgress {
+	progress := GoalProgress{Goal: goal, Start: start, End: end, AsOf: asOf}
+
+	ratio := 1.0
+	if goal.Basis == GoalBasisNet {
+		ratio = netRatio
+	}
+	onBasis := func(c money.Cents) money.Cents {
+		return money.Cents(float64(c)*ratio + 0.5)
+	}
+
+	var earned, scheduled, periodTotal, goalTotal money.Cents
+	var earnedHours, totalHours float64
+	for _, seg := range segments {
+		seg, ok := clipSegment(seg, start, end)
+		if !ok {
+			continue
+		}
+		periodTotal += seg.Amount
+		if goal.WorkplaceID != nil && seg.WorkplaceID != *goal.WorkplaceID {
+			continue
+		}
+		goalTotal += seg.Amount
+		totalHours += seg.Hours
+
+		if past, ok := clipSegment(seg, start, asOf); ok {
+			earned += past.Amount
+			earnedHours += past.Hours
+		}
+		if future, ok := clipSegment(seg, asOf, end); ok {
+			scheduled += future.Amount
+		}
+	}
+
+	progress.Earned = onBasis(earned)
+	progress.Scheduled = onBasis(scheduled)
+	if remaining := goal.TargetCents - progress.Earned - progress.Scheduled; remaining > 0 {
+		progress.Remaining = remaining
+	}
+	if goal.TargetCents > 0 {
+		progress.Progress = float64(progress.Earned) / float64(goal.TargetCents)
+		progress.Committed = float64(progress.Earned+progress.Scheduled) / float64(goal.TargetCents)
+	}
+
+	if earnedHours > 0 {
+		progress.EffectiveHourlyRate = hourlyRate(progress.Earned, earnedHours)
+	} else {
+		progress.EffectiveHourlyRate = hourlyRate(onBasis(goalTotal), totalHours)
+	}
+	if progress.EffectiveHourlyRate > 0 {
+		progress.RequiredHours = float64(progress.Remaining) / float64(progress.EffectiveHourlyRate)
+	}
+
+	pace, ok := monthlyPace(segments, goal.Year, start.Location(), asOf)
+	if !ok {
+		// Nothing completed yet to take a pace from; assume the schedule holds
+		progress.PaceProjection = progress.Earned + progress.Scheduled
+	} else {
+		if goal.WorkplaceID != nil {
+			share := 0.0
+			if periodTotal > 0 {
+				share = float64(goalTotal) / float64(periodTotal)
+			}
+			pace = money.Cents(float64(pace) * share)
+		}
+		progress.PaceProjection = progress.Earned + onBasis(money.Cents(float64(pace)*monthsRemaining(start, end, asOf)))
+	}
+	progress.OnTrack = progress.PaceProjection >= goal.TargetCents
+
+	return progress
+}
+
+// monthlyPace averages the gross of the year's months that ended by asOf.
+func monthlyPace(segments []EarningSegment, year int, loc *time.Location, asOf time.Time) (money.Cents, bool) {
+	var total money.Cents
+	months := 0
+	for m := time.January; m <= time.December; m++ {
+		start, end := MonthBounds(year, m, loc)
+		if end.After(asOf) {
+			break
+		}
+		for _, seg := range segments {
+			if part, ok := clipSegment(seg, start, end); ok {
+				total += part.Amount
+			}
+		}
+		months++
+	}
+	if months == 0 {
+		return 0, false
+	}
+	return total / money.Cents(months), true
+}
+
+// monthsRemaining is how many months of [start, end) lie after asOf, counting
+// partial months by their elapsed fraction.
+func monthsRemaining(start, end, asOf time.Time) float64 {
+	if !asOf.Before(end) {
+		return 0
+	}
+	remaining := 0.0
+	for month := start; month.Before(end); month = month.AddDate(0, 1, 0) {
+		next := month.AddDate(0, 1, 0)
+		if next.After(end) {
+			next = end
+		}
+		switch {
+		case !asOf.After(month):
+			remaining++
+		case asOf.Before(next):
+			remaining += float64(next.Sub(asOf)) / float64(next.Sub(month))
+		}
+	}
+	return remaining
+}
+
+// clipSegment limits a segment to [from, to), pro-rating hours and amount.
+func clipSegment(seg EarningSegment, from, to time.Time) (EarningSegment, bool) {
+	span := seg.End.Sub(seg.Start)
+	if span <= 0 || !seg.Start.Before(to) || !seg.End.After(from) {
+		return seg, false
+	}
+	if seg.Start.Before(from) || seg.End.After(to) {
+		part := overlap(seg.Start, seg.End, from, to)
+		fraction := float64(part) / float64(span)
+		seg.Hours *= fraction
+		seg.Amount = money.Cents(float64(seg.Amount)*fraction + 0.5)
+		if seg.Start.Before(from) {
+			seg.Start = from
+		}
+		if seg.End.After(to) {
+			seg.End = to
+		}
+	}
+	return seg, true
+}
@@ -0,0 +1,143 @@
+package finance
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/auth"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+func TestBuildGoalProgress(t *testing.T) {
+	hospital, clinic := uuid.New(), uuid.New()
+
+	// A 10-hour, 500 EUR shift at the hospital on the 10th of every month and
+	// a 200 EUR one at the clinic on the 20th
+	var segments []EarningSegment
+	for m := time.January; m <= time.December; m++ {
+		start := time.Date(2026, m, 10, 8, 0, 0, 0, time.UTC)
+		segments = append(segments,
+			EarningSegment{ShiftID: uuid.New(), WorkplaceID: hospital, Start: start, End: start.Add(10 * time.Hour), Hours: 10, Amount: 50000},
+			EarningSegment{ShiftID: uuid.New(), WorkplaceID: clinic, Start: start.AddDate(0, 0, 10), End: start.AddDate(0, 0, 10).Add(4 * time.Hour), Hours: 4, Amount: 20000},
+		)
+	}
+	asOf := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("annual gross goal", func(t *testing.T) {
+		goal := &IncomeGoal{Year: 2026, Basis: GoalBasisGross, TargetCents: 1000000}
+		start, end := GoalBounds(goal, time.UTC)
+		p := BuildGoalProgress(goal, start, end, asOf, segments, 0.6)
+
+		if p.Earned != 420000 || p.Scheduled != 420000 || p.Remaining != 160000 {
+			t.Errorf("expected 420000 earned, 420000 scheduled, 160000 remaining, got %d, %d, %d", p.Earned, p.Scheduled, p.Remaining)
+		}
+		// 420000 over 84 hours
+		if p.EffectiveHourlyRate != 5000 || p.RequiredHours != 32 {
+			t.Errorf("expected 32 hours at 5000, got %v at %d", p.RequiredHours, p.EffectiveHourlyRate)
+		}
+		// 420000 earned plus six months at the 70000 of each month worked,
+		// whether or not its shifts were marked completed
+		if p.PaceProjection != 840000 || p.OnTrack {
+			t.Errorf("expected pace of 840000 and off track, got %d (on track %v)", p.PaceProjection, p.OnTrack)
+		}
+		if math.Abs(p.Progress-0.42) > 1e-9 || math.Abs(p.Committed-0.84) > 1e-9 {
+			t.Errorf("unexpected progress %v / committed %v", p.Progress, p.Committed)
+		}
+	})
+
+	t.Run("monthly net workplace goal", func(t *testing.T) {
+		month := 7
+		goal := &IncomeGoal{WorkplaceID: &hospital, Year: 2026, Month: &month, Basis: GoalBasisNet, TargetCents: 30000}
+		start, end := GoalBounds(goal, time.UTC)
+		p := BuildGoalProgress(goal, start, end, time.Date(2026, 7, 15, 0, 0, 0, 0, time.UTC), segments, 0.6)
+
+		if p.Earned != 30000 || p.Scheduled != 0 || p.Remaining != 0 {
+			t.Errorf("expected the hospital shift to count as 30000 net earned, got %+v", p)
+		}
+		if !p.OnTrack {
+			t.Error("expected goal to be on track")
+		}
+	})
+}
+
+func TestMonthsRemaining(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+
+	if got := monthsRemaining(start, end, start); got != 12 {
+		t.Errorf("expected 12 months at the start of the year, got %v", got)
+	}
+	if got := monthsRemaining(start, end, time.Date(2026, 12, 16, 12, 0, 0, 0, time.UTC)); math.Abs(got-0.5) > 1e-9 {
+		t.Errorf("expected half a month left, got %v", got)
+	}
+	if got := monthsRemaining(start, end, end); got != 0 {
+		t.Errorf("expected nothing left at the end, got %v", got)
+	}
+}
+
+func TestClipSegment(t *testing.T) {
+	seg := EarningSegment{
+		Start:  time.Date(2026, 1, 31, 20, 0, 0, 0, time.UTC),
+		End:    time.Date(2026, 2, 1, 8, 0, 0, 0, time.UTC),
+		Hours:  12,
+		Amount: money.Cents(36000),
+	}
+
+	clipped, ok := clipSegment(seg, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	if !ok || clipped.Hours != 8 || clipped.Amount != 24000 {
+		t.Errorf("expected 8 hours and 24000, got %+v", clipped)
+	}
+	if _, ok := clipSegment(seg, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)); ok {
+		t.Error("expected no overlap with March")
+	}
+}
+
+type mockGoalRepo struct {
+	mockSettlementRepo
+	goals    []*IncomeGoal
+	segments []EarningSegment
+}
+
+func (m *mockGoalRepo) ListGoals(_ context.Context, _ uuid.UUID, _ int) ([]*IncomeGoal, error) {
+	return m.goals, nil
+}
+
+func (m *mockGoalRepo) ListEarningSegments(_ context.Context, _ uuid.UUID, _, _ time.Time) ([]EarningSegment, error) {
+	return m.segments, nil
+}
+
+func TestGetGoalProgress_NetUsesUserTaxSituation(t *testing.T) {
+	start := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	repo := &mockGoalRepo{
+		mockSettlementRepo: mockSettlementRepo{gross: money.FromEuros(20000)},
+		goals:              []*IncomeGoal{{Year: 2026, Basis: GoalBasisNet, TargetCents: money.FromEuros(50000)}},
+		segments:           []EarningSegment{{Start: start, End: start.Add(10 * time.Hour), Hours: 10, Amount: money.FromEuros(80000)}},
+	}
+	asOf := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	ifici := 2026
+
+	earned := func(user *auth.User) money.Cents {
+		t.Helper()
+		svc := NewService(repo, nil, nil, &mockUserRepo{user: user}, tax.DefaultRegistry())
+		progress, err := svc.GetGoalProgress(context.Background(), uuid.New(), 2026, asOf)
+		if err != nil {
+			t.Fatalf("GetGoalProgress failed: %v", err)
+		}
+		return progress[0].Earned
+	}
+
+	// The ratio comes from the whole projected year, taxed under IFICI's
+	// flat rate rather than the brackets
+	config := tax.ConfigForYear(2026)
+	summary := tax.NewPortugalEngine().CalculateAnnualSummary(config, tax.Income{Gross: money.FromEuros(80000), IFICI: true})
+	if got, want := earned(&auth.User{IFICIStartYear: &ifici}), summary.NetIncome; got != want {
+		t.Errorf("expected %d net under IFICI, got %d", want, got)
+	}
+	if withIFICI, without := earned(&auth.User{IFICIStartYear: &ifici}), earned(&auth.User{}); withIFICI <= without {
+		t.Errorf("expected IFICI to leave more net than the brackets, got %d and %d", withIFICI, without)
+	}
+}
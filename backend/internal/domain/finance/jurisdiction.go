@@ -0,0 +1,100 @@
+package finance
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/workplace"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+// SourceTax is what a year's income bears in one country: the residence taxes
+// the worldwide income, a source country only the income sourced there.
+type SourceTax struct {
+	Country   string            `json:"country"`
+	Name      string            `json:"name"`
+	Residence bool              `json:"residence"` // the user's tax residence
+	Income    tax.Income        `json:"income"`
+	Summary   tax.AnnualSummary `json:"summary"`
+
+	// Relief the residence gives for tax paid abroad (art. 81 CIRS): the
+	// foreign tax, up to the residence's IRS on that income
+	ForeignTaxCredit money.Cents `json:"foreign_tax_credit,omitempty"`
+	IRSDue           money.Cents `json:"irs_due"` // IRS left to pay in this country
+}
+
+// JurisdictionReport splits a year's income by the country it is sourced in.
+type JurisdictionReport struct {
+	Year      int         `json:"year"`
+	Residence string      `json:"residence"`
+	Sources   []SourceTax `json:"sources"`
+}
+
+// BuildJurisdictionReport groups the year's earnings by where each
+// workplace's income is sourced, the user's residence unless the workplace
+// names another country. Each source country taxes its group under its own
+// rules, with a share of the expenses in proportion to gross. The residence
+// taxes the worldwide income, special regimes, withholding dispensation and
+// salary included, and credits the tax paid abroad up to its own IRS on that
+// income.
+func BuildJurisdictionReport(year int, income tax.Income, earnings []WorkplaceEarnings, workplaces map[uuid.UUID]*workplace.Workplace, residence tax.Jurisdiction, registry *tax.Registry) *JurisdictionReport {
+	gross := make(map[string]money.Cents)
+	for _, we := range earnings {
+		wp := workplaces[we.WorkplaceID]
+		if wp == nil || wp.TaxCountry == nil || *wp.TaxCountry == residence.Code {
+			continue
+		}
+		gross[*wp.TaxCountry] += we.Gross
+	}
+
+	codes := make([]string, 0, len(gross))
+	for code := range gross {
+		if code != residence.Code {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	codes = append([]string{residence.Code}, codes...)
+
+	report := &JurisdictionReport{Year: year, Residence: residence.Code}
+	for _, code := range codes {
+		j := residence
+		source := income
+		if code != residence.Code {
+			j = registry.Resolve(code)
+			source.Gross = gross[code]
+			if income.Gross > 0 {
+				share := float64(source.Gross) / float64(income.Gross)
+				source.JustifiedExpenses = money.Cents(float64(income.JustifiedExpenses) * share)
+				source.DeductibleExpenses = money.Cents(float64(income.DeductibleExpenses) * share)
+			}
+			source.IRSJovemYear = 0
+			source.IFICI = false
+			source.WithholdingDispensed = false
+			source.Employment = tax.Employment{}
+			source.SSContributions = nil // declared at home, on all the income
+		}
+
+		summary := j.Engine.CalculateAnnualSummary(j.Config(year), source)
+		report.Sources = append(report.Sources, SourceTax{
+			Country:   code,
+			Name:      j.Name,
+			Residence: code == residence.Code,
+			Income:    source,
+			Summary:   summary,
+			IRSDue:    summary.IRSAmount,
+		})
+	}
+
+	home := &report.Sources[0]
+	for _, foreign := range report.Sources[1:] {
+		if home.Summary.GrossIncome <= 0 {
+			break
+		}
+		share := float64(foreign.Income.Gross) / float64(home.Summary.GrossIncome)
+		home.ForeignTaxCredit += min(foreign.Summary.IRSAmount, money.Cents(float64(home.Summary.IRSAmount)*share))
+	}
+	home.IRSDue -= home.ForeignTaxCredit
+	return report
+}
@@ -0,0 +1,50 @@
+package finance
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/workplace"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+func TestBuildJurisdictionReport(t *testing.T) {
+	spain := "ES"
+	lisbon := &workplace.Workplace{ID: uuid.New(), Name: "Lisbon"}
+	vigo := &workplace.Workplace{ID: uuid.New(), Name: "Vigo", TaxCountry: &spain}
+	workplaces := map[uuid.UUID]*workplace.Workplace{lisbon.ID: lisbon, vigo.ID: vigo}
+
+	income := tax.Income{Gross: money.FromEuros(50000), DeductibleExpenses: money.FromEuros(4000), IRSJovemYear: 2}
+	earnings := []WorkplaceEarnings{
+		{WorkplaceID: lisbon.ID, Gross: money.FromEuros(30000)},
+		{WorkplaceID: vigo.ID, Gross: money.FromEuros(20000)},
+	}
+
+	registry := tax.DefaultRegistry()
+	report := BuildJurisdictionReport(2025, income, earnings, workplaces, tax.Portugal(), registry)
+
+	if len(report.Sources) != 2 || report.Sources[0].Country != "PT" || report.Sources[1].Country != "ES" {
+		t.Fatalf("expected Portugal then Spain, got %+v", report.Sources)
+	}
+	pt, es := report.Sources[0], report.Sources[1]
+	if !pt.Residence || pt.Income != income {
+		t.Errorf("expected the residence to tax the worldwide income, got %+v", pt.Income)
+	}
+	if es.Residence || es.Income.Gross != money.FromEuros(20000) || es.Income.DeductibleExpenses != money.FromEuros(1600) || es.Income.IRSJovemYear != 0 {
+		t.Errorf("unexpected Spanish income %+v", es.Income)
+	}
+
+	want := tax.NewSpainEngine().CalculateAnnualSummary(tax.Spain2025Config(), es.Income)
+	if es.Summary != want || es.IRSDue != want.IRSAmount || es.ForeignTaxCredit != 0 {
+		t.Errorf("expected Spanish rules on the Spanish income, got %+v", es.Summary)
+	}
+
+	// The credit is the Spanish tax, up to the Portuguese IRS on two fifths
+	// of the income
+	home := tax.NewPortugalEngine().CalculateAnnualSummary(tax.Portugal2025Config(), income)
+	credit := min(want.IRSAmount, money.Cents(float64(home.IRSAmount)*0.4))
+	if pt.Summary != home || pt.ForeignTaxCredit != credit || pt.IRSDue != home.IRSAmount-credit {
+		t.Errorf("expected a credit of %d against %d, got %d leaving %d", credit, home.IRSAmount, pt.ForeignTaxCredit, pt.IRSDue)
+	}
+}
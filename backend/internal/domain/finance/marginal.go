@@ -0,0 +1,119 @@
+package finance
+
+import (
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+// MarginalStep is the extra income the marginal analysis prices, 1,000 EUR.
+const MarginalStep money.Cents = 100000
+
+// MarginalAnalysis is what the next MarginalStep of gross income would cost
+// in IRS and Social Security on top of the year's projected income, and how
+// far that income is from the thresholds that change the rates.
+type MarginalAnalysis struct {
+	FiscalYear     int         `json:"fiscal_year"`
+	ProjectedGross money.Cents `json:"projected_gross"` // earnings including scheduled shifts
+	Step           money.Cents `json:"step"`
+
+	IRS            money.Cents `json:"irs"`        // excluding the solidarity surcharge
+	Solidarity     money.Cents `json:"solidarity"` // art. 68-A surcharge
+	SocialSecurity money.Cents `json:"social_security"`
+	Net            money.Cents `json:"net"` // what is left of the step
+
+	IRSRate        float64 `json:"irs_rate"`
+	SolidarityRate float64 `json:"solidarity_rate"`
+	SSRate         float64 `json:"ss_rate"`
+	CombinedRate   float64 `json:"combined_rate"`
+
+	TaxableIncome        money.Cents `json:"taxable_income"`
+	BracketRate          float64     `json:"bracket_rate"`                // normal rate of the current bracket
+	NextBracketRate      float64     `json:"next_bracket_rate,omitempty"` // zero in the top bracket or under IFICI
+	TaxableToNextBracket money.Cents `json:"taxable_to_next_bracket,omitempty"`
+	GrossToNextBracket   money.Cents `json:"gross_to_next_bracket,omitempty"`
+
+	// Contributions stop growing once the monthly base reaches 12 x IAS
+	AtSSCeiling      bool        `json:"at_ss_ceiling"`
+	GrossToSSCeiling money.Cents `json:"gross_to_ss_ceiling,omitempty"`
+}
+
+// BuildMarginalAnalysis runs the engine on the projected income and on that
+// income plus MarginalStep. Social Security grows as the yearly estimate does
+// for income spread evenly over the quarters, from any contributions already
+// declared.
+func BuildMarginalAnalysis(engine tax.Engine, config tax.YearConfig, income tax.Income) *MarginalAnalysis {
+	next := WithGross(engine, config, income, income.Gross+MarginalStep)
+
+	base := engine.CalculateIRS(config, income)
+	more := engine.CalculateIRS(config, next)
+	ssBase := tax.AnnualContributions(engine, config, income)
+	ssMore := tax.AnnualContributions(engine, config, next)
+
+	a := &MarginalAnalysis{
+		FiscalYear:     config.FiscalYear,
+		ProjectedGross: income.Gross,
+		Step:           MarginalStep,
+		Solidarity:     more.SolidarityTax - base.SolidarityTax,
+		SocialSecurity: ssMore - ssBase,
+		TaxableIncome:  base.TaxableIncome,
+	}
+	a.IRS = more.TotalTax - base.TotalTax - a.Solidarity
+	a.Net = a.Step - a.IRS - a.Solidarity - a.SocialSecurity
+
+	step := float64(a.Step)
+	a.IRSRate = float64(a.IRS) / step
+	a.SolidarityRate = float64(a.Solidarity) / step
+	a.SSRate = float64(a.SocialSecurity) / step
+	a.CombinedRate = float64(a.Step-a.Net) / step
+
+	if !income.IFICI {
+		for i, bracket := range config.Brackets {
+			if base.TaxableIncome > bracket.UpperLimit {
+				continue
+			}
+			a.BracketRate = bracket.Rate
+			if i+1 < len(config.Brackets) {
+				a.NextBracketRate = config.Brackets[i+1].Rate
+				a.TaxableToNextBracket = bracket.UpperLimit - base.TaxableIncome
+				a.GrossToNextBracket = grossToTaxable(engine, config, income, bracket.UpperLimit) - income.Gross
+			}
+			break
+		}
+	}
+
+	// The yearly gross, spread evenly, at which the monthly base reaches the
+	// ceiling
+	ceiling := money.Cents(float64(config.IASValueCents*12*3)/config.SSIncomeCoefficient) * 4
+	if income.Gross >= ceiling {
+		a.AtSSCeiling = true
+	} else {
+		a.GrossToSSCeiling = ceiling - income.Gross
+	}
+
+	return a
+}
+
+// grossToTaxable finds, to the cent, the smallest gross income above the
+// income's own whose taxable income exceeds target. Taxable income never falls
+// as gross grows, under either regime.
+func grossToTaxable(engine tax.Engine, config tax.YearConfig, income tax.Income, target money.Cents) money.Cents {
+	taxable := func(gross money.Cents) money.Cents {
+		at := income
+		at.Gross = gross
+		return engine.CalculateIRS(config, at).TaxableIncome
+	}
+
+	lo, hi := income.Gross, income.Gross+MarginalStep
+	for taxable(hi) <= target {
+		lo, hi = hi, hi*2
+	}
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		if taxable(mid) > target {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi
+}
@@ -0,0 +1,50 @@
+package finance
+
+import (
+	"testing"
+
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+func TestBuildMarginalAnalysis(t *testing.T) {
+	engine := tax.NewPortugalEngine()
+	config := tax.Portugal2025Config()
+
+	// 40,000 EUR taxable sits in the 37% bracket, 10,483 EUR below the 43.5% one
+	justified := money.FromEuros(20000)
+	got := BuildMarginalAnalysis(engine, config, tax.Income{Gross: money.FromEuros(53333.34), JustifiedExpenses: justified})
+
+	if got.BracketRate != 0.37 || got.NextBracketRate != 0.435 {
+		t.Fatalf("expected the 37%% bracket, got %v -> %v", got.BracketRate, got.NextBracketRate)
+	}
+	if got.TaxableToNextBracket != money.FromEuros(10483) {
+		t.Errorf("expected 10483 EUR of taxable income to the next bracket, got %d", got.TaxableToNextBracket)
+	}
+	if got.GrossToNextBracket != money.FromEuros(13977.34) {
+		t.Errorf("expected 13977.34 EUR of gross at the 0.75 coefficient, got %d", got.GrossToNextBracket)
+	}
+
+	// 750 EUR more taxable at 37%, and 70% of 1,000 EUR at 21.4%
+	if got.IRS != money.FromEuros(277.50) || got.Solidarity != 0 {
+		t.Errorf("expected 277.50 EUR of IRS, got %d (solidarity %d)", got.IRS, got.Solidarity)
+	}
+	if got.SocialSecurity < money.FromEuros(149) || got.SocialSecurity > money.FromEuros(150) {
+		t.Errorf("expected about 149.80 EUR of contributions, got %d", got.SocialSecurity)
+	}
+	if got.Net != got.Step-got.IRS-got.SocialSecurity || got.CombinedRate < 0.42 || got.CombinedRate > 0.43 {
+		t.Errorf("unexpected net %d at a combined rate of %v", got.Net, got.CombinedRate)
+	}
+	if got.AtSSCeiling || got.GrossToSSCeiling <= 0 {
+		t.Errorf("expected room below the SS ceiling, got %+v", got)
+	}
+
+	// Above the ceiling only IRS and the surcharge grow
+	high := BuildMarginalAnalysis(engine, config, tax.Income{Gross: money.FromEuros(150000), JustifiedExpenses: justified})
+	if !high.AtSSCeiling || high.SocialSecurity != 0 || high.NextBracketRate != 0 {
+		t.Errorf("expected the top bracket at the SS ceiling, got %+v", high)
+	}
+	if high.Solidarity != money.FromEuros(18.75) || high.SolidarityRate != 0.01875 {
+		t.Errorf("expected 2.5%% surcharge on 750 EUR of taxable income, got %d", high.Solidarity)
+	}
+}
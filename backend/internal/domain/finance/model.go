@@ -24,9 +24,14 @@ type Invoice struct {
 
 	InvoiceNumber *string    `json:"invoice_number,omitempty"`
 	IssuedAt      *time.Time `json:"issued_at,omitempty"`
+	DueAt         *time.Time `json:"due_at,omitempty"` // Expected payment date from the workplace's terms
 	PaidAt        *time.Time `json:"paid_at,omitempty"`
 	Notes         *string    `json:"notes,omitempty"`
 
+	// Set when creating the invoice takes the year past the withholding
+	// dispensation threshold; not stored
+	Warning string `json:"warning,omitempty"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -56,19 +61,45 @@ type WorkplaceEarnings struct {
 type TaxEstimate struct {
 	FiscalYear int `json:"fiscal_year"`
 
-	GrossAnnualIncome money.Cents `json:"gross_annual_income"`
+	GrossAnnualIncome money.Cents `json:"gross_annual_income"` // Category B and Category A
 	TaxableIncome     money.Cents `json:"taxable_income"`
+	ExpenseShortfall  money.Cents `json:"expense_shortfall"` // added back under the simplified regime
+
+	// Salary from employment (Category A) and its part of the taxable income,
+	// after the specific deduction
+	EmploymentIncome  money.Cents `json:"employment_income,omitempty"`
+	EmploymentTaxable money.Cents `json:"employment_taxable,omitempty"`
 
+	IRSJovemExempt   money.Cents `json:"irs_jovem_exempt,omitempty"` // income exempt under IRS Jovem
+	IFICITax         money.Cents `json:"ifici_tax,omitempty"`        // IRS at the IFICI flat rate
 	IRSAmount        money.Cents `json:"irs_amount"`
 	IRSEffectiveRate float64     `json:"irs_effective_rate"`
 
+	// Contributions set by the year's quarterly declarations
 	SocialSecurity     money.Cents `json:"social_security"`
-	SSMonthlyBase      money.Cents `json:"ss_monthly_base"`
-	SSQuarterlyPayment money.Cents `json:"ss_quarterly_payment"`
+	SSMonthlyBase      money.Cents `json:"ss_monthly_base"`      // average over quarters with income
+	SSQuarterlyPayment money.Cents `json:"ss_quarterly_payment"` // average over the four quarters
+
+	// Withholding recorded on the year's invoices, plus what the workplaces
+	// will withhold on earnings not invoiced yet
+	WithholdingInvoiced money.Cents `json:"withholding_invoiced"`
+	WithholdingTotal    money.Cents `json:"withholding_total"`
 
-	WithholdingTotal money.Cents `json:"withholding_total"`
+	// Withheld from the salary and contributed by the employee on it, as
+	// recorded on the payslips
+	EmploymentWithheld money.Cents `json:"employment_withheld,omitempty"`
+	EmploymentSS       money.Cents `json:"employment_ss,omitempty"`
 
-	NetAnnualIncome money.Cents `json:"net_annual_income"`
+	// Paid on account of the year's tax, or still expected to be
+	PaymentsOnAccount money.Cents `json:"payments_on_account"`
+
+	// Expected settlement without personal deductions: positive when tax is
+	// due, negative for a refund
+	SettlementBalance money.Cents `json:"settlement_balance"`
+	Refund            money.Cents `json:"refund"`
+	AmountDue         money.Cents `json:"amount_due"`
+
+	NetAnnualIncome money.Cents `json:"net_annual_income"` // gross less IRS and both categories' contributions
 	MonthlyNet      money.Cents `json:"monthly_net"`
 
 	BracketBreakdown []BracketDetail `json:"bracket_breakdown"`
@@ -87,6 +118,8 @@ type Projection struct {
 	ActualGross    money.Cents `json:"actual_gross"`
 	Difference     money.Cents `json:"difference"`
 	IsActual       bool        `json:"is_actual"` // true if month has passed
+
+	PaymentOnAccount money.Cents `json:"payment_on_account,omitempty"` // instalment of IRS due in the month
 }
 
 type CreateInvoiceInput struct {
@@ -94,9 +127,10 @@ type CreateInvoiceInput struct {
 	PeriodStart      time.Time  `json:"period_start" validate:"required"`
 	PeriodEnd        time.Time  `json:"period_end" validate:"required"`
 	GrossAmountCents int64      `json:"gross_amount_cents" validate:"required,min=0"`
-	WithholdingRate  float64    `json:"withholding_rate" validate:"min=0,max=1"`
+	WithholdingRate  *float64   `json:"withholding_rate" validate:"omitempty,min=0,max=1"` // Overrides the client's withholding
 	IVARate          float64    `json:"iva_rate" validate:"min=0,max=1"`
 	InvoiceNumber    *string    `json:"invoice_number"`
 	IssuedAt         *time.Time `json:"issued_at"`
+	DueAt            *time.Time `json:"due_at"` // Overrides the workplace payment terms
 	Notes            *string    `json:"notes"`
 }
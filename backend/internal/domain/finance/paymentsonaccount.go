@@ -0,0 +1,121 @@
+package finance
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+// PaymentOnAccount is an instalment the user paid on account of a year's IRS,
+// as recorded from the tax office's payment notice.
+type PaymentOnAccount struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+
+	FiscalYear  int         `json:"fiscal_year"` // the year whose tax it is paid on account of
+	Instalment  int         `json:"instalment"`  // 1 to 3
+	AmountCents money.Cents `json:"amount_cents"`
+	PaidOn      time.Time   `json:"paid_on"`
+	Reference   *string     `json:"reference,omitempty"` // payment reference on the notice
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RecordPaymentOnAccountInput struct {
+	FiscalYear  int       `json:"fiscal_year" validate:"required"`
+	Instalment  int       `json:"instalment" validate:"required,min=1,max=3"`
+	AmountCents int64     `json:"amount_cents" validate:"required,min=1"`
+	PaidOn      time.Time `json:"paid_on" validate:"required"`
+	Reference   *string   `json:"reference" validate:"omitempty,max=50"`
+}
+
+// PaymentOnAccountInstalment sets one instalment's estimate against what was
+// recorded as paid for it.
+type PaymentOnAccountInstalment struct {
+	Instalment int         `json:"instalment"`
+	Due        time.Time   `json:"due"`
+	Estimated  money.Cents `json:"estimated"`
+	Paid       money.Cents `json:"paid"`
+	PaidOn     *time.Time  `json:"paid_on,omitempty"` // the latest payment recorded
+}
+
+// Expected is what the instalment comes to: the amount paid once recorded,
+// the estimate until then.
+func (i PaymentOnAccountInstalment) Expected() tax.Instalment {
+	if i.PaidOn != nil {
+		return tax.Instalment{Amount: i.Paid, Paid: true}
+	}
+	return tax.Instalment{Amount: i.Estimated}
+}
+
+// PaymentsOnAccountReport is a year's payments on account: the estimate
+// from the basis year and the payments recorded against it.
+type PaymentsOnAccountReport struct {
+	Year  int                       `json:"year"`
+	Basis tax.PaymentOnAccountBasis `json:"basis"`
+
+	Instalments    []PaymentOnAccountInstalment `json:"instalments"`
+	TotalEstimated money.Cents                  `json:"total_estimated"`
+	TotalPaid      money.Cents                  `json:"total_paid"`
+	TotalExpected  money.Cents                  `json:"total_expected"` // paid where recorded, estimated otherwise
+
+	Payments []*PaymentOnAccount `json:"payments"`
+}
+
+// BuildPaymentsOnAccountReport lays the year's recorded payments against the
+// three estimated instalments.
+func BuildPaymentsOnAccountReport(year int, basis tax.PaymentOnAccountBasis, estimate [3]money.Cents, payments []*PaymentOnAccount, loc *time.Location) *PaymentsOnAccountReport {
+	report := &PaymentsOnAccountReport{Year: year, Basis: basis, Payments: payments}
+	if report.Payments == nil {
+		report.Payments = []*PaymentOnAccount{}
+	}
+
+	for i, amount := range estimate {
+		report.Instalments = append(report.Instalments, PaymentOnAccountInstalment{
+			Instalment: i + 1,
+			Due:        tax.PaymentOnAccountDue(year, i+1, loc),
+			Estimated:  amount,
+		})
+		report.TotalEstimated += amount
+	}
+	for _, p := range payments {
+		if p.FiscalYear != year || p.Instalment < 1 || p.Instalment > 3 {
+			continue
+		}
+		inst := &report.Instalments[p.Instalment-1]
+		inst.Paid += p.AmountCents
+		if inst.PaidOn == nil || p.PaidOn.After(*inst.PaidOn) {
+			paidOn := p.PaidOn
+			inst.PaidOn = &paidOn
+		}
+		report.TotalPaid += p.AmountCents
+	}
+	for _, inst := range report.Instalments {
+		report.TotalExpected += inst.Expected().Amount
+	}
+	return report
+}
+
+// Schedule is the year's three instalments as they are expected to be paid.
+func (r *PaymentsOnAccountReport) Schedule() [3]tax.Instalment {
+	var schedule [3]tax.Instalment
+	for i, inst := range r.Instalments {
+		schedule[i] = inst.Expected()
+	}
+	return schedule
+}
+
+// AddPaymentsOnAccount puts each instalment on the projection for the month
+// it falls due in.
+func AddPaymentsOnAccount(projections []Projection, report *PaymentsOnAccountReport) {
+	for _, inst := range report.Instalments {
+		month := inst.Due.Format("2006-01")
+		for i := range projections {
+			if projections[i].Month == month {
+				projections[i].PaymentOnAccount += inst.Expected().Amount
+			}
+		}
+	}
+}
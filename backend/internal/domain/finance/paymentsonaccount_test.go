@@ -0,0 +1,70 @@
+package finance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+func TestBuildPaymentsOnAccountReport(t *testing.T) {
+	estimate := [3]money.Cents{money.FromEuros(500), money.FromEuros(500), money.FromEuros(500)}
+	payments := []*PaymentOnAccount{
+		{FiscalYear: 2026, Instalment: 1, AmountCents: money.FromEuros(480), PaidOn: time.Date(2026, 7, 18, 0, 0, 0, 0, time.UTC)},
+		{FiscalYear: 2025, Instalment: 2, AmountCents: money.FromEuros(999), PaidOn: time.Date(2025, 9, 18, 0, 0, 0, 0, time.UTC)},
+	}
+
+	report := BuildPaymentsOnAccountReport(2026, tax.PaymentOnAccountBasis{FiscalYear: 2024}, estimate, payments, time.UTC)
+
+	if report.TotalEstimated != money.FromEuros(1500) || report.TotalPaid != money.FromEuros(480) {
+		t.Errorf("expected 1500 estimated and 480 paid, got %d and %d", report.TotalEstimated, report.TotalPaid)
+	}
+	// The first instalment as paid and the other two as estimated
+	if want := money.FromEuros(1480); report.TotalExpected != want {
+		t.Errorf("expected %d in all, got %d", want, report.TotalExpected)
+	}
+	schedule := report.Schedule()
+	if !schedule[0].Paid || schedule[0].Amount != money.FromEuros(480) || schedule[1].Paid || schedule[1].Amount != money.FromEuros(500) {
+		t.Errorf("unexpected schedule %+v", schedule)
+	}
+	if due := report.Instalments[2].Due; !due.Equal(time.Date(2026, 12, 20, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the last instalment due 20 December, got %s", due)
+	}
+
+	projections := []Projection{{Month: "2026-06"}, {Month: "2026-07"}, {Month: "2026-09"}}
+	AddPaymentsOnAccount(projections, report)
+	if projections[0].PaymentOnAccount != 0 || projections[1].PaymentOnAccount != money.FromEuros(480) || projections[2].PaymentOnAccount != money.FromEuros(500) {
+		t.Errorf("unexpected projections %+v", projections)
+	}
+}
+
+func TestGetPaymentsOnAccount_SplitsByTaxableIncome(t *testing.T) {
+	repo := &mockSettlementRepo{
+		gross: money.FromEuros(30000),
+		salaries: []*SalaryRecord{
+			{GrossCents: money.FromEuros(10000), WithholdingCents: money.FromEuros(1200), SocialSecurityCents: money.FromEuros(1100)},
+		},
+	}
+	svc := NewService(repo, nil, nil, &mockUserRepo{}, tax.DefaultRegistry())
+
+	report, err := svc.GetPaymentsOnAccount(context.Background(), uuid.New(), 2027)
+	if err != nil {
+		t.Fatalf("GetPaymentsOnAccount failed: %v", err)
+	}
+
+	income, _ := svc.TaxIncome(context.Background(), uuid.New(), 2025)
+	settlement := tax.NewPortugalEngine().SimulateSettlement(tax.ConfigForYear(2025), tax.SettlementInput{Income: income})
+	basis := report.Basis
+	if basis.CategoryBIncome != settlement.AnexoB.TaxableIncome || basis.TotalIncome != settlement.TaxableIncome {
+		t.Errorf("expected the taxable incomes %d of %d, got %d of %d",
+			settlement.AnexoB.TaxableIncome, settlement.TaxableIncome, basis.CategoryBIncome, basis.TotalIncome)
+	}
+	// Category B with no expenses justified, and the salary less its 4,104
+	// EUR deduction, rather than 30,000 of 40,000 EUR gross
+	if basis.CategoryBIncome != money.FromEuros(27000) || basis.TotalIncome != money.FromEuros(32896) {
+		t.Errorf("expected 27000 of 32896 EUR taxable, got %d of %d", basis.CategoryBIncome, basis.TotalIncome)
+	}
+}
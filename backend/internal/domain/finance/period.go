@@ -0,0 +1,19 @@
+package finance
+
+import "time"
+
+// MonthBounds returns the half-open interval [start, end) covering a calendar
+// month in loc. Shifts are stored in UTC, so a night shift starting at 23:00
+// on 31 March in Lisbon summer time (22:00 UTC) only lands in March when the
+// boundary is drawn at local midnight.
+func MonthBounds(year int, month time.Month, loc *time.Location) (time.Time, time.Time) {
+	start := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// YearBounds returns the half-open interval [start, end) covering a calendar
+// year in loc.
+func YearBounds(year int, loc *time.Location) (time.Time, time.Time) {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	return start, start.AddDate(1, 0, 0)
+}
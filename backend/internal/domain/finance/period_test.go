@@ -0,0 +1,174 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/auth"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+)
+
+// mockPeriodRepo records the bounds the service asks for.
+type mockPeriodRepo struct {
+	Repository
+	start, end time.Time
+	loc        *time.Location
+}
+
+func (m *mockPeriodRepo) GetEarningsSummary(_ context.Context, _ uuid.UUID, start, end time.Time) (*EarningsSummary, error) {
+	m.start, m.end = start, end
+	return &EarningsSummary{Period: start.Format("2006-01")}, nil
+}
+
+func (m *mockPeriodRepo) GetYearlyEarnings(_ context.Context, _ uuid.UUID, year int, loc *time.Location) (*EarningsSummary, error) {
+	m.loc = loc
+	m.start, m.end = YearBounds(year, loc)
+	return &EarningsSummary{}, nil
+}
+
+func (m *mockPeriodRepo) ListExpenses(_ context.Context, _ uuid.UUID, start, end time.Time) ([]*Expense, error) {
+	m.start, m.end = start, end
+	return nil, nil
+}
+
+type mockUserRepo struct {
+	auth.Repository
+	user  *auth.User
+	err   error
+	loads int
+}
+
+func (m *mockUserRepo) GetUserByID(_ context.Context, _ uuid.UUID) (*auth.User, error) {
+	m.loads++
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.user == nil {
+		return nil, auth.ErrUserNotFound
+	}
+	return m.user, nil
+}
+
+func newPeriodTestService(timezone string) (*Service, *mockPeriodRepo) {
+	repo := &mockPeriodRepo{}
+	users := &mockUserRepo{}
+	if timezone != "" {
+		users.user = &auth.User{ID: uuid.New(), Timezone: timezone}
+	}
+	return NewService(repo, nil, nil, users, tax.DefaultRegistry()), repo
+}
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("timezone %s not available: %v", name, err)
+	}
+	return loc
+}
+
+func inPeriod(ts, start, end time.Time) bool {
+	return !ts.Before(start) && ts.Before(end)
+}
+
+func TestGetMonthlySummary_LisbonNightShiftStaysInMarch(t *testing.T) {
+	mustLoad(t, "Europe/Lisbon")
+	svc, repo := newPeriodTestService("Europe/Lisbon")
+
+	// 23:00 on 31 March in Lisbon summer time is 22:00 UTC
+	shiftStart := time.Date(2026, 3, 31, 22, 0, 0, 0, time.UTC)
+
+	if _, err := svc.GetMonthlySummary(context.Background(), uuid.New(), 2026, 3); err != nil {
+		t.Fatalf("GetMonthlySummary returned unexpected error: %v", err)
+	}
+	if !repo.end.Equal(time.Date(2026, 3, 31, 23, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected March to end at 23:00 UTC, got %v", repo.end.UTC())
+	}
+	if !inPeriod(shiftStart, repo.start, repo.end) {
+		t.Error("expected the 23:00 shift to be booked to March")
+	}
+
+	if _, err := svc.GetMonthlySummary(context.Background(), uuid.New(), 2026, 4); err != nil {
+		t.Fatalf("GetMonthlySummary returned unexpected error: %v", err)
+	}
+	if inPeriod(shiftStart, repo.start, repo.end) {
+		t.Error("expected the 23:00 shift not to be booked to April")
+	}
+}
+
+func TestGetMonthlySummary_AzoresBoundaries(t *testing.T) {
+	mustLoad(t, "Atlantic/Azores")
+	svc, repo := newPeriodTestService("Atlantic/Azores")
+
+	if _, err := svc.GetMonthlySummary(context.Background(), uuid.New(), 2026, 4); err != nil {
+		t.Fatalf("GetMonthlySummary returned unexpected error: %v", err)
+	}
+	// Azores summer time is UTC+0, an hour behind Lisbon
+	if !repo.start.Equal(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected April to start at 00:00 UTC, got %v", repo.start.UTC())
+	}
+	// Winter time (UTC-1) resumes on 25 October
+	if _, err := svc.GetMonthlySummary(context.Background(), uuid.New(), 2026, 11); err != nil {
+		t.Fatalf("GetMonthlySummary returned unexpected error: %v", err)
+	}
+	if !repo.start.Equal(time.Date(2026, 11, 1, 1, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected November to start at 01:00 UTC, got %v", repo.start.UTC())
+	}
+	if !repo.end.Equal(time.Date(2026, 12, 1, 1, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected November to end at 01:00 UTC on 1 December, got %v", repo.end.UTC())
+	}
+}
+
+func TestGetYearlySummary_UsesUserTimezone(t *testing.T) {
+	azores := mustLoad(t, "Atlantic/Azores")
+	svc, repo := newPeriodTestService("Atlantic/Azores")
+
+	if _, err := svc.GetYearlySummary(context.Background(), uuid.New(), 2026); err != nil {
+		t.Fatalf("GetYearlySummary returned unexpected error: %v", err)
+	}
+	if repo.loc.String() != azores.String() {
+		t.Errorf("expected Atlantic/Azores, got %v", repo.loc)
+	}
+	if !repo.start.Equal(time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the year to start at 01:00 UTC, got %v", repo.start.UTC())
+	}
+}
+
+func TestGetMonthlySummary_DefaultsToLisbon(t *testing.T) {
+	mustLoad(t, auth.DefaultTimezone)
+	svc, repo := newPeriodTestService("")
+
+	if _, err := svc.GetMonthlySummary(context.Background(), uuid.New(), 2026, 7); err != nil {
+		t.Fatalf("GetMonthlySummary returned unexpected error: %v", err)
+	}
+	if !repo.start.Equal(time.Date(2026, 6, 30, 23, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected July to start at 23:00 UTC on 30 June, got %v", repo.start.UTC())
+	}
+}
+
+func TestGetExpenseReport_UsesUserTimezone(t *testing.T) {
+	mustLoad(t, "Atlantic/Azores")
+	svc, repo := newPeriodTestService("Atlantic/Azores")
+
+	if _, err := svc.GetExpenseReport(context.Background(), uuid.New(), 2026); err != nil {
+		t.Fatalf("GetExpenseReport returned unexpected error: %v", err)
+	}
+	if !repo.start.Equal(time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the year to start at 01:00 UTC, got %v", repo.start.UTC())
+	}
+	if !repo.end.Equal(time.Date(2027, 1, 1, 1, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the year to end at 01:00 UTC, got %v", repo.end.UTC())
+	}
+}
+
+func TestGetMonthlySummary_UserLoadError(t *testing.T) {
+	failure := errors.New("connection refused")
+	svc := NewService(&mockPeriodRepo{}, nil, nil, &mockUserRepo{err: failure}, tax.DefaultRegistry())
+
+	if _, err := svc.GetMonthlySummary(context.Background(), uuid.New(), 2026, 3); !errors.Is(err, failure) {
+		t.Errorf("expected the load error, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package finance
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/workplace"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+// Night work is 22:00-07:00 unless a collective agreement says otherwise
+// (art. 223 Código do Trabalho).
+const (
+	nightStartHour = 22
+	nightEndHour   = 7
+)
+
+// EarningSegment is a shift earning segment clipped to a reporting period,
+// with its hours and amount pro-rated to the part inside the period.
+type EarningSegment struct {
+	ShiftID      uuid.UUID
+	WorkplaceID  uuid.UUID
+	ShiftStart   time.Time
+	PatientsSeen int
+
+	Start  time.Time
+	End    time.Time
+	Hours  float64
+	Amount money.Cents
+}
+
+type WorkplaceProfitability struct {
+	Rank           int         `json:"rank"`
+	WorkplaceID    uuid.UUID   `json:"workplace_id"`
+	WorkplaceName  string      `json:"workplace_name"`
+	CommuteMinutes int         `json:"commute_minutes"`
+	ShiftCount     int         `json:"shift_count"`
+	Hours          float64     `json:"hours"`
+	CommuteHours   float64     `json:"commute_hours"` // round trips for every shift
+	Gross          money.Cents `json:"gross"`
+	Withholding    money.Cents `json:"withholding"`
+	Expenses       money.Cents `json:"expenses"` // expenses assigned to this workplace
+	Net            money.Cents `json:"net"`
+
+	GrossHourlyRate     money.Cents `json:"gross_hourly_rate"`
+	NetHourlyRate       money.Cents `json:"net_hourly_rate"`
+	CommuteAdjustedRate money.Cents `json:"commute_adjusted_rate"` // net per hour worked or travelling
+	NightShare          float64     `json:"night_share"`           // fraction of hours between 22:00 and 07:00
+	WeekendShare        float64     `json:"weekend_share"`         // fraction of hours on Saturday or Sunday
+	PatientsSeen        int         `json:"patients_seen"`
+	PatientsPerHour     float64     `json:"patients_per_hour"`
+}
+
+type ProfitabilityReport struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	Workplaces          []WorkplaceProfitability `json:"workplaces"`
+	UnallocatedExpenses money.Cents              `json:"unallocated_expenses"` // expenses not tied to a workplace
+}
+
+// BuildProfitabilityReport ranks workplaces by what an hour there is really
+// worth: gross less withholding and the workplace's own expenses, spread over
+// the hours worked plus the commute to every shift. Night and weekend hours
+// are classified in loc. Patients count toward the period the shift starts in.
+// dispensed is whether the withholding dispensation still covers the period.
+func BuildProfitabilityReport(start, end time.Time, segments []EarningSegment, expenses []*Expense, workplaces map[uuid.UUID]*workplace.Workplace, engine tax.Engine, config tax.YearConfig, dispensed bool, loc *time.Location) *ProfitabilityReport {
+	report := &ProfitabilityReport{Start: start, End: end}
+
+	byID := make(map[uuid.UUID]*WorkplaceProfitability)
+	entry := func(id uuid.UUID) *WorkplaceProfitability {
+		if wp, ok := byID[id]; ok {
+			return wp
+		}
+		wp := &WorkplaceProfitability{WorkplaceID: id}
+		if w, ok := workplaces[id]; ok {
+			wp.WorkplaceName = w.Name
+			wp.CommuteMinutes = w.CommuteMinutes
+		}
+		byID[id] = wp
+		return wp
+	}
+
+	nightHours := make(map[uuid.UUID]float64)
+	weekendHours := make(map[uuid.UUID]float64)
+	seen := make(map[uuid.UUID]bool)
+	for _, seg := range segments {
+		wp := entry(seg.WorkplaceID)
+		wp.Gross += seg.Amount
+		wp.Hours += seg.Hours
+
+		if span := seg.End.Sub(seg.Start); span > 0 {
+			night, weekend := classifyHours(seg.Start.In(loc), seg.End.In(loc))
+			nightHours[seg.WorkplaceID] += seg.Hours * float64(night) / float64(span)
+			weekendHours[seg.WorkplaceID] += seg.Hours * float64(weekend) / float64(span)
+		}
+
+		if !seen[seg.ShiftID] {
+			seen[seg.ShiftID] = true
+			wp.ShiftCount++
+			if !seg.ShiftStart.Before(start) && seg.ShiftStart.Before(end) {
+				wp.PatientsSeen += seg.PatientsSeen
+			}
+		}
+	}
+
+	for _, e := range expenses {
+		if e.WorkplaceID == nil {
+			report.UnallocatedExpenses += e.TotalCents()
+			continue
+		}
+		entry(*e.WorkplaceID).Expenses += e.TotalCents()
+	}
+
+	for id, wp := range byID {
+		rate := tax.WithholdingRate(config, payerOf(workplaces[id]), dispensed)
+		wp.Withholding = engine.CalculateWithholding(wp.Gross, rate)
+		wp.Net = wp.Gross - wp.Withholding - wp.Expenses
+		wp.CommuteHours = float64(wp.ShiftCount*2*wp.CommuteMinutes) / 60
+
+		wp.GrossHourlyRate = hourlyRate(wp.Gross, wp.Hours)
+		wp.NetHourlyRate = hourlyRate(wp.Net, wp.Hours)
+		wp.CommuteAdjustedRate = hourlyRate(wp.Net, wp.Hours+wp.CommuteHours)
+		if wp.Hours > 0 {
+			wp.NightShare = nightHours[id] / wp.Hours
+			wp.WeekendShare = weekendHours[id] / wp.Hours
+			wp.PatientsPerHour = float64(wp.PatientsSeen) / wp.Hours
+		}
+		report.Workplaces = append(report.Workplaces, *wp)
+	}
+
+	sort.Slice(report.Workplaces, func(i, j int) bool {
+		a, b := report.Workplaces[i], report.Workplaces[j]
+		if a.CommuteAdjustedRate != b.CommuteAdjustedRate {
+			return a.CommuteAdjustedRate > b.CommuteAdjustedRate
+		}
+		return a.WorkplaceName < b.WorkplaceName
+	})
+	for i := range report.Workplaces {
+		report.Workplaces[i].Rank = i + 1
+	}
+
+	return report
+}
+
+// classifyHours returns how much of [start, end) falls in the night window and
+// on a weekend. Both times must already be in the location to classify in.
+func classifyHours(start, end time.Time) (night, weekend time.Duration) {
+	loc := start.Location()
+	for day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc); day.Before(end); day = day.AddDate(0, 0, 1) {
+		next := day.AddDate(0, 0, 1)
+		if wd := day.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			weekend += overlap(start, end, day, next)
+		}
+		night += overlap(start, end, day, time.Date(day.Year(), day.Month(), day.Day(), nightEndHour, 0, 0, 0, loc))
+		night += overlap(start, end, time.Date(day.Year(), day.Month(), day.Day(), nightStartHour, 0, 0, 0, loc), next)
+	}
+	return night, weekend
+}
+
+func overlap(start, end, from, to time.Time) time.Duration {
+	if from.Before(start) {
+		from = start
+	}
+	if to.After(end) {
+		to = end
+	}
+	if !to.After(from) {
+		return 0
+	}
+	return to.Sub(from)
+}
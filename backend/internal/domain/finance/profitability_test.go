@@ -0,0 +1,76 @@
+package finance
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/workplace"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+func TestBuildProfitabilityReport(t *testing.T) {
+	lisbon, err := time.LoadLocation("Europe/Lisbon")
+	if err != nil {
+		t.Skip("Europe/Lisbon timezone unavailable")
+	}
+	at := func(day, hour int) time.Time {
+		return time.Date(2026, 3, day, hour, 0, 0, 0, lisbon)
+	}
+
+	hospital := &workplace.Workplace{ID: uuid.New(), Name: "Hospital", WithholdingRate: agreedRate(0.25), CommuteMinutes: 60}
+	clinic := &workplace.Workplace{ID: uuid.New(), Name: "Clinic", WithholdingRate: agreedRate(0.25)}
+	workplaces := map[uuid.UUID]*workplace.Workplace{hospital.ID: hospital, clinic.ID: clinic}
+
+	// Saturday night shift at the hospital, 20:00-08:00, and a weekday morning at the clinic
+	night := uuid.New()
+	segments := []EarningSegment{
+		{ShiftID: night, WorkplaceID: hospital.ID, ShiftStart: at(7, 20), PatientsSeen: 18, Start: at(7, 20), End: at(8, 0), Hours: 4, Amount: 12000},
+		{ShiftID: night, WorkplaceID: hospital.ID, ShiftStart: at(7, 20), PatientsSeen: 18, Start: at(8, 0), End: at(8, 8), Hours: 8, Amount: 36000},
+		{ShiftID: uuid.New(), WorkplaceID: clinic.ID, ShiftStart: at(4, 9), PatientsSeen: 10, Start: at(4, 9), End: at(4, 13), Hours: 4, Amount: 20000},
+	}
+	expenses := []*Expense{
+		{WorkplaceID: &hospital.ID, AmountCents: 6000},
+		{AmountCents: 1000},
+	}
+
+	report := BuildProfitabilityReport(at(1, 0), at(31, 0), segments, expenses, workplaces, tax.NewPortugalEngine(), tax.ConfigForYear(2026), false, lisbon)
+
+	if len(report.Workplaces) != 2 {
+		t.Fatalf("expected 2 workplaces, got %d", len(report.Workplaces))
+	}
+	first, second := report.Workplaces[0], report.Workplaces[1]
+	if first.WorkplaceID != clinic.ID || first.Rank != 1 || second.Rank != 2 {
+		t.Fatalf("expected clinic ranked first, got %+v", report.Workplaces)
+	}
+	if first.CommuteAdjustedRate != money.Cents(3750) {
+		t.Errorf("expected clinic rate 3750, got %d", first.CommuteAdjustedRate)
+	}
+
+	if second.Gross != 48000 || second.Net != 30000 || second.ShiftCount != 1 {
+		t.Errorf("unexpected hospital totals %+v", second)
+	}
+	if second.GrossHourlyRate != 4000 || second.NetHourlyRate != 2500 {
+		t.Errorf("expected hospital rates 4000/2500, got %d/%d", second.GrossHourlyRate, second.NetHourlyRate)
+	}
+	// 30000 over 12 hours worked plus a 2-hour round trip
+	if second.CommuteHours != 2 || second.CommuteAdjustedRate != money.Cents(2143) {
+		t.Errorf("expected commute-adjusted rate 2143, got %d", second.CommuteAdjustedRate)
+	}
+	// 22:00-07:00 is 9 of 12 hours; the whole shift falls on the weekend
+	if math.Abs(second.NightShare-0.75) > 1e-9 || math.Abs(second.WeekendShare-1) > 1e-9 {
+		t.Errorf("expected night share 0.75 and weekend share 1, got %v and %v", second.NightShare, second.WeekendShare)
+	}
+	if second.PatientsSeen != 18 || second.PatientsPerHour != 1.5 {
+		t.Errorf("expected 18 patients at 1.5 per hour, got %d at %v", second.PatientsSeen, second.PatientsPerHour)
+	}
+	if first.NightShare != 0 || first.WeekendShare != 0 {
+		t.Errorf("expected no night or weekend hours at the clinic, got %+v", first)
+	}
+
+	if report.UnallocatedExpenses != 1000 {
+		t.Errorf("expected 1000 unallocated expenses, got %d", report.UnallocatedExpenses)
+	}
+}
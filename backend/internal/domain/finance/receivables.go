@@ -0,0 +1,157 @@
+package finance
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/workplace"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+// AgingBuckets splits outstanding net amounts by how many days past the due
+// date they are.
+type AgingBuckets struct {
+	Current    money.Cents `json:"current"`
+	Days1To30  money.Cents `json:"days_1_30"`
+	Days31To60 money.Cents `json:"days_31_60"`
+	Days61To90 money.Cents `json:"days_61_90"`
+	Over90     money.Cents `json:"over_90"`
+}
+
+func (b *AgingBuckets) add(daysOverdue int, amount money.Cents) {
+	switch {
+	case daysOverdue <= 0:
+		b.Current += amount
+	case daysOverdue <= 30:
+		b.Days1To30 += amount
+	case daysOverdue <= 60:
+		b.Days31To60 += amount
+	case daysOverdue <= 90:
+		b.Days61To90 += amount
+	default:
+		b.Over90 += amount
+	}
+}
+
+type WorkplaceReceivables struct {
+	WorkplaceID      uuid.UUID    `json:"workplace_id"`
+	WorkplaceName    string       `json:"workplace_name"`
+	PaymentTermsDays int          `json:"payment_terms_days"`
+	Outstanding      money.Cents  `json:"outstanding"`
+	Buckets          AgingBuckets `json:"buckets"`
+
+	// Payment history, from invoices that have been paid
+	PaidInvoiceCount int      `json:"paid_invoice_count"`
+	AvgDaysToPay     *float64 `json:"avg_days_to_pay,omitempty"`
+}
+
+type OverdueInvoice struct {
+	Invoice       *Invoice  `json:"invoice"`
+	WorkplaceName string    `json:"workplace_name"`
+	DueAt         time.Time `json:"due_at"`
+	DaysOverdue   int       `json:"days_overdue"`
+}
+
+type ReceivablesReport struct {
+	AsOf             time.Time              `json:"as_of"`
+	TotalOutstanding money.Cents            `json:"total_outstanding"`
+	Buckets          AgingBuckets           `json:"buckets"`
+	ByWorkplace      []WorkplaceReceivables `json:"by_workplace"`
+	Overdue          []OverdueInvoice       `json:"overdue"`
+}
+
+// BuildReceivablesReport ages unpaid invoices against their due date as of asOf
+// and summarises how long each workplace has historically taken to pay.
+func BuildReceivablesReport(asOf time.Time, unpaid, paid []*Invoice, workplaces map[uuid.UUID]*workplace.Workplace) *ReceivablesReport {
+	asOf = truncateToDay(asOf)
+	report := &ReceivablesReport{AsOf: asOf}
+
+	byWorkplace := make(map[uuid.UUID]*WorkplaceReceivables)
+	entry := func(id uuid.UUID) *WorkplaceReceivables {
+		if wr, ok := byWorkplace[id]; ok {
+			return wr
+		}
+		wr := &WorkplaceReceivables{WorkplaceID: id, PaymentTermsDays: workplace.DefaultPaymentTermsDays}
+		if wp, ok := workplaces[id]; ok {
+			wr.WorkplaceName = wp.Name
+			wr.PaymentTermsDays = wp.PaymentTermsDays
+		}
+		byWorkplace[id] = wr
+		return wr
+	}
+
+	for _, inv := range unpaid {
+		wr := entry(inv.WorkplaceID)
+		dueAt := invoiceDueDate(inv, wr.PaymentTermsDays)
+		daysOverdue := daysBetween(dueAt, asOf)
+
+		wr.Outstanding += inv.NetAmountCents
+		wr.Buckets.add(daysOverdue, inv.NetAmountCents)
+		report.TotalOutstanding += inv.NetAmountCents
+		report.Buckets.add(daysOverdue, inv.NetAmountCents)
+
+		if daysOverdue > 0 {
+			report.Overdue = append(report.Overdue, OverdueInvoice{
+				Invoice:       inv,
+				WorkplaceName: wr.WorkplaceName,
+				DueAt:         dueAt,
+				DaysOverdue:   daysOverdue,
+			})
+		}
+	}
+
+	totalDays := make(map[uuid.UUID]int)
+	for _, inv := range paid {
+		if inv.PaidAt == nil {
+			continue
+		}
+		wr := entry(inv.WorkplaceID)
+		wr.PaidInvoiceCount++
+		totalDays[inv.WorkplaceID] += daysBetween(invoiceIssueDate(inv), truncateToDay(*inv.PaidAt))
+	}
+	for id, days := range totalDays {
+		wr := byWorkplace[id]
+		avg := float64(days) / float64(wr.PaidInvoiceCount)
+		wr.AvgDaysToPay = &avg
+	}
+
+	for _, wr := range byWorkplace {
+		report.ByWorkplace = append(report.ByWorkplace, *wr)
+	}
+	sort.Slice(report.ByWorkplace, func(i, j int) bool {
+		if report.ByWorkplace[i].Outstanding != report.ByWorkplace[j].Outstanding {
+			return report.ByWorkplace[i].Outstanding > report.ByWorkplace[j].Outstanding
+		}
+		return report.ByWorkplace[i].WorkplaceName < report.ByWorkplace[j].WorkplaceName
+	})
+	sort.Slice(report.Overdue, func(i, j int) bool {
+		return report.Overdue[i].DaysOverdue > report.Overdue[j].DaysOverdue
+	})
+
+	return report
+}
+
+// invoiceIssueDate falls back to the end of the billed period for invoices
+// recorded without an issue date.
+func invoiceIssueDate(inv *Invoice) time.Time {
+	if inv.IssuedAt != nil {
+		return truncateToDay(*inv.IssuedAt)
+	}
+	return truncateToDay(inv.PeriodEnd)
+}
+
+func invoiceDueDate(inv *Invoice, termsDays int) time.Time {
+	if inv.DueAt != nil {
+		return truncateToDay(*inv.DueAt)
+	}
+	return invoiceIssueDate(inv).AddDate(0, 0, termsDays)
+}
+
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
@@ -0,0 +1,65 @@
+package finance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/workplace"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+func TestBuildReceivablesReport(t *testing.T) {
+	hospital := &workplace.Workplace{ID: uuid.New(), Name: "Hospital", PaymentTermsDays: 60}
+	clinic := &workplace.Workplace{ID: uuid.New(), Name: "Clinic", PaymentTermsDays: 30}
+	workplaces := map[uuid.UUID]*workplace.Workplace{hospital.ID: hospital, clinic.ID: clinic}
+
+	asOf := time.Date(2026, 6, 30, 15, 0, 0, 0, time.UTC)
+	day := func(m time.Month, d int) *time.Time {
+		t := time.Date(2026, m, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	unpaid := []*Invoice{
+		// Issued 2026-06-01, due 2026-07-31: current
+		{ID: uuid.New(), WorkplaceID: hospital.ID, NetAmountCents: 100000, IssuedAt: day(6, 1)},
+		// Issued 2026-02-01, due 2026-04-02: 89 days overdue
+		{ID: uuid.New(), WorkplaceID: hospital.ID, NetAmountCents: 50000, IssuedAt: day(2, 1)},
+		// Explicit due date 2026-06-10: 20 days overdue
+		{ID: uuid.New(), WorkplaceID: clinic.ID, NetAmountCents: 20000, IssuedAt: day(5, 1), DueAt: day(6, 10)},
+		// No issue date, period ended 2026-01-31, due 2026-03-02: 120 days overdue
+		{ID: uuid.New(), WorkplaceID: clinic.ID, NetAmountCents: 10000, PeriodEnd: *day(1, 31)},
+	}
+	paid := []*Invoice{
+		{ID: uuid.New(), WorkplaceID: hospital.ID, IssuedAt: day(1, 1), PaidAt: day(4, 1)},  // 90 days
+		{ID: uuid.New(), WorkplaceID: hospital.ID, IssuedAt: day(2, 1), PaidAt: day(3, 13)}, // 40 days
+	}
+
+	report := BuildReceivablesReport(asOf, unpaid, paid, workplaces)
+
+	if report.TotalOutstanding != money.Cents(180000) {
+		t.Errorf("expected 180000 outstanding, got %d", report.TotalOutstanding)
+	}
+	want := AgingBuckets{Current: 100000, Days1To30: 20000, Days61To90: 50000, Over90: 10000}
+	if report.Buckets != want {
+		t.Errorf("expected buckets %+v, got %+v", want, report.Buckets)
+	}
+
+	if len(report.ByWorkplace) != 2 || report.ByWorkplace[0].WorkplaceID != hospital.ID {
+		t.Fatalf("expected hospital first by outstanding, got %+v", report.ByWorkplace)
+	}
+	hosp := report.ByWorkplace[0]
+	if hosp.AvgDaysToPay == nil || *hosp.AvgDaysToPay != 65 {
+		t.Errorf("expected hospital to average 65 days to pay, got %v", hosp.AvgDaysToPay)
+	}
+	if report.ByWorkplace[1].AvgDaysToPay != nil {
+		t.Error("expected no payment history for clinic")
+	}
+
+	if len(report.Overdue) != 3 {
+		t.Fatalf("expected 3 overdue invoices, got %d", len(report.Overdue))
+	}
+	if report.Overdue[0].DaysOverdue != 120 || report.Overdue[0].WorkplaceName != "Clinic" {
+		t.Errorf("expected most overdue invoice first, got %+v", report.Overdue[0])
+	}
+}
@@ -0,0 +1,154 @@
+package finance
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/workplace"
+)
+
+// Match scoring weights. Payments outside the date window are never
+// proposed; inside it, an exact net amount or the invoice number is enough,
+// while a gross amount or the client NIF need corroborating evidence.
+const (
+	scoreNetAmount     = 50
+	scoreGrossAmount   = 30
+	scoreInvoiceNumber = 50
+	scoreNIF           = 30
+	scoreDateWindow    = 10
+	minMatchScore      = 60
+
+	// DefaultMatchLateDays is how long past its payment terms a payment is
+	// still considered for an invoice. Portuguese hospitals routinely pay
+	// 60-120 days late.
+	DefaultMatchLateDays = 150
+)
+
+// PaymentMatch proposes that a bank credit settles an open invoice.
+type PaymentMatch struct {
+	Transaction BankTransaction `json:"transaction"`
+	InvoiceID   uuid.UUID       `json:"invoice_id"`
+	Invoice     *Invoice        `json:"invoice"`
+	Score       int             `json:"score"`
+	Reasons     []string        `json:"reasons"`
+}
+
+// ReconciliationResult is the outcome of matching a statement against open invoices.
+// Nothing is persisted until the proposals are confirmed.
+type ReconciliationResult struct {
+	Matches   []PaymentMatch    `json:"matches"`
+	Unmatched []BankTransaction `json:"unmatched"`
+}
+
+type ConfirmPaymentInput struct {
+	InvoiceID uuid.UUID `json:"invoice_id" validate:"required"`
+	PaidAt    time.Time `json:"paid_at" validate:"required"`
+}
+
+// MatchPayments pairs bank credits with unpaid invoices. Every candidate pair is
+// scored on amount, invoice number, client NIF and date window; pairs are then
+// assigned greedily by score so each transaction and invoice is used at most once.
+// The window runs to lateDays past the workplace's payment terms.
+func MatchPayments(txs []BankTransaction, invoices []*Invoice, workplaces map[uuid.UUID]*workplace.Workplace, lateDays int) *ReconciliationResult {
+	if lateDays <= 0 {
+		lateDays = DefaultMatchLateDays
+	}
+
+	type candidate struct {
+		tx      int
+		inv     int
+		score   int
+		reasons []string
+	}
+
+	var candidates []candidate
+	for i, tx := range txs {
+		for j, inv := range invoices {
+			score, reasons := scorePayment(tx, inv, workplaces[inv.WorkplaceID], lateDays)
+			if score >= minMatchScore {
+				candidates = append(candidates, candidate{tx: i, inv: j, score: score, reasons: reasons})
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return candidates[a].score > candidates[b].score
+	})
+
+	usedTx := make(map[int]bool)
+	usedInv := make(map[int]bool)
+	result := &ReconciliationResult{}
+	for _, c := range candidates {
+		if usedTx[c.tx] || usedInv[c.inv] {
+			continue
+		}
+		usedTx[c.tx] = true
+		usedInv[c.inv] = true
+		result.Matches = append(result.Matches, PaymentMatch{
+			Transaction: txs[c.tx],
+			InvoiceID:   invoices[c.inv].ID,
+			Invoice:     invoices[c.inv],
+			Score:       c.score,
+			Reasons:     c.reasons,
+		})
+	}
+
+	for i, tx := range txs {
+		if !usedTx[i] {
+			result.Unmatched = append(result.Unmatched, tx)
+		}
+	}
+
+	return result
+}
+
+func scorePayment(tx BankTransaction, inv *Invoice, wp *workplace.Workplace, lateDays int) (int, []string) {
+	var score int
+	var reasons []string
+
+	switch tx.AmountCents {
+	case inv.NetAmountCents:
+		score += scoreNetAmount
+		reasons = append(reasons, "amount matches net invoice amount")
+	case inv.GrossAmountCents:
+		score += scoreGrossAmount
+		reasons = append(reasons, "amount matches gross invoice amount")
+	}
+
+	text := strings.ToUpper(tx.Description + " " + tx.Reference + " " + tx.Counterparty)
+	if inv.InvoiceNumber != nil && *inv.InvoiceNumber != "" && containsToken(text, strings.ToUpper(*inv.InvoiceNumber)) {
+		score += scoreInvoiceNumber
+		reasons = append(reasons, "invoice number found in description")
+	}
+	if wp != nil && wp.NIF != nil && *wp.NIF != "" && strings.Contains(text, *wp.NIF) {
+		score += scoreNIF
+		reasons = append(reasons, "client NIF found in description")
+	}
+
+	issued := inv.PeriodEnd
+	if inv.IssuedAt != nil {
+		issued = *inv.IssuedAt
+	}
+	terms := workplace.DefaultPaymentTermsDays
+	if wp != nil {
+		terms = wp.PaymentTermsDays
+	}
+	// Allow a week of slack before issue for invoices registered late.
+	if !tx.Date.Before(issued.AddDate(0, 0, -7)) && !tx.Date.After(issued.AddDate(0, 0, terms+lateDays)) {
+		score += scoreDateWindow
+		reasons = append(reasons, "payment date within expected window")
+	} else {
+		return 0, nil
+	}
+
+	return score, reasons
+}
+
+// containsToken reports whether token appears in text on its own, not as part
+// of a longer number or word: invoice 12 is not found in "FT 2026/120".
+func containsToken(text, token string) bool {
+	return regexp.MustCompile(`(^|[^\pL\pN])` + regexp.QuoteMeta(token) + `($|[^\pL\pN])`).MatchString(text)
+}
@@ -0,0 +1,290 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/schedule"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/workplace"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+func TestParseCSVStatement_PortugueseFormat(t *testing.T) {
+	csvData := `Extrato conta 0001
+Data Mov.;Descrição;Débito;Crédito
+03-02-2026;TRF HOSPITAL SANTA MARIA 500100144;;1.925,00
+04-02-2026;COMPRA SUPERMERCADO;45,10;
+05-02-2026;TRF CLINICA NORTE FT 2026/12;;770,50
+`
+	txs, err := ParseCSVStatement(strings.NewReader(csvData), CSVColumnMapping{
+		Date:         "Data Mov.",
+		Credit:       "Crédito",
+		Description:  "Descrição",
+		DecimalComma: true,
+		SkipRows:     1,
+	})
+	if err != nil {
+		t.Fatalf("ParseCSVStatement returned unexpected error: %v", err)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("expected 2 credit transactions, got %d", len(txs))
+	}
+	if txs[0].AmountCents != money.Cents(192500) {
+		t.Errorf("expected 192500 cents, got %d", txs[0].AmountCents)
+	}
+	if !txs[0].Date.Equal(time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected date %v", txs[0].Date)
+	}
+	if txs[1].AmountCents != money.Cents(77050) {
+		t.Errorf("expected 77050 cents, got %d", txs[1].AmountCents)
+	}
+}
+
+func TestParseCSVStatement_SignedAmountSkipsDebits(t *testing.T) {
+	csvData := `date,amount,memo
+2026-03-01,"1,200.00",Transfer in
+2026-03-02,-80.00,Card payment
+`
+	txs, err := ParseCSVStatement(strings.NewReader(csvData), CSVColumnMapping{
+		Date:        "date",
+		Amount:      "amount",
+		Description: "memo",
+		DateFormat:  "2006-01-02",
+		Delimiter:   ",",
+	})
+	if err != nil {
+		t.Fatalf("ParseCSVStatement returned unexpected error: %v", err)
+	}
+	if len(txs) != 1 {
+		t.Fatalf("expected 1 credit transaction, got %d", len(txs))
+	}
+	if txs[0].AmountCents != money.Cents(120000) {
+		t.Errorf("expected 120000 cents, got %d", txs[0].AmountCents)
+	}
+}
+
+func TestParseCSVStatement_MissingColumn(t *testing.T) {
+	_, err := ParseCSVStatement(strings.NewReader("a;b\n1;2\n"), CSVColumnMapping{
+		Date:        "date",
+		Amount:      "amount",
+		Description: "memo",
+	})
+	if err == nil {
+		t.Fatal("expected error for unknown column, got nil")
+	}
+}
+
+func TestParseCAMT053(t *testing.T) {
+	xmlData := `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt>
+    <Stmt>
+      <Ntry>
+        <Amt Ccy="EUR">1925.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <BookgDt><Dt>2026-02-03</Dt></BookgDt>
+        <AcctSvcrRef>BANKREF1</AcctSvcrRef>
+        <NtryDtls>
+          <TxDtls>
+            <Refs><EndToEndId>FT2026-7</EndToEndId></Refs>
+            <RltdPties><Dbtr><Nm>Hospital Santa Maria</Nm></Dbtr></RltdPties>
+            <RmtInf><Ustrd>PAGAMENTO FT 2026/7 NIF 500100144</Ustrd></RmtInf>
+          </TxDtls>
+        </NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="EUR">45.10</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <BookgDt><Dt>2026-02-04</Dt></BookgDt>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`
+
+	txs, err := ParseCAMT053(strings.NewReader(xmlData))
+	if err != nil {
+		t.Fatalf("ParseCAMT053 returned unexpected error: %v", err)
+	}
+	if len(txs) != 1 {
+		t.Fatalf("expected 1 credit entry, got %d", len(txs))
+	}
+	tx := txs[0]
+	if tx.AmountCents != money.Cents(192500) {
+		t.Errorf("expected 192500 cents, got %d", tx.AmountCents)
+	}
+	if tx.Counterparty != "Hospital Santa Maria" {
+		t.Errorf("expected debtor name as counterparty, got %q", tx.Counterparty)
+	}
+	if tx.Reference != "FT2026-7" {
+		t.Errorf("expected end-to-end id as reference, got %q", tx.Reference)
+	}
+	if !strings.Contains(tx.Description, "500100144") {
+		t.Errorf("expected remittance info in description, got %q", tx.Description)
+	}
+}
+
+func TestMatchPayments(t *testing.T) {
+	nif := "500100144"
+	hospital := &workplace.Workplace{ID: uuid.New(), Name: "Hospital Santa Maria", NIF: &nif}
+	clinic := &workplace.Workplace{ID: uuid.New(), Name: "Clinica Norte"}
+	workplaces := map[uuid.UUID]*workplace.Workplace{hospital.ID: hospital, clinic.ID: clinic}
+
+	issued := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	number := "FT 2026/12"
+	hospitalInv := &Invoice{
+		ID: uuid.New(), WorkplaceID: hospital.ID,
+		GrossAmountCents: 250000, WithholdingCents: 57500, NetAmountCents: 192500,
+		PeriodEnd: issued, IssuedAt: &issued,
+	}
+	clinicInv := &Invoice{
+		ID: uuid.New(), WorkplaceID: clinic.ID,
+		GrossAmountCents: 100000, WithholdingCents: 23000, NetAmountCents: 77000,
+		PeriodEnd: issued, IssuedAt: &issued, InvoiceNumber: &number,
+	}
+	staleInv := &Invoice{
+		ID: uuid.New(), WorkplaceID: clinic.ID,
+		GrossAmountCents: 50000, NetAmountCents: 38500,
+		PeriodEnd: issued.AddDate(-1, 0, 0),
+	}
+
+	txs := []BankTransaction{
+		{Date: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), AmountCents: 192500, Description: "TRF 500100144"},
+		{Date: time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), AmountCents: 77050, Description: "CLINICA NORTE FT 2026/12"},
+		{Date: time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), AmountCents: 38500, Description: "UNKNOWN"},
+	}
+
+	result := MatchPayments(txs, []*Invoice{hospitalInv, clinicInv, staleInv}, workplaces, 0)
+
+	if len(result.Matches) != 2 {
+		t.Fatalf("expected 2 matches, got %d", len(result.Matches))
+	}
+	got := make(map[uuid.UUID]money.Cents)
+	for _, m := range result.Matches {
+		got[m.InvoiceID] = m.Transaction.AmountCents
+	}
+	if got[hospitalInv.ID] != 192500 {
+		t.Errorf("expected hospital invoice matched by amount and NIF, got %v", got[hospitalInv.ID])
+	}
+	if got[clinicInv.ID] != 77050 {
+		t.Errorf("expected clinic invoice matched by invoice number, got %v", got[clinicInv.ID])
+	}
+	if _, ok := got[staleInv.ID]; ok {
+		t.Error("invoice outside the date window must not be matched")
+	}
+	if len(result.Unmatched) != 1 || result.Unmatched[0].AmountCents != 38500 {
+		t.Errorf("expected the stale-amount credit to stay unmatched, got %+v", result.Unmatched)
+	}
+}
+
+func TestMatchPayments_InvoiceNumberPrefix(t *testing.T) {
+	clinic := &workplace.Workplace{ID: uuid.New(), Name: "Clinica Norte", PaymentTermsDays: 30}
+	workplaces := map[uuid.UUID]*workplace.Workplace{clinic.ID: clinic}
+	issued := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	for _, number := range []string{"12", "FT 2026/12"} {
+		inv := &Invoice{
+			ID: uuid.New(), WorkplaceID: clinic.ID,
+			GrossAmountCents: 100000, NetAmountCents: 77000,
+			PeriodEnd: issued, IssuedAt: &issued, InvoiceNumber: &number,
+		}
+		tx := BankTransaction{Date: time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC), AmountCents: 12345, Description: "CLINICA NORTE FT 2026/120"}
+
+		if result := MatchPayments([]BankTransaction{tx}, []*Invoice{inv}, workplaces, 0); len(result.Matches) != 0 {
+			t.Errorf("invoice %q must not match a payment for FT 2026/120", number)
+		}
+		tx.Description = "CLINICA NORTE FT 2026/12."
+		if result := MatchPayments([]BankTransaction{tx}, []*Invoice{inv}, workplaces, 0); len(result.Matches) != 1 {
+			t.Errorf("expected invoice %q matched by its number", number)
+		}
+	}
+}
+
+func TestMatchPayments_WindowFollowsPaymentTerms(t *testing.T) {
+	hospital := &workplace.Workplace{ID: uuid.New(), Name: "Hospital Santa Maria", PaymentTermsDays: 90}
+	clinic := &workplace.Workplace{ID: uuid.New(), Name: "Clinica Norte", PaymentTermsDays: 30}
+	workplaces := map[uuid.UUID]*workplace.Workplace{hospital.ID: hospital, clinic.ID: clinic}
+	issued := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	hospitalInv := &Invoice{ID: uuid.New(), WorkplaceID: hospital.ID, NetAmountCents: 192500, PeriodEnd: issued, IssuedAt: &issued}
+	clinicInv := &Invoice{ID: uuid.New(), WorkplaceID: clinic.ID, NetAmountCents: 77000, PeriodEnd: issued, IssuedAt: &issued}
+	// 200 days after issue: within 90 + 150 days, past 30 + 150
+	paid := issued.AddDate(0, 0, 200)
+	txs := []BankTransaction{
+		{Date: paid, AmountCents: 192500, Description: "TRF"},
+		{Date: paid, AmountCents: 77000, Description: "TRF"},
+	}
+
+	result := MatchPayments(txs, []*Invoice{hospitalInv, clinicInv}, workplaces, 0)
+	if len(result.Matches) != 1 || result.Matches[0].InvoiceID != hospitalInv.ID {
+		t.Errorf("expected only the 90-day invoice matched, got %+v", result.Matches)
+	}
+}
+
+type mockPaymentRepo struct {
+	Repository
+	invoices map[uuid.UUID]*Invoice
+	paid     []*Invoice
+	shiftIDs []uuid.UUID
+}
+
+func (m *mockPaymentRepo) GetInvoiceByID(_ context.Context, id uuid.UUID) (*Invoice, error) {
+	if inv, ok := m.invoices[id]; ok {
+		return inv, nil
+	}
+	return nil, errors.New("no rows")
+}
+
+func (m *mockPaymentRepo) RecordPayments(_ context.Context, invoices []*Invoice, shiftIDs []uuid.UUID) error {
+	m.paid, m.shiftIDs = invoices, shiftIDs
+	return nil
+}
+
+type mockShiftRepo struct {
+	schedule.Repository
+	shifts []*schedule.Shift
+}
+
+func (m *mockShiftRepo) ListShifts(_ context.Context, _ schedule.ShiftFilter) ([]*schedule.Shift, error) {
+	return m.shifts, nil
+}
+
+func TestConfirmPayments_OnlyWorkedShiftsArePaid(t *testing.T) {
+	userID := uuid.New()
+	inv := &Invoice{ID: uuid.New(), UserID: userID, WorkplaceID: uuid.New()}
+	other := &Invoice{ID: uuid.New(), UserID: uuid.New()}
+	completed := &schedule.Shift{ID: uuid.New(), Status: schedule.ShiftStatusCompleted}
+	confirmed := &schedule.Shift{ID: uuid.New(), Status: schedule.ShiftStatusConfirmed}
+	shifts := &mockShiftRepo{shifts: []*schedule.Shift{
+		completed,
+		confirmed,
+		{ID: uuid.New(), Status: schedule.ShiftStatusCancelled},
+		{ID: uuid.New(), Status: schedule.ShiftStatusScheduled},
+	}}
+	repo := &mockPaymentRepo{invoices: map[uuid.UUID]*Invoice{inv.ID: inv, other.ID: other}}
+	svc := NewService(repo, nil, shifts, nil, nil)
+
+	paidAt := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	if _, err := svc.ConfirmPayments(context.Background(), userID, []ConfirmPaymentInput{{InvoiceID: inv.ID, PaidAt: paidAt}}); err != nil {
+		t.Fatalf("ConfirmPayments returned unexpected error: %v", err)
+	}
+	if len(repo.paid) != 1 || !repo.paid[0].PaidAt.Equal(paidAt) {
+		t.Fatalf("expected the invoice paid on %v, got %+v", paidAt, repo.paid)
+	}
+	if len(repo.shiftIDs) != 2 || repo.shiftIDs[0] != completed.ID || repo.shiftIDs[1] != confirmed.ID {
+		t.Errorf("expected only the completed and confirmed shifts paid, got %v", repo.shiftIDs)
+	}
+
+	// Another user's invoice fails the whole batch before anything is stored
+	repo.paid = nil
+	_, err := svc.ConfirmPayments(context.Background(), userID, []ConfirmPaymentInput{
+		{InvoiceID: inv.ID, PaidAt: paidAt},
+		{InvoiceID: other.ID, PaidAt: paidAt},
+	})
+	if !errors.Is(err, ErrInvoiceNotFound) || repo.paid != nil {
+		t.Errorf("expected ErrInvoiceNotFound with nothing recorded, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package finance
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/workplace"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+// AnnualReport gathers a year's figures for the accountant: earnings per
+// workplace and month, the invoices issued, Social Security, the IRS estimate
+// and expenses.
+type AnnualReport struct {
+	Year        int       `json:"year"`
+	Taxpayer    string    `json:"taxpayer"`
+	NIF         string    `json:"nif,omitempty"`
+	Residence   string    `json:"residence"` // name of the tax jurisdiction
+	Regime      string    `json:"regime"`
+	GeneratedAt time.Time `json:"generated_at"`
+
+	Earnings []WorkplaceYear `json:"earnings"`
+	Monthly  [12]money.Cents `json:"monthly"`
+	Total    money.Cents     `json:"total"`
+	Invoices []ReportInvoice `json:"invoices"`
+	Invoiced InvoiceTotals   `json:"invoiced"`
+
+	SocialSecurity *SocialSecurityReport `json:"social_security"`
+	Tax            *TaxEstimate          `json:"tax"`
+	Expenses       *ExpenseReport        `json:"expenses"`
+}
+
+// WorkplaceYear is one workplace's earnings in each month of the year.
+type WorkplaceYear struct {
+	WorkplaceID uuid.UUID       `json:"workplace_id"`
+	Name        string          `json:"name"`
+	Monthly     [12]money.Cents `json:"monthly"`
+	Total       money.Cents     `json:"total"`
+}
+
+// ReportInvoice is an invoice as listed in the report, with its workplace's
+// name.
+type ReportInvoice struct {
+	Number          string      `json:"number"`
+	Workplace       string      `json:"workplace"`
+	PeriodStart     time.Time   `json:"period_start"`
+	PeriodEnd       time.Time   `json:"period_end"`
+	IssuedAt        *time.Time  `json:"issued_at,omitempty"`
+	PaidAt          *time.Time  `json:"paid_at,omitempty"`
+	Gross           money.Cents `json:"gross"`
+	WithholdingRate float64     `json:"withholding_rate"`
+	Withholding     money.Cents `json:"withholding"`
+	IVA             money.Cents `json:"iva"`
+	Net             money.Cents `json:"net"`
+}
+
+type InvoiceTotals struct {
+	Gross       money.Cents `json:"gross"`
+	Withholding money.Cents `json:"withholding"`
+	IVA         money.Cents `json:"iva"`
+	Net         money.Cents `json:"net"`
+}
+
+// BuildAnnualReport lays out the year's monthly earnings by workplace, the
+// busiest first, and its invoices in the order of their periods. Only
+// invoices whose period starts in [start, end) are listed, as for the
+// withholding credited; those without a number are listed as drafts.
+func BuildAnnualReport(year int, start, end time.Time, monthly []EarningsSummary, invoices []*Invoice, workplaces map[uuid.UUID]*workplace.Workplace) *AnnualReport {
+	report := &AnnualReport{Year: year, Earnings: []WorkplaceYear{}, Invoices: []ReportInvoice{}}
+
+	byWorkplace := map[uuid.UUID]*WorkplaceYear{}
+	for _, summary := range monthly {
+		period, err := time.Parse("2006-01", summary.Period)
+		if err != nil || period.Year() != year {
+			continue
+		}
+		m := period.Month() - 1
+		for _, we := range summary.ByWorkplace {
+			wy, ok := byWorkplace[we.WorkplaceID]
+			if !ok {
+				wy = &WorkplaceYear{WorkplaceID: we.WorkplaceID, Name: we.WorkplaceName}
+				byWorkplace[we.WorkplaceID] = wy
+			}
+			wy.Monthly[m] += we.Gross
+			wy.Total += we.Gross
+			report.Monthly[m] += we.Gross
+			report.Total += we.Gross
+		}
+	}
+	for _, wy := range byWorkplace {
+		report.Earnings = append(report.Earnings, *wy)
+	}
+	sort.Slice(report.Earnings, func(i, j int) bool {
+		if report.Earnings[i].Total != report.Earnings[j].Total {
+			return report.Earnings[i].Total > report.Earnings[j].Total
+		}
+		return report.Earnings[i].Name < report.Earnings[j].Name
+	})
+
+	var sorted []*Invoice
+	for _, inv := range invoices {
+		if !inv.PeriodStart.Before(start) && inv.PeriodStart.Before(end) {
+			sorted = append(sorted, inv)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].PeriodStart.Before(sorted[j].PeriodStart)
+	})
+	for _, inv := range sorted {
+		line := ReportInvoice{
+			Number:          "draft",
+			PeriodStart:     inv.PeriodStart,
+			PeriodEnd:       inv.PeriodEnd,
+			IssuedAt:        inv.IssuedAt,
+			PaidAt:          inv.PaidAt,
+			Gross:           inv.GrossAmountCents,
+			WithholdingRate: inv.WithholdingRate,
+			Withholding:     inv.WithholdingCents,
+			IVA:             inv.IVACents,
+			Net:             inv.NetAmountCents,
+		}
+		if inv.InvoiceNumber != nil {
+			line.Number = *inv.InvoiceNumber
+		}
+		if wp := workplaces[inv.WorkplaceID]; wp != nil {
+			line.Workplace = wp.Name
+		}
+		report.Invoices = append(report.Invoices, line)
+
+		report.Invoiced.Gross += inv.GrossAmountCents
+		report.Invoiced.Withholding += inv.WithholdingCents
+		report.Invoiced.IVA += inv.IVACents
+		report.Invoiced.Net += inv.NetAmountCents
+	}
+	return report
+}
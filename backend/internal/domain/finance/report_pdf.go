@@ -0,0 +1,402 @@
+package finance
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+	"github.com/joao-moreira/doctor-tracker/pkg/pdf"
+)
+
+// Report layout, in points on a landscape A4 page.
+const (
+	reportMargin   = 36.0
+	reportFontSize = 8.0
+	reportRow      = 12.0
+)
+
+// reportColumn is a table column; amounts are aligned right.
+type reportColumn struct {
+	title string
+	width float64
+	right bool
+}
+
+// reportLayout places the report's blocks down the page, starting a new page
+// when one does not fit.
+type reportLayout struct {
+	doc   *pdf.Document
+	pages []*pdf.Page
+	page  *pdf.Page
+	y     float64
+}
+
+// WritePDF renders the report as a PDF for printing: a landscape A4 document
+// with a section for each part of the year, amounts in euros.
+func (r *AnnualReport) WritePDF(w io.Writer) error {
+	doc := pdf.New(fmt.Sprintf("Annual report %d", r.Year), pdf.A4Height, pdf.A4Width)
+	doc.Created = r.GeneratedAt
+	l := &reportLayout{doc: doc}
+	l.newPage()
+
+	l.title(fmt.Sprintf("Annual report %d", r.Year))
+	details := []string{r.Taxpayer}
+	if r.NIF != "" {
+		details = append(details, "NIF "+r.NIF)
+	}
+	details = append(details, "Tax residence: "+r.Residence, "Regime: "+r.Regime)
+	l.text(strings.Join(details, "  ·  "))
+	l.text(fmt.Sprintf("Generated on %s. Amounts in EUR. Figures for periods not yet ended are estimates.", r.GeneratedAt.Format("2006-01-02")))
+
+	r.writeEarnings(l)
+	r.writeInvoices(l)
+	if r.SocialSecurity != nil {
+		r.writeSocialSecurity(l)
+	}
+	if r.Tax != nil {
+		r.writeTax(l)
+	}
+	if r.Expenses != nil {
+		r.writeExpenses(l)
+	}
+
+	for i, p := range l.pages {
+		width, _ := doc.Size()
+		p.TextRight(width-reportMargin, reportMargin/2, pdf.Helvetica, 7, fmt.Sprintf("Page %d of %d", i+1, len(l.pages)))
+	}
+	return doc.Render(w)
+}
+
+func (r *AnnualReport) writeEarnings(l *reportLayout) {
+	l.heading("Earnings by workplace and month")
+
+	width, _ := l.doc.Size()
+	monthWidth := 50.0
+	cols := []reportColumn{{title: "Workplace", width: width - 2*reportMargin - 13*monthWidth - 6}}
+	for m := time.January; m <= time.December; m++ {
+		cols = append(cols, reportColumn{title: m.String()[:3], width: monthWidth, right: true})
+	}
+	cols = append(cols, reportColumn{title: "Total", width: monthWidth + 6, right: true})
+
+	rows := make([][]string, 0, len(r.Earnings))
+	for _, wy := range r.Earnings {
+		row := []string{wy.Name}
+		for _, c := range wy.Monthly {
+			row = append(row, reportAmount(c))
+		}
+		rows = append(rows, append(row, reportAmount(wy.Total)))
+	}
+	total := []string{"Total"}
+	for _, c := range r.Monthly {
+		total = append(total, reportAmount(c))
+	}
+	l.table(cols, rows, append(total, reportAmount(r.Total)))
+}
+
+func (r *AnnualReport) writeInvoices(l *reportLayout) {
+	l.heading("Invoices (recibos verdes)")
+	if len(r.Invoices) == 0 {
+		l.text("No invoices for the year.")
+		return
+	}
+
+	cols := []reportColumn{
+		{title: "Number", width: 110},
+		{title: "Workplace", width: 150},
+		{title: "Period", width: 110},
+		{title: "Issued", width: 60},
+		{title: "Paid", width: 60},
+		{title: "Gross", width: 60, right: true},
+		{title: "Rate", width: 40, right: true},
+		{title: "Withholding", width: 60, right: true},
+		{title: "IVA", width: 50, right: true},
+		{title: "Net", width: 60, right: true},
+	}
+	rows := make([][]string, 0, len(r.Invoices))
+	for _, inv := range r.Invoices {
+		rows = append(rows, []string{
+			inv.Number,
+			inv.Workplace,
+			inv.PeriodStart.Format("2006-01-02") + " – " + inv.PeriodEnd.Format("2006-01-02"),
+			reportDate(inv.IssuedAt),
+			reportDate(inv.PaidAt),
+			reportAmount(inv.Gross),
+			reportRate(inv.WithholdingRate),
+			reportAmount(inv.Withholding),
+			reportAmount(inv.IVA),
+			reportAmount(inv.Net),
+		})
+	}
+	l.table(cols, rows, []string{
+		fmt.Sprintf("%d invoices", len(r.Invoices)), "", "", "", "",
+		reportAmount(r.Invoiced.Gross), "",
+		reportAmount(r.Invoiced.Withholding),
+		reportAmount(r.Invoiced.IVA),
+		reportAmount(r.Invoiced.Net),
+	})
+}
+
+func (r *AnnualReport) writeSocialSecurity(l *reportLayout) {
+	l.heading("Social Security contributions")
+
+	cols := []reportColumn{
+		{title: "Quarter", width: 60},
+		{title: "Declaration due", width: 90},
+		{title: "Income", width: 80, right: true},
+		{title: "Relevant income", width: 90, right: true},
+		{title: "Monthly base", width: 80, right: true},
+		{title: "Monthly contribution", width: 100, right: true},
+		{title: "Contributions", width: 80, right: true},
+		{title: "", width: 120},
+	}
+	rows := make([][]string, 0, len(r.SocialSecurity.Declarations))
+	for _, d := range r.SocialSecurity.Declarations {
+		var paid money.Cents
+		for _, p := range d.Payments {
+			if !p.Exempt {
+				paid += p.Amount
+			}
+		}
+		var notes []string
+		if d.Estimated {
+			notes = append(notes, "estimated")
+		}
+		if d.Exempt {
+			notes = append(notes, "exempt")
+		}
+		rows = append(rows, []string{
+			fmt.Sprintf("Q%d", d.Quarter),
+			d.DeclarationDue.Format("2006-01-02"),
+			reportAmount(d.Gross),
+			reportAmount(d.RelevantIncome),
+			reportAmount(d.MonthlyBase),
+			reportAmount(d.MonthlyContribution),
+			reportAmount(paid),
+			strings.Join(notes, ", "),
+		})
+	}
+	l.table(cols, rows, []string{"Total", "", "", "", "", "", reportAmount(r.SocialSecurity.TotalContributions), ""})
+}
+
+func (r *AnnualReport) writeTax(l *reportLayout) {
+	t := r.Tax
+	l.heading("IRS estimate")
+
+	rows := taxRows(t)
+	rows = append(rows, []string{"Effective rate", reportRate(t.IRSEffectiveRate)})
+	settlement := []string{"Settlement: amount due", reportAmount(t.AmountDue)}
+	if t.Refund > 0 {
+		settlement = []string{"Settlement: refund", reportAmount(t.Refund)}
+	}
+	l.table([]reportColumn{{title: "", width: 220}, {title: "", width: 90, right: true}}, rows, settlement)
+
+	if len(t.BracketBreakdown) == 0 {
+		return
+	}
+	l.subheading("Bracket breakdown")
+	cols := []reportColumn{
+		{title: "Bracket", width: 220},
+		{title: "Taxable in bracket", width: 90, right: true},
+		{title: "Tax", width: 80, right: true},
+	}
+	rows = rows[:0]
+	var total money.Cents
+	for _, b := range t.BracketBreakdown {
+		rows = append(rows, []string{b.BracketLabel, reportAmount(b.TaxableInBrack), reportAmount(b.TaxAmount)})
+		total += b.TaxAmount
+	}
+	l.table(cols, rows, []string{"Collected tax", "", reportAmount(total)})
+}
+
+// taxRows lists the estimate's lines, leaving out those that do not apply.
+// IRS less every withholding line and the payments on account comes to the
+// settlement printed below them.
+func taxRows(t *TaxEstimate) [][]string {
+	type line struct {
+		label  string
+		amount money.Cents
+		show   bool
+	}
+	lines := []line{
+		{"Gross income", t.GrossAnnualIncome, true},
+		{"of which salary (Category A)", t.EmploymentIncome, t.EmploymentIncome != 0},
+		{"Expense justification shortfall added back", t.ExpenseShortfall, t.ExpenseShortfall != 0},
+		{"Exempt under IRS Jovem", t.IRSJovemExempt, t.IRSJovemExempt != 0},
+		{"Taxable income", t.TaxableIncome, true},
+		{"IRS at the IFICI flat rate", t.IFICITax, t.IFICITax != 0},
+		{"IRS", t.IRSAmount, true},
+		{"Withholding on invoices", t.WithholdingInvoiced, true},
+		{"Withholding expected for the year", t.WithholdingTotal, true},
+		{"Withholding on salary (Category A)", t.EmploymentWithheld, t.EmploymentWithheld != 0},
+		{"Payments on account", t.PaymentsOnAccount, t.PaymentsOnAccount != 0},
+		{"Social Security contributions", t.SocialSecurity, true},
+		{"Social Security on salary (Category A)", t.EmploymentSS, t.EmploymentSS != 0},
+		{"Net income", t.NetAnnualIncome, true},
+	}
+	rows := [][]string{}
+	for _, ln := range lines {
+		if ln.show {
+			rows = append(rows, []string{ln.label, reportAmount(ln.amount)})
+		}
+	}
+	return rows
+}
+
+func (r *AnnualReport) writeExpenses(l *reportLayout) {
+	e := r.Expenses
+	l.heading("Expenses")
+	if len(e.ByCategory) == 0 {
+		l.text("No expenses for the year.")
+	} else {
+		cols := []reportColumn{
+			{title: "Category", width: 220},
+			{title: "Receipts", width: 60, right: true},
+			{title: "Total", width: 80, right: true},
+			{title: "Simplified regime", width: 90, right: true},
+			{title: "Organized accounting", width: 100, right: true},
+		}
+		rows := make([][]string, 0, len(e.ByCategory))
+		for _, c := range e.ByCategory {
+			rows = append(rows, []string{c.Label, strconv.Itoa(c.Count), reportAmount(c.Total), reportAmount(c.SimplifiedEligible), reportAmount(c.OrganizedDeductible)})
+		}
+		l.table(cols, rows, []string{"Total", "", reportAmount(e.TotalExpenses), reportAmount(e.SimplifiedEligible), reportAmount(e.OrganizedDeductible)})
+	}
+
+	j := e.Justification
+	l.text(fmt.Sprintf("Expense justification (art. 31(13) CIRS): %s required, %s counted automatically, %s justified, %s shortfall.",
+		reportAmount(j.Required), reportAmount(j.Automatic), reportAmount(j.Justified), reportAmount(j.Shortfall)))
+}
+
+func (l *reportLayout) newPage() {
+	l.page = l.doc.AddPage()
+	l.pages = append(l.pages, l.page)
+	_, height := l.doc.Size()
+	l.y = height - reportMargin
+}
+
+// need starts a new page unless height points still fit above the footer.
+func (l *reportLayout) need(height float64) {
+	if l.y-height < reportMargin {
+		l.newPage()
+	}
+}
+
+func (l *reportLayout) title(s string) {
+	l.y -= 16
+	l.page.Text(reportMargin, l.y, pdf.HelveticaBold, 16, s)
+	l.y -= 8
+}
+
+func (l *reportLayout) heading(s string) {
+	// keep the heading with the table header and a first row
+	l.need(18 + 3*reportRow)
+	l.y -= 18
+	l.page.Text(reportMargin, l.y, pdf.HelveticaBold, 11, s)
+	l.y -= 6
+}
+
+func (l *reportLayout) subheading(s string) {
+	l.need(12 + 3*reportRow)
+	l.y -= 12
+	l.page.Text(reportMargin, l.y, pdf.HelveticaBold, 9, s)
+	l.y -= 4
+}
+
+func (l *reportLayout) text(s string) {
+	l.need(reportRow)
+	l.y -= reportRow
+	l.page.Text(reportMargin, l.y, pdf.Helvetica, reportFontSize, s)
+}
+
+// table draws a shaded header, when any column has a title, the rows and a
+// bold total row under a rule. The header is repeated on each new page.
+func (l *reportLayout) table(cols []reportColumn, rows [][]string, total []string) {
+	header := false
+	for _, c := range cols {
+		header = header || c.title != ""
+	}
+	drawHeader := func() {
+		if !header {
+			return
+		}
+		l.y -= reportRow
+		l.page.FillRect(reportMargin, l.y-3, tableWidth(cols), reportRow, 0.9)
+		l.row(cols, nil, pdf.HelveticaBold)
+	}
+
+	l.need(2 * reportRow)
+	drawHeader()
+	for _, row := range rows {
+		if l.y-reportRow < reportMargin {
+			l.newPage()
+			drawHeader()
+		}
+		l.y -= reportRow
+		l.row(cols, row, pdf.Helvetica)
+	}
+	if total != nil {
+		l.need(reportRow + 2)
+		l.page.Line(reportMargin, l.y-3, reportMargin+tableWidth(cols), l.y-3, 0.5)
+		l.y -= reportRow + 1
+		l.row(cols, total, pdf.HelveticaBold)
+	}
+	l.y -= 4
+}
+
+// row draws cells on the current line; without cells it draws the titles.
+func (l *reportLayout) row(cols []reportColumn, cells []string, font pdf.Font) {
+	x := reportMargin
+	for i, c := range cols {
+		s := c.title
+		if cells != nil {
+			s = ""
+			if i < len(cells) {
+				s = cells[i]
+			}
+		}
+		s = pdf.Truncate(font, reportFontSize, c.width-4, s)
+		if c.right {
+			l.page.TextRight(x+c.width-2, l.y, font, reportFontSize, s)
+		} else {
+			l.page.Text(x+2, l.y, font, reportFontSize, s)
+		}
+		x += c.width
+	}
+}
+
+func tableWidth(cols []reportColumn) float64 {
+	var width float64
+	for _, c := range cols {
+		width += c.width
+	}
+	return width
+}
+
+// reportAmount formats cents as euros with thousands separators.
+func reportAmount(c money.Cents) string {
+	sign := ""
+	if c < 0 {
+		sign, c = "-", -c
+	}
+	euros := strconv.FormatInt(int64(c)/100, 10)
+	for i := len(euros) - 3; i > 0; i -= 3 {
+		euros = euros[:i] + "," + euros[i:]
+	}
+	return fmt.Sprintf("%s%s.%02d", sign, euros, int64(c)%100)
+}
+
+// reportRate formats a rate as a percentage with up to two decimals.
+func reportRate(rate float64) string {
+	s := strings.TrimRight(fmt.Sprintf("%.2f", rate*100), "0")
+	return strings.TrimSuffix(s, ".") + "%"
+}
+
+func reportDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,134 @@
+package finance
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/workplace"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+func TestBuildAnnualReport(t *testing.T) {
+	hospital := &workplace.Workplace{ID: uuid.New(), Name: "Hospital"}
+	clinic := &workplace.Workplace{ID: uuid.New(), Name: "Clinic"}
+	monthly := []EarningsSummary{
+		{Period: "2025-01", ByWorkplace: []WorkplaceEarnings{
+			{WorkplaceID: clinic.ID, WorkplaceName: clinic.Name, Gross: money.FromEuros(800)},
+			{WorkplaceID: hospital.ID, WorkplaceName: hospital.Name, Gross: money.FromEuros(3000)},
+		}},
+		{Period: "2025-03", ByWorkplace: []WorkplaceEarnings{
+			{WorkplaceID: hospital.ID, WorkplaceName: hospital.Name, Gross: money.FromEuros(2500)},
+		}},
+	}
+	number := "FR ATSIRE01FR/7"
+	invoices := []*Invoice{
+		{WorkplaceID: hospital.ID, PeriodStart: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), GrossAmountCents: money.FromEuros(2500), WithholdingCents: money.FromEuros(575), NetAmountCents: money.FromEuros(1925)},
+		{WorkplaceID: hospital.ID, PeriodStart: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC), GrossAmountCents: money.FromEuros(1000)},
+		{WorkplaceID: clinic.ID, PeriodStart: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), InvoiceNumber: &number, GrossAmountCents: money.FromEuros(800), WithholdingCents: money.FromEuros(184), NetAmountCents: money.FromEuros(616)},
+	}
+	start, end := YearBounds(2025, time.UTC)
+
+	report := BuildAnnualReport(2025, start, end, monthly, invoices, map[uuid.UUID]*workplace.Workplace{hospital.ID: hospital, clinic.ID: clinic})
+
+	if len(report.Earnings) != 2 || report.Earnings[0].Name != "Hospital" || report.Earnings[0].Total != money.FromEuros(5500) {
+		t.Fatalf("expected the hospital first with 5500 EUR, got %+v", report.Earnings)
+	}
+	if report.Earnings[0].Monthly[time.March-1] != money.FromEuros(2500) || report.Monthly[0] != money.FromEuros(3800) || report.Total != money.FromEuros(6300) {
+		t.Errorf("unexpected monthly totals %v (total %d)", report.Monthly, report.Total)
+	}
+
+	// December 2024's invoice belongs to the previous year
+	if len(report.Invoices) != 2 || report.Invoices[0].Number != number || report.Invoices[1].Number != "draft" || report.Invoices[1].Workplace != "Hospital" {
+		t.Fatalf("expected the year's two invoices in period order, got %+v", report.Invoices)
+	}
+	if report.Invoiced.Gross != money.FromEuros(3300) || report.Invoiced.Withholding != money.FromEuros(759) {
+		t.Errorf("unexpected invoice totals %+v", report.Invoiced)
+	}
+}
+
+func TestAnnualReport_WritePDF(t *testing.T) {
+	hospital := &workplace.Workplace{ID: uuid.New(), Name: "Hospital de Santa Maria"}
+	var invoices []*Invoice
+	for i := 0; i < 120; i++ {
+		invoices = append(invoices, &Invoice{
+			WorkplaceID:      hospital.ID,
+			PeriodStart:      time.Date(2025, time.Month(i%12+1), 1, 0, 0, 0, 0, time.UTC),
+			GrossAmountCents: money.FromEuros(1000),
+			WithholdingRate:  0.23,
+			WithholdingCents: money.FromEuros(230),
+		})
+	}
+	start, end := YearBounds(2025, time.UTC)
+	report := BuildAnnualReport(2025, start, end, nil, invoices, map[uuid.UUID]*workplace.Workplace{hospital.ID: hospital})
+	report.Taxpayer = "Joana Simões"
+	report.GeneratedAt = time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	engine := tax.NewPortugalEngine()
+	config := tax.ConfigForYear(2025)
+	irs := engine.CalculateIRS(config, tax.Income{Gross: report.Invoiced.Gross})
+	report.Tax = &TaxEstimate{FiscalYear: 2025, GrossAnnualIncome: report.Invoiced.Gross, IRSAmount: irs.TotalTax}
+	for _, b := range irs.BracketBreakdown {
+		report.Tax.BracketBreakdown = append(report.Tax.BracketBreakdown, BracketDetail{BracketLabel: b.BracketLabel, Rate: b.Rate, TaxAmount: b.TaxAmount})
+	}
+	report.Expenses = &ExpenseReport{FiscalYear: 2025}
+
+	var buf bytes.Buffer
+	if err := report.WritePDF(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.Bytes()
+	if !bytes.HasPrefix(out, []byte("%PDF-")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Fatal("expected a complete PDF")
+	}
+	// 120 invoices at 12 points a row run over a second page
+	if bytes.Contains(out, []byte("/Count 1 ")) || !bytes.Contains(out, []byte("/Title (Annual report 2025)")) {
+		t.Errorf("expected a titled report over several pages")
+	}
+}
+
+func TestTaxRows_ListSalaryLines(t *testing.T) {
+	est := &TaxEstimate{
+		GrossAnnualIncome:  money.FromEuros(60000),
+		EmploymentIncome:   money.FromEuros(20000),
+		IRSAmount:          money.FromEuros(12000),
+		WithholdingTotal:   money.FromEuros(9200),
+		EmploymentWithheld: money.FromEuros(3000),
+		EmploymentSS:       money.FromEuros(2200),
+	}
+	labels := map[string]string{}
+	for _, row := range taxRows(est) {
+		labels[row[0]] = row[1]
+	}
+	if labels["Withholding on salary (Category A)"] != "3,000.00" || labels["Social Security on salary (Category A)"] != "2,200.00" {
+		t.Errorf("expected the salary withholding and contributions listed, got %v", labels)
+	}
+
+	est.EmploymentIncome, est.EmploymentWithheld, est.EmploymentSS = 0, 0, 0
+	for _, row := range taxRows(est) {
+		if strings.Contains(row[0], "salary") {
+			t.Errorf("expected no salary lines without Category A income, got %q", row[0])
+		}
+	}
+}
+
+func TestReportAmount(t *testing.T) {
+	for c, want := range map[money.Cents]string{
+		0:           "0.00",
+		5:           "0.05",
+		123456:      "1,234.56",
+		-100000000:  "-1,000,000.00",
+		12345678901: "123,456,789.01",
+	} {
+		if got := reportAmount(c); got != want {
+			t.Errorf("%d: expected %q, got %q", c, want, got)
+		}
+	}
+	if got := fmt.Sprint(reportRate(0.23), " ", reportRate(0.125), " ", reportRate(0)); got != "23% 12.5% 0%" {
+		t.Errorf("unexpected rates %q", got)
+	}
+}
@@ -12,14 +12,55 @@ type Repository interface {
 	CreateInvoice(ctx context.Context, invoice *Invoice) error
 	GetInvoiceByID(ctx context.Context, id uuid.UUID) (*Invoice, error)
 	ListInvoices(ctx context.Context, userID uuid.UUID, workplaceID *uuid.UUID, start, end time.Time) ([]*Invoice, error)
+	ListUnpaidInvoices(ctx context.Context, userID uuid.UUID) ([]*Invoice, error)
+	ListPaidInvoices(ctx context.Context, userID uuid.UUID) ([]*Invoice, error)
 	UpdateInvoice(ctx context.Context, invoice *Invoice) error
 	DeleteInvoice(ctx context.Context, id uuid.UUID) error
+	// RecordPayments sets the invoices' paid dates and moves the earnings of
+	// the given shifts to paid, in one transaction.
+	RecordPayments(ctx context.Context, invoices []*Invoice, shiftIDs []uuid.UUID) error
 
-	// Earnings aggregation
+	// Expenses
+	CreateExpense(ctx context.Context, expense *Expense) error
+	GetExpenseByID(ctx context.Context, id uuid.UUID) (*Expense, error)
+	ListExpenses(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]*Expense, error)
+	UpdateExpense(ctx context.Context, expense *Expense) error
+	DeleteExpense(ctx context.Context, id uuid.UUID) error
+
+	// Income goals
+	CreateGoal(ctx context.Context, goal *IncomeGoal) error
+	GetGoalByID(ctx context.Context, id uuid.UUID) (*IncomeGoal, error)
+	ListGoals(ctx context.Context, userID uuid.UUID, year int) ([]*IncomeGoal, error)
+	UpdateGoal(ctx context.Context, goal *IncomeGoal) error
+	DeleteGoal(ctx context.Context, id uuid.UUID) error
+
+	// Tax scenarios
+	CreateScenario(ctx context.Context, scenario *Scenario) error
+	GetScenarioByID(ctx context.Context, id uuid.UUID) (*Scenario, error)
+	ListScenarios(ctx context.Context, userID uuid.UUID, year int) ([]*Scenario, error)
+	UpdateScenario(ctx context.Context, scenario *Scenario) error
+	DeleteScenario(ctx context.Context, id uuid.UUID) error
+
+	// Payments on account
+	CreatePaymentOnAccount(ctx context.Context, payment *PaymentOnAccount) error
+	GetPaymentOnAccountByID(ctx context.Context, id uuid.UUID) (*PaymentOnAccount, error)
+	ListPaymentsOnAccount(ctx context.Context, userID uuid.UUID, fiscalYear int) ([]*PaymentOnAccount, error)
+	DeletePaymentOnAccount(ctx context.Context, id uuid.UUID) error
+
+	// Category A salary records. Years are calendar years of the period paid.
+	CreateSalaryRecord(ctx context.Context, record *SalaryRecord) error
+	GetSalaryRecordByID(ctx context.Context, id uuid.UUID) (*SalaryRecord, error)
+	ListSalaryRecords(ctx context.Context, userID uuid.UUID, year int) ([]*SalaryRecord, error)
+	UpdateSalaryRecord(ctx context.Context, record *SalaryRecord) error
+	DeleteSalaryRecord(ctx context.Context, id uuid.UUID) error
+
+	// Earnings aggregation. Periods are half-open [start, end); month and year
+	// boundaries are drawn in loc.
 	GetEarningsSummary(ctx context.Context, userID uuid.UUID, start, end time.Time) (*EarningsSummary, error)
-	GetMonthlyEarnings(ctx context.Context, userID uuid.UUID, year int) ([]EarningsSummary, error)
-	GetYearlyEarnings(ctx context.Context, userID uuid.UUID, year int) (*EarningsSummary, error)
+	GetMonthlyEarnings(ctx context.Context, userID uuid.UUID, year int, loc *time.Location) ([]EarningsSummary, error)
+	GetYearlyEarnings(ctx context.Context, userID uuid.UUID, year int, loc *time.Location) (*EarningsSummary, error)
+	ListEarningSegments(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]EarningSegment, error)
 
 	// Projections
-	GetProjections(ctx context.Context, userID uuid.UUID, year int) ([]Projection, error)
+	GetProjections(ctx context.Context, userID uuid.UUID, year int, loc *time.Location) ([]Projection, error)
 }
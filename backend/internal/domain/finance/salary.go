@@ -0,0 +1,68 @@
+package finance
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+// SalaryRecord is a month's pay under an employment contract (Category A), as
+// it appears on the payslip.
+type SalaryRecord struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+
+	Employer            string      `json:"employer"`
+	Period              time.Time   `json:"period"` // first day of the month paid
+	GrossCents          money.Cents `json:"gross_cents"`
+	WithholdingCents    money.Cents `json:"withholding_cents"`     // IRS withheld
+	SocialSecurityCents money.Cents `json:"social_security_cents"` // employee's contributions
+	Notes               *string     `json:"notes,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateSalaryRecordInput struct {
+	Employer            string    `json:"employer" validate:"required,max=255"`
+	Period              time.Time `json:"period" validate:"required"`
+	GrossCents          int64     `json:"gross_cents" validate:"required,min=1"`
+	WithholdingCents    int64     `json:"withholding_cents" validate:"min=0"`
+	SocialSecurityCents int64     `json:"social_security_cents" validate:"min=0"`
+	Notes               *string   `json:"notes"`
+}
+
+type UpdateSalaryRecordInput struct {
+	Employer            *string    `json:"employer" validate:"omitempty,max=255"`
+	Period              *time.Time `json:"period"`
+	GrossCents          *int64     `json:"gross_cents" validate:"omitempty,min=1"`
+	WithholdingCents    *int64     `json:"withholding_cents" validate:"omitempty,min=0"`
+	SocialSecurityCents *int64     `json:"social_security_cents" validate:"omitempty,min=0"`
+	Notes               *string    `json:"notes"`
+}
+
+// SalaryPeriod is the first day of the month a payslip is for.
+func SalaryPeriod(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// valid reports whether the payslip adds up: a positive gross that covers
+// what was withheld and contributed from it.
+func (r *SalaryRecord) valid() bool {
+	return r.Employer != "" && r.Period.Year() > 1 && r.GrossCents > 0 &&
+		r.WithholdingCents >= 0 && r.SocialSecurityCents >= 0 &&
+		r.WithholdingCents+r.SocialSecurityCents <= r.GrossCents
+}
+
+// EmploymentIncome adds up a year's payslips into its Category A income.
+func EmploymentIncome(records []*SalaryRecord) tax.Employment {
+	var employment tax.Employment
+	for _, r := range records {
+		employment.Gross += r.GrossCents
+		employment.Withholding += r.WithholdingCents
+		employment.SocialSecurity += r.SocialSecurityCents
+	}
+	return employment
+}
@@ -0,0 +1,240 @@
+package finance
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/auth"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/workplace"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+// Scenario is a named what-if for a year. The baseline is the user's
+// earnings, workplaces and tax profile as they stand; the overrides describe
+// what changes.
+type Scenario struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+
+	Year      int               `json:"year"`
+	Name      string            `json:"name"`
+	Overrides ScenarioOverrides `json:"overrides"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ScenarioOverrides struct {
+	Workplaces []WorkplaceOverride `json:"workplaces,omitempty"`
+	AddShifts  []ScenarioShift     `json:"add_shifts,omitempty"`
+
+	// Tax profile; zero clears a year, as on the profile
+	TaxRegime         *string `json:"tax_regime,omitempty"`
+	BirthYear         *int    `json:"birth_year,omitempty"`
+	IRSJovemFirstYear *int    `json:"irs_jovem_first_year,omitempty"`
+	IFICIStartYear    *int    `json:"ifici_start_year,omitempty"`
+
+	// Activity expenses on e-Fatura on top of the year's, counted by both
+	// regimes
+	ExtraExpensesCents int64 `json:"extra_expenses_cents,omitempty"`
+}
+
+// WorkplaceOverride changes what a workplace pays over the year.
+type WorkplaceOverride struct {
+	WorkplaceID uuid.UUID `json:"workplace_id"`
+	Drop        bool      `json:"drop,omitempty"`        // stop working there
+	RateChange  float64   `json:"rate_change,omitempty"` // 0.10 raises every earning by 10%
+}
+
+// ScenarioShift is a shift added PerMonth times to every month from
+// FromMonth, on the first days of the month that match Days.
+type ScenarioShift struct {
+	WorkplaceID   uuid.UUID             `json:"workplace_id"`
+	PerMonth      int                   `json:"per_month"`
+	StartTime     string                `json:"start_time"` // HH:MM
+	Hours         float64               `json:"hours"`
+	Days          []workplace.DayOfWeek `json:"days,omitempty"`       // any day when empty
+	FromMonth     int                   `json:"from_month,omitempty"` // January when zero
+	PatientsSeen  int                   `json:"patients_seen,omitempty"`
+	OutsideVisits int                   `json:"outside_visits,omitempty"`
+}
+
+type CreateScenarioInput struct {
+	Year      int               `json:"year" validate:"required"`
+	Name      string            `json:"name" validate:"required,max=100"`
+	Overrides ScenarioOverrides `json:"overrides"`
+}
+
+type UpdateScenarioInput struct {
+	Name      *string            `json:"name" validate:"omitempty,max=100"`
+	Overrides *ScenarioOverrides `json:"overrides"`
+}
+
+// ScenarioResult is the year's tax estimate under a scenario, with the
+// changes from the baseline.
+type ScenarioResult struct {
+	ScenarioID *uuid.UUID        `json:"scenario_id,omitempty"` // none for the baseline
+	Name       string            `json:"name"`
+	Income     tax.Income        `json:"income"`
+	Summary    tax.AnnualSummary `json:"summary"`
+
+	GrossChange money.Cents `json:"gross_change"`
+	IRSChange   money.Cents `json:"irs_change"`
+	SSChange    money.Cents `json:"ss_change"`
+	NetChange   money.Cents `json:"net_change"`
+
+	// Why the scenario could not be estimated; the figures are then empty
+	Error string `json:"error,omitempty"`
+}
+
+type ScenarioComparison struct {
+	Year      int              `json:"year"`
+	Baseline  ScenarioResult   `json:"baseline"`
+	Scenarios []ScenarioResult `json:"scenarios"`
+}
+
+// ScenarioBaseline is what scenarios change: the year's tax income, its
+// earnings per workplace and the tax profile behind the income.
+type ScenarioBaseline struct {
+	Income   tax.Income
+	Earnings []WorkplaceEarnings
+	Profile  TaxProfile
+}
+
+// TaxProfile is the part of the user's profile that shapes the year's tax.
+type TaxProfile struct {
+	Regime            tax.Regime
+	BirthYear         *int
+	IRSJovemFirstYear *int
+	IFICIStartYear    *int
+}
+
+func (o ScenarioOverrides) validate() error {
+	for _, wo := range o.Workplaces {
+		if wo.RateChange <= -1 {
+			return ErrInvalidScenario
+		}
+	}
+	for _, shift := range o.AddShifts {
+		if _, err := time.Parse("15:04", shift.StartTime); err != nil {
+			return ErrInvalidScenario
+		}
+		if shift.PerMonth < 1 || shift.PerMonth > 31 || shift.Hours <= 0 || shift.Hours > 48 ||
+			shift.FromMonth < 0 || shift.FromMonth > 12 || shift.PatientsSeen < 0 || shift.OutsideVisits < 0 {
+			return ErrInvalidScenario
+		}
+		for _, day := range shift.Days {
+			if !slices.Contains(workplace.AllDays, day) {
+				return ErrInvalidScenario
+			}
+		}
+	}
+	if o.TaxRegime != nil && *o.TaxRegime != auth.TaxRegimeSimplified && *o.TaxRegime != auth.TaxRegimeOrganized {
+		return ErrInvalidScenario
+	}
+	for _, year := range []*int{o.BirthYear, o.IRSJovemFirstYear, o.IFICIStartYear} {
+		if year != nil && *year != 0 && (*year < 1900 || *year > 2100) {
+			return ErrInvalidScenario
+		}
+	}
+	if o.ExtraExpensesCents < 0 {
+		return ErrInvalidScenario
+	}
+	return nil
+}
+
+// ApplyScenario works out the year's tax income under a scenario. added is
+// what the scenario's extra shifts earn; see ScenarioShiftEarnings.
+// Declared contributions follow the change in gross, as WithGross moves them.
+func ApplyScenario(engine tax.Engine, config tax.YearConfig, baseline ScenarioBaseline, overrides ScenarioOverrides, added money.Cents) (tax.Income, error) {
+	income := baseline.Income
+
+	// Earnings not tied to a workplace are left as they are
+	gross := income.Gross
+	for _, we := range baseline.Earnings {
+		gross -= we.Gross
+		earned := we.Gross
+		for _, wo := range overrides.Workplaces {
+			if wo.WorkplaceID != we.WorkplaceID {
+				continue
+			}
+			if wo.Drop {
+				earned = 0
+			} else if wo.RateChange != 0 {
+				earned = money.Cents(float64(earned) * (1 + wo.RateChange))
+			}
+		}
+		gross += earned
+	}
+	income = WithGross(engine, config, income, gross+added)
+
+	extra := money.Cents(overrides.ExtraExpensesCents)
+	income.JustifiedExpenses += extra
+	income.DeductibleExpenses += extra
+
+	profile := baseline.Profile
+	if overrides.TaxRegime != nil {
+		profile.Regime = tax.Regime(*overrides.TaxRegime)
+	}
+	for _, field := range []struct {
+		value *int
+		dest  **int
+	}{
+		{overrides.BirthYear, &profile.BirthYear},
+		{overrides.IRSJovemFirstYear, &profile.IRSJovemFirstYear},
+		{overrides.IFICIStartYear, &profile.IFICIStartYear},
+	} {
+		switch {
+		case field.value == nil:
+		case *field.value == 0:
+			*field.dest = nil
+		default:
+			*field.dest = field.value
+		}
+	}
+	if profile.IRSJovemFirstYear != nil && profile.IFICIStartYear != nil {
+		return tax.Income{}, ErrInvalidScenario
+	}
+
+	income.Regime = profile.Regime
+	income.IRSJovemYear = tax.IRSJovemYear(config, profile.IRSJovemFirstYear, profile.BirthYear)
+	income.IFICI = tax.IFICIApplies(config, profile.IFICIStartYear)
+	return income, nil
+}
+
+// ScenarioShiftEarnings prices a scenario's extra shifts for the year with
+// the workplace's pricing rules, placing them in loc.
+func ScenarioShiftEarnings(year int, shift ScenarioShift, wp *workplace.Workplace, rules []*workplace.PricingRule, loc *time.Location) money.Cents {
+	startTime, err := time.Parse("15:04", shift.StartTime)
+	if err != nil {
+		return 0
+	}
+	duration := time.Duration(shift.Hours * float64(time.Hour))
+
+	var total money.Cents
+	for month := max(shift.FromMonth, 1); month <= 12; month++ {
+		first := time.Date(year, time.Month(month), 1, startTime.Hour(), startTime.Minute(), 0, 0, loc)
+		placed := 0
+		for start := first; start.Month() == first.Month() && placed < shift.PerMonth; start = start.AddDate(0, 0, 1) {
+			if len(shift.Days) > 0 && !slices.Contains(shift.Days, workplace.DayOfWeekOf(start)) {
+				continue
+			}
+			for _, seg := range workplace.ResolveShiftEarnings(start, start.Add(duration), wp, rules, shift.PatientsSeen, shift.OutsideVisits) {
+				total += seg.Amount
+			}
+			placed++
+		}
+	}
+	return total
+}
+
+// CompareScenario sets a scenario's estimate against the baseline's.
+func CompareScenario(result ScenarioResult, baseline tax.AnnualSummary) ScenarioResult {
+	result.GrossChange = result.Summary.GrossIncome - baseline.GrossIncome
+	result.IRSChange = result.Summary.IRSAmount - baseline.IRSAmount
+	result.SSChange = result.Summary.SSAnnual - baseline.SSAnnual
+	result.NetChange = result.Summary.NetIncome - baseline.NetIncome
+	return result
+}
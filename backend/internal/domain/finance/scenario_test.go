@@ -0,0 +1,150 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/auth"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/workplace"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+func TestScenarioShiftEarnings(t *testing.T) {
+	er := &workplace.Workplace{ID: uuid.New(), PayModel: workplace.PayModelHourly, BaseRateCents: money.FromEuros(40)}
+	multiplier := 1.5
+	weekend := &workplace.PricingRule{Name: "Weekend", Priority: 1, DaysOfWeek: workplace.Weekend, RateMultiplier: &multiplier, IsActive: true}
+
+	// Two Saturday day shifts in November and two in December
+	shift := ScenarioShift{WorkplaceID: er.ID, PerMonth: 2, StartTime: "08:00", Hours: 12, Days: []workplace.DayOfWeek{workplace.Saturday}, FromMonth: 11}
+
+	if got, want := ScenarioShiftEarnings(2026, shift, er, nil, time.UTC), money.FromEuros(4*12*40); got != want {
+		t.Errorf("expected %d at the base rate, got %d", want, got)
+	}
+	rules := []*workplace.PricingRule{weekend}
+	if got, want := ScenarioShiftEarnings(2026, shift, er, rules, time.UTC), money.FromEuros(4*12*60); got != want {
+		t.Errorf("expected %d at the weekend rate, got %d", want, got)
+	}
+}
+
+func TestApplyScenario(t *testing.T) {
+	config := tax.Portugal2026Config()
+	clinic, hospital := uuid.New(), uuid.New()
+	baseline := ScenarioBaseline{
+		Income: tax.Income{Gross: money.FromEuros(61000), Regime: tax.RegimeSimplified, JustifiedExpenses: money.FromEuros(5000)},
+		Earnings: []WorkplaceEarnings{
+			{WorkplaceID: clinic, Gross: money.FromEuros(20000)},
+			{WorkplaceID: hospital, Gross: money.FromEuros(40000)},
+		},
+		Profile: TaxProfile{Regime: tax.RegimeSimplified},
+	}
+
+	organized := auth.TaxRegimeOrganized
+	overrides := ScenarioOverrides{
+		Workplaces: []WorkplaceOverride{
+			{WorkplaceID: clinic, Drop: true},
+			{WorkplaceID: hospital, RateChange: 0.1},
+		},
+		TaxRegime:          &organized,
+		ExtraExpensesCents: int64(money.FromEuros(1000)),
+	}
+
+	income, err := ApplyScenario(tax.NewPortugalEngine(), config, baseline, overrides, money.FromEuros(5000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1,000 EUR not tied to a workplace, 44,000 from the hospital and 5,000 added
+	if want := money.FromEuros(50000); income.Gross != want {
+		t.Errorf("expected %d gross, got %d", want, income.Gross)
+	}
+	if income.Regime != tax.RegimeOrganized || income.JustifiedExpenses != money.FromEuros(6000) || income.DeductibleExpenses != money.FromEuros(1000) {
+		t.Errorf("unexpected profile or expenses: %+v", income)
+	}
+
+	first, start := 2025, 2025
+	_, err = ApplyScenario(tax.NewPortugalEngine(), config, baseline, ScenarioOverrides{IRSJovemFirstYear: &first, IFICIStartYear: &start}, 0)
+	if !errors.Is(err, ErrInvalidScenario) {
+		t.Errorf("expected IRS Jovem and IFICI together to be rejected, got %v", err)
+	}
+}
+
+func TestCompareScenario(t *testing.T) {
+	engine := tax.NewPortugalEngine()
+	config := tax.Portugal2026Config()
+
+	base := engine.CalculateAnnualSummary(config, tax.Income{Gross: money.FromEuros(50000)})
+	more := engine.CalculateAnnualSummary(config, tax.Income{Gross: money.FromEuros(60000)})
+
+	got := CompareScenario(ScenarioResult{Summary: more}, base)
+	if got.GrossChange != money.FromEuros(10000) || got.IRSChange <= 0 || got.SSChange <= 0 {
+		t.Errorf("unexpected changes %+v", got)
+	}
+	if got.NetChange != got.GrossChange-got.IRSChange-got.SSChange {
+		t.Errorf("expected the net change to be what the taxes leave, got %d", got.NetChange)
+	}
+}
+
+type mockScenarioRepo struct {
+	mockSettlementRepo
+	scenarios []*Scenario
+}
+
+func (m *mockScenarioRepo) CreateScenario(_ context.Context, scenario *Scenario) error {
+	m.scenarios = append(m.scenarios, scenario)
+	return nil
+}
+
+func (m *mockScenarioRepo) ListScenarios(_ context.Context, _ uuid.UUID, _ int) ([]*Scenario, error) {
+	return m.scenarios, nil
+}
+
+func TestCreateScenario_ChecksWorkplaces(t *testing.T) {
+	userID := uuid.New()
+	own := &workplace.Workplace{ID: uuid.New(), UserID: userID}
+	other := &workplace.Workplace{ID: uuid.New(), UserID: uuid.New()}
+	repo := &mockScenarioRepo{}
+	svc := NewService(repo, &mockWorkplaceRepo{workplaces: []*workplace.Workplace{own, other}}, nil, &mockUserRepo{}, tax.DefaultRegistry())
+
+	input := CreateScenarioInput{Year: 2026, Name: "Raise", Overrides: ScenarioOverrides{
+		Workplaces: []WorkplaceOverride{{WorkplaceID: other.ID, Drop: true}},
+	}}
+	if _, err := svc.CreateScenario(context.Background(), userID, input); !errors.Is(err, ErrInvalidScenario) {
+		t.Errorf("expected another user's workplace to be rejected, got %v", err)
+	}
+	input.Overrides.Workplaces[0].WorkplaceID = own.ID
+	if _, err := svc.CreateScenario(context.Background(), userID, input); err != nil || len(repo.scenarios) != 1 {
+		t.Errorf("expected the scenario to be saved, got %v", err)
+	}
+}
+
+func TestCompareScenarios_FlagsScenarioWithMissingWorkplace(t *testing.T) {
+	userID := uuid.New()
+	organized := auth.TaxRegimeOrganized
+	repo := &mockScenarioRepo{
+		mockSettlementRepo: mockSettlementRepo{gross: money.FromEuros(40000)},
+		scenarios: []*Scenario{
+			{ID: uuid.New(), UserID: userID, Year: 2026, Name: "Deleted clinic", Overrides: ScenarioOverrides{
+				AddShifts: []ScenarioShift{{WorkplaceID: uuid.New(), PerMonth: 2, StartTime: "08:00", Hours: 12}},
+			}},
+			{ID: uuid.New(), UserID: userID, Year: 2026, Name: "Organized", Overrides: ScenarioOverrides{TaxRegime: &organized}},
+		},
+	}
+	svc := NewService(repo, &mockWorkplaceRepo{}, nil, &mockUserRepo{}, tax.DefaultRegistry())
+
+	got, err := svc.CompareScenarios(context.Background(), userID, 2026)
+	if err != nil {
+		t.Fatalf("CompareScenarios failed: %v", err)
+	}
+	if len(got.Scenarios) != 2 {
+		t.Fatalf("expected both scenarios, got %d", len(got.Scenarios))
+	}
+	if got.Scenarios[0].Error == "" || got.Scenarios[0].Summary.GrossIncome != 0 {
+		t.Errorf("expected the first scenario flagged without figures, got %+v", got.Scenarios[0])
+	}
+	if got.Scenarios[1].Error != "" || got.Scenarios[1].Summary.GrossIncome != money.FromEuros(40000) {
+		t.Errorf("expected the second scenario estimated, got %+v", got.Scenarios[1])
+	}
+}
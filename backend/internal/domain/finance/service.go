@@ -2,54 +2,919 @@ package finance
 
 import (
 	"context"
+	"errors"
+	"io"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/joao-moreira/doctor-tracker/internal/domain/workplace"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/auth"
 	"github.com/joao-moreira/doctor-tracker/internal/domain/schedule"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/workplace"
 	"github.com/joao-moreira/doctor-tracker/pkg/money"
 )
 
+var (
+	ErrInvoiceNotFound          = errors.New("invoice not found")
+	ErrExpenseNotFound          = errors.New("expense not found")
+	ErrInvalidExpenseCategory   = errors.New("invalid expense category")
+	ErrGoalNotFound             = errors.New("goal not found")
+	ErrInvalidGoal              = errors.New("invalid goal")
+	ErrInvalidSettlement        = errors.New("invalid settlement input")
+	ErrScenarioNotFound         = errors.New("scenario not found")
+	ErrInvalidScenario          = errors.New("invalid scenario")
+	ErrCalendarUnavailable      = errors.New("tax calendar is only available for Portuguese tax residents")
+	ErrPaymentOnAccountNotFound = errors.New("payment on account not found")
+	ErrInvalidPaymentOnAccount  = errors.New("invalid payment on account")
+	ErrSalaryRecordNotFound     = errors.New("salary record not found")
+	ErrInvalidSalaryRecord      = errors.New("invalid salary record")
+)
+
 type Service struct {
 	repo          Repository
 	workplaceRepo workplace.Repository
 	scheduleRepo  schedule.Repository
+	userRepo      auth.Repository
+	taxes         *tax.Registry
 }
 
-func NewService(repo Repository, workplaceRepo workplace.Repository, scheduleRepo schedule.Repository) *Service {
+func NewService(repo Repository, workplaceRepo workplace.Repository, scheduleRepo schedule.Repository, userRepo auth.Repository, taxes *tax.Registry) *Service {
 	return &Service{
 		repo:          repo,
 		workplaceRepo: workplaceRepo,
 		scheduleRepo:  scheduleRepo,
+		userRepo:      userRepo,
+		taxes:         taxes,
 	}
 }
 
 func (s *Service) GetMonthlySummary(ctx context.Context, userID uuid.UUID, year, month int) (*EarningsSummary, error) {
-	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-	end := start.AddDate(0, 1, 0).Add(-time.Nanosecond)
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	start, end := MonthBounds(year, time.Month(month), location(user))
 	return s.repo.GetEarningsSummary(ctx, userID, start, end)
 }
 
 func (s *Service) GetYearlySummary(ctx context.Context, userID uuid.UUID, year int) (*EarningsSummary, error) {
-	return s.repo.GetYearlyEarnings(ctx, userID, year)
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetYearlyEarnings(ctx, userID, year, location(user))
 }
 
 func (s *Service) GetMonthlyBreakdown(ctx context.Context, userID uuid.UUID, year int) ([]EarningsSummary, error) {
-	return s.repo.GetMonthlyEarnings(ctx, userID, year)
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetMonthlyEarnings(ctx, userID, year, location(user))
 }
 
+// GetProjections compares the year's projected and actual earnings month by
+// month, with the payments on account due in each month.
 func (s *Service) GetProjections(ctx context.Context, userID uuid.UUID, year int) ([]Projection, error) {
-	return s.repo.GetProjections(ctx, userID, year)
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	projections, err := s.repo.GetProjections(ctx, userID, year, location(user))
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.paymentsOnAccount(ctx, userID, user, year)
+	if err != nil {
+		return nil, err
+	}
+	AddPaymentsOnAccount(projections, payments)
+	return projections, nil
+}
+
+// ComparePeriods compares two periods given as ParsePeriod specs. When
+// previousSpec is empty the current period is compared with the same period a
+// year earlier. The trend covers the twelve months ending with the current
+// period.
+func (s *Service) ComparePeriods(ctx context.Context, userID uuid.UUID, currentSpec, previousSpec string) (*PeriodComparison, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc := location(user)
+
+	current, err := ParsePeriod(currentSpec, loc)
+	if err != nil {
+		return nil, err
+	}
+	previous := current.YearEarlier()
+	if previousSpec != "" {
+		if previous, err = ParsePeriod(previousSpec, loc); err != nil {
+			return nil, err
+		}
+	}
+
+	currentSummary, err := s.repo.GetEarningsSummary(ctx, userID, current.Start, current.End)
+	if err != nil {
+		return nil, err
+	}
+	previousSummary, err := s.repo.GetEarningsSummary(ctx, userID, previous.Start, previous.End)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PeriodComparison{CurrentPeriod: current, PreviousPeriod: previous}
+	result.Current, result.Previous, result.Delta, result.ByWorkplace = ComparePeriods(currentSummary, previousSummary)
+
+	// Rolling totals for the first trend month reach 23 months back
+	endMonth := current.End.Add(-time.Nanosecond).In(loc)
+	monthly := make(map[string]EarningsSummary)
+	for year := endMonth.AddDate(0, -23, 0).Year(); year <= endMonth.Year(); year++ {
+		summaries, err := s.repo.GetMonthlyEarnings(ctx, userID, year, loc)
+		if err != nil {
+			return nil, err
+		}
+		for _, summary := range summaries {
+			monthly[summary.Period] = summary
+		}
+	}
+	result.Trend = BuildTrend(monthly, endMonth)
+
+	return result, nil
+}
+
+// GetProfitability ranks the user's workplaces for the dates [start, end),
+// drawn in the user's timezone.
+func (s *Service) GetProfitability(ctx context.Context, userID uuid.UUID, start, end time.Time) (*ProfitabilityReport, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	j := s.jurisdiction(user)
+	loc := location(user)
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, loc)
+
+	segments, err := s.repo.ListEarningSegments(ctx, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	expenses, err := s.repo.ListExpenses(ctx, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	wps, err := s.workplaceRepo.ListWorkplacesByUser(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*workplace.Workplace, len(wps))
+	for _, wp := range wps {
+		byID[wp.ID] = wp
+	}
+
+	config := j.Config(start.Year())
+	yearStart, _ := YearBounds(start.Year(), loc)
+	toDate, err := s.repo.GetEarningsSummary(ctx, userID, yearStart, end)
+	if err != nil {
+		return nil, err
+	}
+	dispensed := tax.Dispensed(config, withholdingDispensed(user), toDate.GrossEarnings)
+	return BuildProfitabilityReport(start, end, segments, expenses, byID, j.Engine, config, dispensed, loc), nil
+}
+
+// user loads the user's profile, once per request, for the helpers that read
+// it. It is nil when there is no profile, and the defaults then apply; any
+// other failure to load it is returned.
+func (s *Service) user(ctx context.Context, userID uuid.UUID) (*auth.User, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if errors.Is(err, auth.ErrUserNotFound) {
+		return nil, nil
+	}
+	return user, err
+}
+
+// jurisdiction is the tax system of the user's tax residence.
+func (s *Service) jurisdiction(user *auth.User) tax.Jurisdiction {
+	if user == nil {
+		return s.taxes.Resolve("")
+	}
+	return s.taxes.Resolve(user.TaxCountry)
+}
+
+// location is the user's timezone, in which all period boundaries are drawn.
+func location(user *auth.User) *time.Location {
+	if user == nil {
+		return auth.DefaultLocation()
+	}
+	return user.Location()
+}
+
+// TaxIncome gathers a year's Category B income and the expenses each regime
+// counts, under the user's regime and any special regime for that year.
+func (s *Service) TaxIncome(ctx context.Context, userID uuid.UUID, year int) (tax.Income, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return tax.Income{}, err
+	}
+	return s.yearTaxIncome(ctx, userID, user, year)
+}
+
+// yearTaxIncome is TaxIncome for a user already loaded.
+func (s *Service) yearTaxIncome(ctx context.Context, userID uuid.UUID, user *auth.User, year int) (tax.Income, error) {
+	earnings, err := s.repo.GetYearlyEarnings(ctx, userID, year, location(user))
+	if err != nil {
+		return tax.Income{}, err
+	}
+	// The contributions do not depend on when the report is drawn up
+	ss, err := s.socialSecurity(ctx, userID, user, year, time.Now())
+	if err != nil {
+		return tax.Income{}, err
+	}
+	return s.taxIncome(ctx, userID, user, year, earnings, ss)
+}
+
+// taxIncome is TaxIncome on the year's earnings and Social Security
+// declarations already worked out.
+func (s *Service) taxIncome(ctx context.Context, userID uuid.UUID, user *auth.User, year int, earnings *EarningsSummary, ss *SocialSecurityReport) (tax.Income, error) {
+	expenses, err := s.expenseReport(ctx, userID, user, year, earnings)
+	if err != nil {
+		return tax.Income{}, err
+	}
+	income := tax.Income{
+		Gross:              expenses.GrossIncome,
+		Regime:             tax.RegimeSimplified,
+		JustifiedExpenses:  expenses.SimplifiedEligible,
+		DeductibleExpenses: expenses.OrganizedDeductible,
+		SSContributions:    &ss.TotalContributions,
+	}
+	salaries, err := s.repo.ListSalaryRecords(ctx, userID, year)
+	if err != nil {
+		return tax.Income{}, err
+	}
+	income.Employment = EmploymentIncome(salaries)
+
+	if user == nil {
+		return income, nil
+	}
+	if user.TaxRegime == auth.TaxRegimeOrganized {
+		income.Regime = tax.RegimeOrganized
+	}
+	config := s.taxes.Resolve(user.TaxCountry).Config(year)
+	income.IRSJovemYear = tax.IRSJovemYear(config, user.IRSJovemFirstYear, user.BirthYear)
+	income.IFICI = tax.IFICIApplies(config, user.IFICIStartYear)
+	income.WithholdingDispensed = user.WithholdingDispensed
+	return income, nil
+}
+
+// GetSocialSecurity builds the year's quarterly Social Security declarations
+// from its earnings, with the user's base adjustment and first-year
+// exemption.
+func (s *Service) GetSocialSecurity(ctx context.Context, userID uuid.UUID, year int, asOf time.Time) (*SocialSecurityReport, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.socialSecurity(ctx, userID, user, year, asOf)
+}
+
+// socialSecurity is GetSocialSecurity for a user already loaded.
+func (s *Service) socialSecurity(ctx context.Context, userID uuid.UUID, user *auth.User, year int, asOf time.Time) (*SocialSecurityReport, error) {
+	j := s.jurisdiction(user)
+	loc := location(user)
+
+	monthly, err := s.repo.GetMonthlyEarnings(ctx, userID, year, loc)
+	if err != nil {
+		return nil, err
+	}
+	quarterGross := QuarterlyGross(year, monthly)
+
+	var adjustment float64
+	var exemptUntil *time.Time
+	if user != nil {
+		adjustment = float64(user.SSBaseAdjustment) / 100
+		if user.ActivityStartDate != nil {
+			until := SSExemptUntil(*user.ActivityStartDate, j.Config(year).SSExemptionMonths, loc)
+			exemptUntil = &until
+		}
+	}
+
+	return BuildSocialSecurityReport(year, quarterGross, asOf, exemptUntil, adjustment, j, loc), nil
+}
+
+// GetWithholdingStatus tracks the year's income against the withholding
+// dispensation threshold, as of asOf and with the shifts still scheduled.
+func (s *Service) GetWithholdingStatus(ctx context.Context, userID uuid.UUID, year int, asOf time.Time) (*WithholdingStatus, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	j := s.jurisdiction(user)
+	loc := location(user)
+	yearStart, yearEnd := YearBounds(year, loc)
+
+	toDateEnd := asOf
+	if toDateEnd.After(yearEnd) {
+		toDateEnd = yearEnd
+	}
+	var incomeToDate money.Cents
+	if toDateEnd.After(yearStart) {
+		summary, err := s.repo.GetEarningsSummary(ctx, userID, yearStart, toDateEnd)
+		if err != nil {
+			return nil, err
+		}
+		incomeToDate = summary.GrossEarnings
+	}
+
+	monthly, err := s.repo.GetMonthlyEarnings(ctx, userID, year, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	wps, err := s.workplaceRepo.ListWorkplacesByUser(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildWithholdingStatus(year, incomeToDate, monthly, withholdingDispensed(user), wps, j.Config(year)), nil
+}
+
+// GetMarginalAnalysis prices an extra MarginalStep of income on top of the
+// year's projected earnings, under the user's regime.
+func (s *Service) GetMarginalAnalysis(ctx context.Context, userID uuid.UUID, year int) (*MarginalAnalysis, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	j := s.jurisdiction(user)
+	income, err := s.yearTaxIncome(ctx, userID, user, year)
+	if err != nil {
+		return nil, err
+	}
+	return BuildMarginalAnalysis(j.Engine, j.Config(year), income), nil
+}
+
+// GetJurisdictionReport taxes the year's income sourced in each country
+// under that country's rules.
+func (s *Service) GetJurisdictionReport(ctx context.Context, userID uuid.UUID, year int) (*JurisdictionReport, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	income, err := s.yearTaxIncome(ctx, userID, user, year)
+	if err != nil {
+		return nil, err
+	}
+	earnings, err := s.repo.GetYearlyEarnings(ctx, userID, year, location(user))
+	if err != nil {
+		return nil, err
+	}
+	wps, err := s.workplaceRepo.ListWorkplacesByUser(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*workplace.Workplace, len(wps))
+	for _, wp := range wps {
+		byID[wp.ID] = wp
+	}
+	return BuildJurisdictionReport(year, income, earnings.ByWorkplace, byID, s.jurisdiction(user), s.taxes), nil
+}
+
+// CompareRegimes sets the user's year side by side under the simplified and
+// organized regimes.
+func (s *Service) CompareRegimes(ctx context.Context, userID uuid.UUID, year int) (*tax.RegimeComparison, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	j := s.jurisdiction(user)
+	income, err := s.yearTaxIncome(ctx, userID, user, year)
+	if err != nil {
+		return nil, err
+	}
+	comparison := j.Engine.CompareRegimes(j.Config(year), income)
+	comparison.Current = income.Regime
+	return &comparison, nil
+}
+
+// GetCashFlowForecast projects the cash that will actually move in each month
+// of the year, from earnings, payment terms, withholding and tax outflows.
+func (s *Service) GetCashFlowForecast(ctx context.Context, userID uuid.UUID, year int) (*CashFlowForecast, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	j := s.jurisdiction(user)
+	wps, err := s.workplaceRepo.ListWorkplacesByUser(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*workplace.Workplace, len(wps))
+	maxTerms := 0
+	for _, wp := range wps {
+		byID[wp.ID] = wp
+		if wp.PaymentTermsDays > maxTerms {
+			maxTerms = wp.PaymentTermsDays
+		}
+	}
+
+	// Whole years from the one the lookback starts in, usually the previous
+	// year and this one
+	var earnings []EarningsSummary
+	loc := location(user)
+	first := year - (CashFlowLookbackMonths(maxTerms)+11)/12
+	for y := first; y <= year; y++ {
+		monthly, err := s.repo.GetMonthlyEarnings(ctx, userID, y, loc)
+		if err != nil {
+			return nil, err
+		}
+		earnings = append(earnings, monthly...)
+	}
+
+	settlement, err := s.estimateIRSSettlement(ctx, userID, user, year-1)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.paymentsOnAccount(ctx, userID, user, year)
+	if err != nil {
+		return nil, err
+	}
+	var instalments [3]money.Cents
+	for i, inst := range payments.Schedule() {
+		instalments[i] = inst.Amount
+	}
+
+	return BuildCashFlowForecast(year, earnings, byID, j.Engine, j.Config(year), withholdingDispensed(user), settlement, instalments, loc), nil
+}
+
+// estimateIRSSettlement is the IRS still owed for a year once withholding is
+// credited; negative values are refunds.
+func (s *Service) estimateIRSSettlement(ctx context.Context, userID uuid.UUID, user *auth.User, year int) (money.Cents, error) {
+	_, end := YearBounds(year, location(user))
+	estimate, err := s.taxEstimate(ctx, userID, user, year, end)
+	if err != nil {
+		return 0, err
+	}
+	return estimate.SettlementBalance, nil
+}
+
+// GetTaxCalendar lists the user's filing and payment deadlines falling
+// within a year, with the amounts estimated from the data recorded as of
+// asOf.
+func (s *Service) GetTaxCalendar(ctx context.Context, userID uuid.UUID, year int, asOf time.Time) (*tax.Calendar, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	j := s.jurisdiction(user)
+	if j.Code != tax.Portugal().Code {
+		return nil, ErrCalendarUnavailable
+	}
+
+	var reports []*SocialSecurityReport
+	for _, y := range []int{year - 1, year} {
+		report, err := s.socialSecurity(ctx, userID, user, y, asOf)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	settlement, err := s.estimateIRSSettlement(ctx, userID, user, year-1)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.paymentsOnAccount(ctx, userID, user, year)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildTaxCalendar(year, reports, settlement, payments.Schedule(), location(user)), nil
+}
+
+// GetAnnualReport gathers the year's earnings, invoices, Social Security, IRS
+// estimate and expenses into the report for the accountant, with periods not
+// yet ended as of asOf estimated.
+func (s *Service) GetAnnualReport(ctx context.Context, userID uuid.UUID, year int, asOf time.Time) (*AnnualReport, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc := location(user)
+	start, end := YearBounds(year, loc)
+
+	monthly, err := s.repo.GetMonthlyEarnings(ctx, userID, year, loc)
+	if err != nil {
+		return nil, err
+	}
+	invoices, err := s.repo.ListInvoices(ctx, userID, nil, start, end)
+	if err != nil {
+		return nil, err
+	}
+	wps, err := s.workplaceRepo.ListWorkplacesByUser(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*workplace.Workplace, len(wps))
+	for _, wp := range wps {
+		byID[wp.ID] = wp
+	}
+
+	report := BuildAnnualReport(year, start, end, monthly, invoices, byID)
+	report.GeneratedAt = asOf.In(loc)
+	report.Residence = s.jurisdiction(user).Name
+	report.Regime = auth.TaxRegimeSimplified
+	if user != nil {
+		report.Taxpayer = user.FullName
+		if user.NIF != nil {
+			report.NIF = *user.NIF
+		}
+		if user.TaxRegime != "" {
+			report.Regime = user.TaxRegime
+		}
+	}
+
+	if report.SocialSecurity, err = s.socialSecurity(ctx, userID, user, year, asOf); err != nil {
+		return nil, err
+	}
+	if report.Tax, err = s.taxEstimate(ctx, userID, user, year, asOf); err != nil {
+		return nil, err
+	}
+	earnings, err := s.repo.GetYearlyEarnings(ctx, userID, year, loc)
+	if err != nil {
+		return nil, err
+	}
+	if report.Expenses, err = s.expenseReport(ctx, userID, user, year, earnings); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// GetPaymentsOnAccount estimates the year's payments on account from the
+// year before last and sets the payments recorded for it against them.
+func (s *Service) GetPaymentsOnAccount(ctx context.Context, userID uuid.UUID, year int) (*PaymentsOnAccountReport, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.paymentsOnAccount(ctx, userID, user, year)
+}
+
+// paymentsOnAccount is GetPaymentsOnAccount for a user already loaded.
+func (s *Service) paymentsOnAccount(ctx context.Context, userID uuid.UUID, user *auth.User, year int) (*PaymentsOnAccountReport, error) {
+	j := s.jurisdiction(user)
+	basis, err := s.paymentOnAccountBasis(ctx, userID, user, year-2)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.repo.ListPaymentsOnAccount(ctx, userID, year)
+	if err != nil {
+		return nil, err
+	}
+	estimate := tax.PaymentsOnAccount(j.Config(year), basis)
+	return BuildPaymentsOnAccountReport(year, basis, estimate, payments, location(user)), nil
+}
+
+// paymentOnAccountBasis is a past year's IRS on all its income, the share of
+// it from Category B and the withholding recorded on its invoices.
+func (s *Service) paymentOnAccountBasis(ctx context.Context, userID uuid.UUID, user *auth.User, year int) (tax.PaymentOnAccountBasis, error) {
+	j := s.jurisdiction(user)
+	income, err := s.yearTaxIncome(ctx, userID, user, year)
+	if err != nil {
+		return tax.PaymentOnAccountBasis{}, err
+	}
+	start, end := YearBounds(year, location(user))
+	invoices, err := s.repo.ListInvoices(ctx, userID, nil, start, end)
+	if err != nil {
+		return tax.PaymentOnAccountBasis{}, err
+	}
+
+	settlement := j.Engine.SimulateSettlement(j.Config(year), tax.SettlementInput{Income: income})
+	return tax.PaymentOnAccountBasis{
+		FiscalYear:      year,
+		AssessedTax:     settlement.NetTax,
+		CategoryBIncome: settlement.AnexoB.TaxableIncome,
+		TotalIncome:     settlement.TaxableIncome,
+		Withholding:     InvoiceWithholding(invoices, start, end),
+	}, nil
+}
+
+// RecordPaymentOnAccount records an instalment paid, so that the year's
+// settlement credits it.
+func (s *Service) RecordPaymentOnAccount(ctx context.Context, userID uuid.UUID, input RecordPaymentOnAccountInput) (*PaymentOnAccount, error) {
+	if input.FiscalYear == 0 || input.Instalment < 1 || input.Instalment > 3 || input.AmountCents <= 0 || input.PaidOn.IsZero() {
+		return nil, ErrInvalidPaymentOnAccount
+	}
+
+	payment := &PaymentOnAccount{
+		ID:          uuid.New(),
+		UserID:      userID,
+		FiscalYear:  input.FiscalYear,
+		Instalment:  input.Instalment,
+		AmountCents: money.Cents(input.AmountCents),
+		PaidOn:      input.PaidOn,
+		Reference:   input.Reference,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.repo.CreatePaymentOnAccount(ctx, payment); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (s *Service) DeletePaymentOnAccount(ctx context.Context, userID, id uuid.UUID) error {
+	payment, err := s.repo.GetPaymentOnAccountByID(ctx, id)
+	if err != nil || payment.UserID != userID {
+		return ErrPaymentOnAccountNotFound
+	}
+	return s.repo.DeletePaymentOnAccount(ctx, id)
+}
+
+// Category A salary records
+
+func (s *Service) ListSalaryRecords(ctx context.Context, userID uuid.UUID, year int) ([]*SalaryRecord, error) {
+	return s.repo.ListSalaryRecords(ctx, userID, year)
+}
+
+func (s *Service) CreateSalaryRecord(ctx context.Context, userID uuid.UUID, input CreateSalaryRecordInput) (*SalaryRecord, error) {
+	record := &SalaryRecord{
+		ID:                  uuid.New(),
+		UserID:              userID,
+		Employer:            input.Employer,
+		Period:              SalaryPeriod(input.Period),
+		GrossCents:          money.Cents(input.GrossCents),
+		WithholdingCents:    money.Cents(input.WithholdingCents),
+		SocialSecurityCents: money.Cents(input.SocialSecurityCents),
+		Notes:               input.Notes,
+		CreatedAt:           time.Now(),
+		UpdatedAt:           time.Now(),
+	}
+	if !record.valid() {
+		return nil, ErrInvalidSalaryRecord
+	}
+	if err := s.repo.CreateSalaryRecord(ctx, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (s *Service) UpdateSalaryRecord(ctx context.Context, userID, id uuid.UUID, input UpdateSalaryRecordInput) (*SalaryRecord, error) {
+	record, err := s.repo.GetSalaryRecordByID(ctx, id)
+	if err != nil || record.UserID != userID {
+		return nil, ErrSalaryRecordNotFound
+	}
+
+	if input.Employer != nil {
+		record.Employer = *input.Employer
+	}
+	if input.Period != nil {
+		record.Period = SalaryPeriod(*input.Period)
+	}
+	if input.GrossCents != nil {
+		record.GrossCents = money.Cents(*input.GrossCents)
+	}
+	if input.WithholdingCents != nil {
+		record.WithholdingCents = money.Cents(*input.WithholdingCents)
+	}
+	if input.SocialSecurityCents != nil {
+		record.SocialSecurityCents = money.Cents(*input.SocialSecurityCents)
+	}
+	if input.Notes != nil {
+		record.Notes = input.Notes
+	}
+	if !record.valid() {
+		return nil, ErrInvalidSalaryRecord
+	}
+	record.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateSalaryRecord(ctx, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (s *Service) DeleteSalaryRecord(ctx context.Context, userID, id uuid.UUID) error {
+	record, err := s.repo.GetSalaryRecordByID(ctx, id)
+	if err != nil || record.UserID != userID {
+		return ErrSalaryRecordNotFound
+	}
+	return s.repo.DeleteSalaryRecord(ctx, id)
+}
+
+// GetTaxEstimate works out the year's IRS and Social Security on its
+// earnings, credits the withholding on invoices and still to be withheld and
+// the year's payments on account, paid or still expected, and gives the
+// settlement to expect. Contributions follow the quarterly
+// declarations, estimated for quarters that have not ended by asOf.
+func (s *Service) GetTaxEstimate(ctx context.Context, userID uuid.UUID, year int, asOf time.Time) (*TaxEstimate, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.taxEstimate(ctx, userID, user, year, asOf)
+}
+
+// taxEstimate is GetTaxEstimate for a user already loaded.
+func (s *Service) taxEstimate(ctx context.Context, userID uuid.UUID, user *auth.User, year int, asOf time.Time) (*TaxEstimate, error) {
+	j := s.jurisdiction(user)
+	loc := location(user)
+	start, end := YearBounds(year, loc)
+	earnings, err := s.repo.GetYearlyEarnings(ctx, userID, year, loc)
+	if err != nil {
+		return nil, err
+	}
+	ss, err := s.socialSecurity(ctx, userID, user, year, asOf)
+	if err != nil {
+		return nil, err
+	}
+	income, err := s.taxIncome(ctx, userID, user, year, earnings, ss)
+	if err != nil {
+		return nil, err
+	}
+	config := j.Config(year)
+	irs := j.Engine.CalculateIRS(config, income)
+
+	invoices, err := s.repo.ListInvoices(ctx, userID, nil, start, end)
+	if err != nil {
+		return nil, err
+	}
+	wps, err := s.workplaceRepo.ListWorkplacesByUser(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*workplace.Workplace, len(wps))
+	for _, wp := range wps {
+		byID[wp.ID] = wp
+	}
+	monthly, err := s.repo.GetMonthlyEarnings(ctx, userID, year, loc)
+	if err != nil {
+		return nil, err
+	}
+	invoiced, withheld := EstimateWithholding(monthly, invoices, byID, config, income.WithholdingDispensed, j.Engine, start, end)
+
+	payments, err := s.paymentsOnAccount(ctx, userID, user, year)
+	if err != nil {
+		return nil, err
+	}
+	settlement := j.Engine.SimulateSettlement(config, tax.SettlementInput{
+		Income:            income,
+		Withholding:       withheld,
+		PaymentsOnAccount: payments.TotalExpected,
+	})
+
+	estimate := &TaxEstimate{
+		FiscalYear:          year,
+		GrossAnnualIncome:   income.Gross + income.Employment.Gross,
+		EmploymentIncome:    income.Employment.Gross,
+		EmploymentTaxable:   irs.EmploymentTaxable,
+		TaxableIncome:       irs.TaxableIncome,
+		ExpenseShortfall:    settlement.AnexoB.Justification.Shortfall,
+		IRSJovemExempt:      irs.IRSJovemExempt,
+		IFICITax:            irs.IFICITax,
+		IRSAmount:           irs.TotalTax,
+		IRSEffectiveRate:    irs.EffectiveRate,
+		SocialSecurity:      ss.TotalContributions,
+		SSQuarterlyPayment:  ss.TotalContributions / 4,
+		WithholdingInvoiced: invoiced,
+		WithholdingTotal:    withheld,
+		EmploymentWithheld:  income.Employment.Withholding,
+		EmploymentSS:        income.Employment.SocialSecurity,
+		PaymentsOnAccount:   settlement.PaymentsOnAccount,
+		SettlementBalance:   settlement.Balance,
+		Refund:              settlement.Refund,
+		AmountDue:           settlement.AmountDue,
+		NetAnnualIncome: income.Gross + income.Employment.Gross - irs.TotalTax -
+			ss.TotalContributions - income.Employment.SocialSecurity,
+	}
+	estimate.MonthlyNet = estimate.NetAnnualIncome / 12
+
+	var declared money.Cents
+	for _, d := range ss.Declarations {
+		if d.Gross > 0 {
+			estimate.SSMonthlyBase += d.MonthlyBase
+			declared++
+		}
+	}
+	if declared > 0 {
+		estimate.SSMonthlyBase /= declared
+	}
+
+	for _, b := range irs.BracketBreakdown {
+		estimate.BracketBreakdown = append(estimate.BracketBreakdown, BracketDetail{
+			BracketLabel:   b.BracketLabel,
+			TaxableInBrack: b.TaxableInBracket,
+			Rate:           b.Rate,
+			TaxAmount:      b.TaxAmount,
+		})
+	}
+
+	return estimate, nil
+}
+
+// SimulateSettlement assesses a year's IRS for the user's household, crediting
+// the withholding recorded on the year's invoices and the payments on account
+// recorded for the year.
+func (s *Service) SimulateSettlement(ctx context.Context, userID uuid.UUID, year int, input SimulateSettlementInput) (*tax.Settlement, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	j := s.jurisdiction(user)
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+
+	income, err := s.yearTaxIncome(ctx, userID, user, year)
+	if err != nil {
+		return nil, err
+	}
+	start, end := YearBounds(year, location(user))
+	invoices, err := s.repo.ListInvoices(ctx, userID, nil, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.repo.ListPaymentsOnAccount(ctx, userID, year)
+	if err != nil {
+		return nil, err
+	}
+	var paid money.Cents
+	for _, p := range payments {
+		paid += p.AmountCents
+	}
+
+	settlement := j.Engine.SimulateSettlement(j.Config(year), tax.SettlementInput{
+		Income:            income,
+		Household:         input.household(),
+		Expenses:          input.expenses(),
+		Withholding:       InvoiceWithholding(invoices, start, end),
+		PaymentsOnAccount: paid,
+	})
+	return &settlement, nil
 }
 
 // Invoice management
 
+// CreateInvoice records an invoice. Without an explicit rate, withholding
+// follows the client and the user's dispensation, counting the income already
+// invoiced for the year of the period; crossing the dispensation threshold
+// sets the invoice's Warning.
 func (s *Service) CreateInvoice(ctx context.Context, userID uuid.UUID, input CreateInvoiceInput) (*Invoice, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	j := s.jurisdiction(user)
 	gross := money.Cents(input.GrossAmountCents)
-	withholding := money.Cents(float64(gross) * input.WithholdingRate)
+
+	if err := s.checkWorkplace(ctx, userID, input.WorkplaceID); err != nil {
+		return nil, err
+	}
+	wp, err := s.workplaceRepo.GetWorkplaceByID(ctx, input.WorkplaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	year := input.PeriodStart.Year()
+	config := j.Config(year)
+	optedIn := withholdingDispensed(user)
+	var invoicedToDate money.Cents
+	var warning string
+	if optedIn {
+		start, end := YearBounds(year, location(user))
+		invoices, err := s.repo.ListInvoices(ctx, userID, nil, start, end)
+		if err != nil {
+			return nil, err
+		}
+		for _, inv := range invoices {
+			if !inv.PeriodStart.Before(start) && inv.PeriodStart.Before(end) {
+				invoicedToDate += inv.GrossAmountCents
+			}
+		}
+		if crossesDispensation(config, optedIn, invoicedToDate, gross) {
+			warning = DispensationCrossedWarning
+		}
+	}
+
+	rate := tax.WithholdingRate(config, payerOf(wp), tax.Dispensed(config, optedIn, invoicedToDate+gross))
+	if input.WithholdingRate != nil {
+		rate = *input.WithholdingRate
+	}
+	withholding := money.Cents(float64(gross) * rate)
 	iva := money.Cents(float64(gross) * input.IVARate)
 	net := gross - withholding + iva
 
+	dueAt := input.DueAt
+	if dueAt == nil && input.IssuedAt != nil {
+		terms := workplace.DefaultPaymentTermsDays
+		if wp != nil {
+			terms = wp.PaymentTermsDays
+		}
+		due := input.IssuedAt.AddDate(0, 0, terms)
+		dueAt = &due
+	}
+
 	invoice := &Invoice{
 		ID:               uuid.New(),
 		UserID:           userID,
@@ -57,14 +922,16 @@ func (s *Service) CreateInvoice(ctx context.Context, userID uuid.UUID, input Cre
 		PeriodStart:      input.PeriodStart,
 		PeriodEnd:        input.PeriodEnd,
 		GrossAmountCents: gross,
-		WithholdingRate:  input.WithholdingRate,
+		WithholdingRate:  rate,
 		WithholdingCents: withholding,
 		IVARate:          input.IVARate,
 		IVACents:         iva,
 		NetAmountCents:   net,
 		InvoiceNumber:    input.InvoiceNumber,
 		IssuedAt:         input.IssuedAt,
+		DueAt:            dueAt,
 		Notes:            input.Notes,
+		Warning:          warning,
 		CreatedAt:        time.Now(),
 		UpdatedAt:        time.Now(),
 	}
@@ -86,3 +953,548 @@ func (s *Service) GetInvoice(ctx context.Context, id uuid.UUID) (*Invoice, error
 func (s *Service) DeleteInvoice(ctx context.Context, id uuid.UUID) error {
 	return s.repo.DeleteInvoice(ctx, id)
 }
+
+// Expenses
+
+func (s *Service) CreateExpense(ctx context.Context, userID uuid.UUID, input CreateExpenseInput) (*Expense, error) {
+	if _, ok := RuleForCategory(input.Category); !ok {
+		return nil, ErrInvalidExpenseCategory
+	}
+	if input.WorkplaceID != nil {
+		if err := s.checkWorkplace(ctx, userID, *input.WorkplaceID); err != nil {
+			return nil, err
+		}
+	}
+	inEFatura := true
+	if input.InEFatura != nil {
+		inEFatura = *input.InEFatura
+	}
+
+	expense := &Expense{
+		ID:            uuid.New(),
+		UserID:        userID,
+		WorkplaceID:   input.WorkplaceID,
+		Date:          input.Date,
+		Category:      input.Category,
+		Description:   input.Description,
+		SupplierNIF:   input.SupplierNIF,
+		AmountCents:   money.Cents(input.AmountCents),
+		IVACents:      money.Cents(input.IVACents),
+		InEFatura:     inEFatura,
+		AttachmentRef: input.AttachmentRef,
+		Notes:         input.Notes,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	if err := s.repo.CreateExpense(ctx, expense); err != nil {
+		return nil, err
+	}
+	return expense, nil
+}
+
+// checkWorkplace makes sure a workplace an expense or scenario refers to is
+// one of the user's.
+func (s *Service) checkWorkplace(ctx context.Context, userID, id uuid.UUID) error {
+	wp, err := s.workplaceRepo.GetWorkplaceByID(ctx, id)
+	if err != nil || wp.UserID != userID {
+		return workplace.ErrWorkplaceNotFound
+	}
+	return nil
+}
+
+func (s *Service) GetExpense(ctx context.Context, userID, id uuid.UUID) (*Expense, error) {
+	expense, err := s.repo.GetExpenseByID(ctx, id)
+	if err != nil || expense.UserID != userID {
+		return nil, ErrExpenseNotFound
+	}
+	return expense, nil
+}
+
+func (s *Service) ListExpenses(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]*Expense, error) {
+	return s.repo.ListExpenses(ctx, userID, start, end)
+}
+
+func (s *Service) UpdateExpense(ctx context.Context, userID, id uuid.UUID, input UpdateExpenseInput) (*Expense, error) {
+	expense, err := s.GetExpense(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.WorkplaceID != nil {
+		if err := s.checkWorkplace(ctx, userID, *input.WorkplaceID); err != nil {
+			return nil, err
+		}
+		expense.WorkplaceID = input.WorkplaceID
+	}
+	if input.Date != nil {
+		expense.Date = *input.Date
+	}
+	if input.Category != nil {
+		if _, ok := RuleForCategory(*input.Category); !ok {
+			return nil, ErrInvalidExpenseCategory
+		}
+		expense.Category = *input.Category
+	}
+	if input.Description != nil {
+		expense.Description = input.Description
+	}
+	if input.SupplierNIF != nil {
+		expense.SupplierNIF = input.SupplierNIF
+	}
+	if input.AmountCents != nil {
+		expense.AmountCents = money.Cents(*input.AmountCents)
+	}
+	if input.IVACents != nil {
+		expense.IVACents = money.Cents(*input.IVACents)
+	}
+	if input.InEFatura != nil {
+		expense.InEFatura = *input.InEFatura
+	}
+	if input.AttachmentRef != nil {
+		expense.AttachmentRef = input.AttachmentRef
+	}
+	if input.Notes != nil {
+		expense.Notes = input.Notes
+	}
+	expense.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateExpense(ctx, expense); err != nil {
+		return nil, err
+	}
+	return expense, nil
+}
+
+func (s *Service) DeleteExpense(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.GetExpense(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.repo.DeleteExpense(ctx, id)
+}
+
+// GetExpenseReport totals a year's expenses and checks them against the
+// simplified regime's justification requirement for that year's income.
+func (s *Service) GetExpenseReport(ctx context.Context, userID uuid.UUID, year int) (*ExpenseReport, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	earnings, err := s.repo.GetYearlyEarnings(ctx, userID, year, location(user))
+	if err != nil {
+		return nil, err
+	}
+	return s.expenseReport(ctx, userID, user, year, earnings)
+}
+
+func (s *Service) expenseReport(ctx context.Context, userID uuid.UUID, user *auth.User, year int, earnings *EarningsSummary) (*ExpenseReport, error) {
+	j := s.jurisdiction(user)
+	start, end := YearBounds(year, location(user))
+	expenses, err := s.repo.ListExpenses(ctx, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ExpenseReport{FiscalYear: year, GrossIncome: earnings.GrossEarnings}
+	report.ByCategory, report.TotalExpenses, report.SimplifiedEligible, report.OrganizedDeductible = SummarizeExpenses(expenses)
+	report.Justification = j.Engine.CalculateExpenseJustification(j.Config(year), tax.Income{
+		Gross:             earnings.GrossEarnings,
+		JustifiedExpenses: report.SimplifiedEligible,
+	})
+	return report, nil
+}
+
+// Income goals
+
+func (s *Service) CreateGoal(ctx context.Context, userID uuid.UUID, input CreateGoalInput) (*IncomeGoal, error) {
+	basis := GoalBasisGross
+	if input.Basis != "" {
+		basis = input.Basis
+	}
+	if basis != GoalBasisGross && basis != GoalBasisNet {
+		return nil, ErrInvalidGoal
+	}
+	if input.Year == 0 || input.TargetCents <= 0 || (input.Month != nil && (*input.Month < 1 || *input.Month > 12)) {
+		return nil, ErrInvalidGoal
+	}
+
+	goal := &IncomeGoal{
+		ID:          uuid.New(),
+		UserID:      userID,
+		WorkplaceID: input.WorkplaceID,
+		Year:        input.Year,
+		Month:       input.Month,
+		Basis:       basis,
+		TargetCents: money.Cents(input.TargetCents),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	if err := s.repo.CreateGoal(ctx, goal); err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+func (s *Service) UpdateGoal(ctx context.Context, userID, id uuid.UUID, input UpdateGoalInput) (*IncomeGoal, error) {
+	goal, err := s.getGoal(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Basis != nil {
+		if *input.Basis != GoalBasisGross && *input.Basis != GoalBasisNet {
+			return nil, ErrInvalidGoal
+		}
+		goal.Basis = *input.Basis
+	}
+	if input.TargetCents != nil {
+		if *input.TargetCents <= 0 {
+			return nil, ErrInvalidGoal
+		}
+		goal.TargetCents = money.Cents(*input.TargetCents)
+	}
+	goal.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateGoal(ctx, goal); err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+func (s *Service) DeleteGoal(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.getGoal(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.repo.DeleteGoal(ctx, id)
+}
+
+func (s *Service) getGoal(ctx context.Context, userID, id uuid.UUID) (*IncomeGoal, error) {
+	goal, err := s.repo.GetGoalByID(ctx, id)
+	if err != nil || goal.UserID != userID {
+		return nil, ErrGoalNotFound
+	}
+	return goal, nil
+}
+
+func (s *Service) ListScenarios(ctx context.Context, userID uuid.UUID, year int) ([]*Scenario, error) {
+	return s.repo.ListScenarios(ctx, userID, year)
+}
+
+func (s *Service) CreateScenario(ctx context.Context, userID uuid.UUID, input CreateScenarioInput) (*Scenario, error) {
+	if input.Year == 0 || input.Name == "" {
+		return nil, ErrInvalidScenario
+	}
+	if err := input.Overrides.validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkScenarioWorkplaces(ctx, userID, input.Overrides); err != nil {
+		return nil, err
+	}
+
+	scenario := &Scenario{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Year:      input.Year,
+		Name:      input.Name,
+		Overrides: input.Overrides,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := s.repo.CreateScenario(ctx, scenario); err != nil {
+		return nil, err
+	}
+	return scenario, nil
+}
+
+func (s *Service) UpdateScenario(ctx context.Context, userID, id uuid.UUID, input UpdateScenarioInput) (*Scenario, error) {
+	scenario, err := s.getScenario(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		if *input.Name == "" {
+			return nil, ErrInvalidScenario
+		}
+		scenario.Name = *input.Name
+	}
+	if input.Overrides != nil {
+		if err := input.Overrides.validate(); err != nil {
+			return nil, err
+		}
+		if err := s.checkScenarioWorkplaces(ctx, userID, *input.Overrides); err != nil {
+			return nil, err
+		}
+		scenario.Overrides = *input.Overrides
+	}
+	scenario.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateScenario(ctx, scenario); err != nil {
+		return nil, err
+	}
+	return scenario, nil
+}
+
+func (s *Service) DeleteScenario(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.getScenario(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.repo.DeleteScenario(ctx, id)
+}
+
+// checkScenarioWorkplaces makes sure every workplace a scenario changes or
+// adds shifts at is one of the user's.
+func (s *Service) checkScenarioWorkplaces(ctx context.Context, userID uuid.UUID, overrides ScenarioOverrides) error {
+	for _, o := range overrides.Workplaces {
+		if err := s.checkWorkplace(ctx, userID, o.WorkplaceID); err != nil {
+			return ErrInvalidScenario
+		}
+	}
+	for _, shift := range overrides.AddShifts {
+		if err := s.checkWorkplace(ctx, userID, shift.WorkplaceID); err != nil {
+			return ErrInvalidScenario
+		}
+	}
+	return nil
+}
+
+func (s *Service) getScenario(ctx context.Context, userID, id uuid.UUID) (*Scenario, error) {
+	scenario, err := s.repo.GetScenarioByID(ctx, id)
+	if err != nil || scenario.UserID != userID {
+		return nil, ErrScenarioNotFound
+	}
+	return scenario, nil
+}
+
+// CompareScenarios estimates the year's tax under each of the user's saved
+// scenarios, side by side with the baseline of the year's projected earnings
+// and the current tax profile. A scenario that no longer applies, such as one
+// adding shifts at a deleted workplace, is returned with its error.
+func (s *Service) CompareScenarios(ctx context.Context, userID uuid.UUID, year int) (*ScenarioComparison, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	j := s.jurisdiction(user)
+	loc := location(user)
+	config := j.Config(year)
+
+	income, err := s.yearTaxIncome(ctx, userID, user, year)
+	if err != nil {
+		return nil, err
+	}
+	earnings, err := s.repo.GetYearlyEarnings(ctx, userID, year, loc)
+	if err != nil {
+		return nil, err
+	}
+	baseline := ScenarioBaseline{
+		Income:   income,
+		Earnings: earnings.ByWorkplace,
+		Profile:  TaxProfile{Regime: income.Regime},
+	}
+	if user != nil {
+		baseline.Profile.BirthYear = user.BirthYear
+		baseline.Profile.IRSJovemFirstYear = user.IRSJovemFirstYear
+		baseline.Profile.IFICIStartYear = user.IFICIStartYear
+	}
+
+	scenarios, err := s.repo.ListScenarios(ctx, userID, year)
+	if err != nil {
+		return nil, err
+	}
+
+	base := j.Engine.CalculateAnnualSummary(config, income)
+	comparison := &ScenarioComparison{
+		Year:      year,
+		Baseline:  ScenarioResult{Name: "Baseline", Income: income, Summary: base},
+		Scenarios: []ScenarioResult{},
+	}
+
+	for _, scenario := range scenarios {
+		id := scenario.ID
+		invalid := ScenarioResult{ScenarioID: &id, Name: scenario.Name}
+
+		var added money.Cents
+		var missing bool
+		for _, shift := range scenario.Overrides.AddShifts {
+			wp, err := s.workplaceRepo.GetWorkplaceByID(ctx, shift.WorkplaceID)
+			if err != nil || wp.UserID != userID {
+				missing = true
+				break
+			}
+			rules, err := s.workplaceRepo.ListPricingRules(ctx, wp.ID, true)
+			if err != nil {
+				return nil, err
+			}
+			added += ScenarioShiftEarnings(year, shift, wp, rules, loc)
+		}
+		if missing {
+			invalid.Error = "adds shifts at a workplace that no longer exists"
+			comparison.Scenarios = append(comparison.Scenarios, invalid)
+			continue
+		}
+
+		scenarioIncome, err := ApplyScenario(j.Engine, config, baseline, scenario.Overrides, added)
+		if errors.Is(err, ErrInvalidScenario) {
+			invalid.Error = "combines IRS Jovem and IFICI with the current tax profile"
+			comparison.Scenarios = append(comparison.Scenarios, invalid)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		comparison.Scenarios = append(comparison.Scenarios, CompareScenario(ScenarioResult{
+			ScenarioID: &id,
+			Name:       scenario.Name,
+			Income:     scenarioIncome,
+			Summary:    j.Engine.CalculateAnnualSummary(config, scenarioIncome),
+		}, base))
+	}
+
+	return comparison, nil
+}
+
+// GetGoalProgress reports progress on each of the year's goals as of asOf.
+// Net goals use the ratio of net to gross income the tax engine estimates for
+// the year's projected earnings.
+func (s *Service) GetGoalProgress(ctx context.Context, userID uuid.UUID, year int, asOf time.Time) ([]GoalProgress, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	j := s.jurisdiction(user)
+	goals, err := s.repo.ListGoals(ctx, userID, year)
+	if err != nil {
+		return nil, err
+	}
+	if len(goals) == 0 {
+		return []GoalProgress{}, nil
+	}
+
+	loc := location(user)
+	yearStart, yearEnd := YearBounds(year, loc)
+	segments, err := s.repo.ListEarningSegments(ctx, userID, yearStart, yearEnd)
+	if err != nil {
+		return nil, err
+	}
+	var projectedGross money.Cents
+	for _, seg := range segments {
+		if seg, ok := clipSegment(seg, yearStart, yearEnd); ok {
+			projectedGross += seg.Amount
+		}
+	}
+	netRatio := 1.0
+	if projectedGross > 0 {
+		// The year's income under the user's regime, special regimes and
+		// salaries, with the projected earnings in place of those so far
+		income, err := s.yearTaxIncome(ctx, userID, user, year)
+		if err != nil {
+			return nil, err
+		}
+		config := j.Config(year)
+		income = WithGross(j.Engine, config, income, projectedGross)
+		summary := j.Engine.CalculateAnnualSummary(config, income)
+		// Category A keeps its own net; the rest of the taxes fall on the earnings
+		net := summary.NetIncome - (income.Employment.Gross - income.Employment.SocialSecurity)
+		netRatio = float64(net) / float64(projectedGross)
+	}
+
+	progress := make([]GoalProgress, 0, len(goals))
+	for _, goal := range goals {
+		start, end := GoalBounds(goal, loc)
+		progress = append(progress, BuildGoalProgress(goal, start, end, asOf, segments, netRatio))
+	}
+	return progress, nil
+}
+
+// Payment reconciliation
+
+// ProposePaymentMatches parses a bank statement and matches its credits against the
+// user's unpaid invoices. The result is only a proposal; see ConfirmPayments.
+func (s *Service) ProposePaymentMatches(ctx context.Context, userID uuid.UUID, format StatementFormat, statement io.Reader, mapping *CSVColumnMapping) (*ReconciliationResult, error) {
+	txs, err := ParseStatement(format, statement, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices, err := s.repo.ListUnpaidInvoices(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	wps, err := s.workplaceRepo.ListWorkplacesByUser(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*workplace.Workplace, len(wps))
+	for _, wp := range wps {
+		byID[wp.ID] = wp
+	}
+
+	return MatchPayments(txs, invoices, byID, DefaultMatchLateDays), nil
+}
+
+// ConfirmPayments marks invoices as paid and moves the earnings of the shifts
+// they cover to EarningStatusPaid. Only shifts that were worked, completed or
+// confirmed, are paid: cancelled shifts and those still scheduled keep their
+// earnings. All the payments are recorded together or not at all.
+func (s *Service) ConfirmPayments(ctx context.Context, userID uuid.UUID, payments []ConfirmPaymentInput) ([]*Invoice, error) {
+	var confirmed []*Invoice
+	var shiftIDs []uuid.UUID
+	for _, p := range payments {
+		inv, err := s.repo.GetInvoiceByID(ctx, p.InvoiceID)
+		if err != nil || inv.UserID != userID {
+			return nil, ErrInvoiceNotFound
+		}
+
+		paidAt := p.PaidAt
+		inv.PaidAt = &paidAt
+		inv.UpdatedAt = time.Now()
+
+		shifts, err := s.scheduleRepo.ListShifts(ctx, schedule.ShiftFilter{
+			UserID:      userID,
+			WorkplaceID: &inv.WorkplaceID,
+			Start:       inv.PeriodStart,
+			End:         inv.PeriodEnd.AddDate(0, 0, 1),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, shift := range shifts {
+			if shift.Status == schedule.ShiftStatusCompleted || shift.Status == schedule.ShiftStatusConfirmed {
+				shiftIDs = append(shiftIDs, shift.ID)
+			}
+		}
+
+		confirmed = append(confirmed, inv)
+	}
+	if err := s.repo.RecordPayments(ctx, confirmed, shiftIDs); err != nil {
+		return nil, err
+	}
+	return confirmed, nil
+}
+
+// Receivables
+
+func (s *Service) GetReceivables(ctx context.Context, userID uuid.UUID, asOf time.Time) (*ReceivablesReport, error) {
+	unpaid, err := s.repo.ListUnpaidInvoices(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	paid, err := s.repo.ListPaidInvoices(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	wps, err := s.workplaceRepo.ListWorkplacesByUser(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*workplace.Workplace, len(wps))
+	for _, wp := range wps {
+		byID[wp.ID] = wp
+	}
+
+	return BuildReceivablesReport(asOf, unpaid, paid, byID), nil
+}
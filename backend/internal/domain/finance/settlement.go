@@ -0,0 +1,72 @@
+package finance
+
+import (
+	"time"
+
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+// SimulateSettlementInput describes the household and the personal expenses
+// that the app does not track itself.
+type SimulateSettlementInput struct {
+	Joint                    bool  `json:"joint"`
+	SpouseTaxableIncomeCents int64 `json:"spouse_taxable_income_cents"`
+	SpouseWithholdingCents   int64 `json:"spouse_withholding_cents"`
+	SingleParent             bool  `json:"single_parent"`
+	DependentAges            []int `json:"dependent_ages"`
+
+	GeneralFamilyCents int64 `json:"general_family_cents"`
+	HealthCents        int64 `json:"health_cents"`
+	EducationCents     int64 `json:"education_cents"`
+}
+
+func (in SimulateSettlementInput) validate() error {
+	for _, v := range []int64{in.SpouseTaxableIncomeCents, in.SpouseWithholdingCents, in.GeneralFamilyCents, in.HealthCents, in.EducationCents} {
+		if v < 0 {
+			return ErrInvalidSettlement
+		}
+	}
+	if !in.Joint && (in.SpouseTaxableIncomeCents != 0 || in.SpouseWithholdingCents != 0) {
+		return ErrInvalidSettlement
+	}
+	if in.Joint && in.SingleParent {
+		return ErrInvalidSettlement
+	}
+	for _, age := range in.DependentAges {
+		if age < 0 {
+			return ErrInvalidSettlement
+		}
+	}
+	return nil
+}
+
+func (in SimulateSettlementInput) household() tax.Household {
+	return tax.Household{
+		Joint:               in.Joint,
+		SpouseTaxableIncome: money.Cents(in.SpouseTaxableIncomeCents),
+		SpouseWithholding:   money.Cents(in.SpouseWithholdingCents),
+		SingleParent:        in.SingleParent,
+		DependentAges:       in.DependentAges,
+	}
+}
+
+func (in SimulateSettlementInput) expenses() tax.PersonalExpenses {
+	return tax.PersonalExpenses{
+		GeneralFamily: money.Cents(in.GeneralFamilyCents),
+		Health:        money.Cents(in.HealthCents),
+		Education:     money.Cents(in.EducationCents),
+	}
+}
+
+// InvoiceWithholding totals the withholding on invoices whose period starts
+// in [start, end), so an invoice spanning New Year counts once.
+func InvoiceWithholding(invoices []*Invoice, start, end time.Time) money.Cents {
+	var total money.Cents
+	for _, inv := range invoices {
+		if !inv.PeriodStart.Before(start) && inv.PeriodStart.Before(end) {
+			total += inv.WithholdingCents
+		}
+	}
+	return total
+}
@@ -0,0 +1,430 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/auth"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/workplace"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+type mockSettlementRepo struct {
+	Repository
+	gross    money.Cents
+	expenses []*Expense
+	invoices []*Invoice
+	payments []*PaymentOnAccount
+	salaries []*SalaryRecord
+}
+
+func (m *mockSettlementRepo) GetYearlyEarnings(_ context.Context, _ uuid.UUID, _ int, _ *time.Location) (*EarningsSummary, error) {
+	return &EarningsSummary{GrossEarnings: m.gross}, nil
+}
+
+// GetMonthlyEarnings spreads the year's gross evenly over the months.
+func (m *mockSettlementRepo) GetMonthlyEarnings(_ context.Context, _ uuid.UUID, year int, _ *time.Location) ([]EarningsSummary, error) {
+	monthly := make([]EarningsSummary, 12)
+	for i := range monthly {
+		monthly[i].Period = time.Date(year, time.Month(i+1), 1, 0, 0, 0, 0, time.UTC).Format("2006-01")
+		monthly[i].GrossEarnings = m.gross / 12
+	}
+	return monthly, nil
+}
+
+func (m *mockSettlementRepo) ListExpenses(_ context.Context, _ uuid.UUID, _, _ time.Time) ([]*Expense, error) {
+	return m.expenses, nil
+}
+
+func (m *mockSettlementRepo) ListInvoices(_ context.Context, _ uuid.UUID, _ *uuid.UUID, _, _ time.Time) ([]*Invoice, error) {
+	return m.invoices, nil
+}
+
+func (m *mockSettlementRepo) ListPaymentsOnAccount(_ context.Context, _ uuid.UUID, _ int) ([]*PaymentOnAccount, error) {
+	return m.payments, nil
+}
+
+func (m *mockSettlementRepo) ListSalaryRecords(_ context.Context, _ uuid.UUID, _ int) ([]*SalaryRecord, error) {
+	return m.salaries, nil
+}
+
+func TestSimulateSettlement_CreditsInvoiceWithholding(t *testing.T) {
+	repo := &mockSettlementRepo{
+		gross: money.FromEuros(60000),
+		invoices: []*Invoice{
+			{PeriodStart: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC), WithholdingCents: money.FromEuros(1000)},
+			{PeriodStart: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), WithholdingCents: money.FromEuros(7000)},
+			{PeriodStart: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), WithholdingCents: money.FromEuros(6800)},
+		},
+		payments: []*PaymentOnAccount{
+			{FiscalYear: 2025, Instalment: 1, AmountCents: money.FromEuros(300)},
+			{FiscalYear: 2025, Instalment: 2, AmountCents: money.FromEuros(300)},
+		},
+	}
+	engine := tax.NewPortugalEngine()
+	svc := NewService(repo, nil, nil, &mockUserRepo{}, tax.DefaultRegistry())
+
+	got, err := svc.SimulateSettlement(context.Background(), uuid.New(), 2025, SimulateSettlementInput{})
+	if err != nil {
+		t.Fatalf("SimulateSettlement failed: %v", err)
+	}
+
+	// December 2024's invoice belongs to the previous year
+	if got.Withholding != money.FromEuros(13800) || got.AnexoB.Withholding != got.Withholding {
+		t.Errorf("expected 13800 EUR withheld, got %d", got.Withholding)
+	}
+
+	config := tax.ConfigForYear(2025)
+	irs := engine.CalculateIRS(config, tax.Income{Gross: repo.gross})
+	if got.CollectedTax != irs.TotalTax || got.NetTax != irs.TotalTax {
+		t.Errorf("expected collected tax %d without deductions, got %d/%d", irs.TotalTax, got.CollectedTax, got.NetTax)
+	}
+	// The payments on account recorded for the year are credited too
+	if want := irs.TotalTax - got.Withholding - money.FromEuros(600); got.PaymentsOnAccount != money.FromEuros(600) || got.Balance != want {
+		t.Errorf("expected balance %d after 600 EUR paid on account, got %d", want, got.Balance)
+	}
+}
+
+func TestSimulateSettlement_RejectsSpouseIncomeWhenFilingAlone(t *testing.T) {
+	svc := NewService(&mockSettlementRepo{}, nil, nil, &mockUserRepo{}, tax.DefaultRegistry())
+
+	_, err := svc.SimulateSettlement(context.Background(), uuid.New(), 2025, SimulateSettlementInput{SpouseTaxableIncomeCents: 100})
+	if !errors.Is(err, ErrInvalidSettlement) {
+		t.Errorf("expected ErrInvalidSettlement, got %v", err)
+	}
+}
+
+func TestSettlement_JointHouseholdWithDependents(t *testing.T) {
+	engine := tax.NewPortugalEngine()
+	config := tax.ConfigForYear(2025)
+	income := tax.Income{Gross: money.FromEuros(60000)}
+
+	single := engine.SimulateSettlement(config, tax.SettlementInput{Income: income})
+	joint := engine.SimulateSettlement(config, tax.SettlementInput{
+		Income: income,
+		Household: tax.Household{
+			Joint:               true,
+			SpouseTaxableIncome: money.FromEuros(10000),
+			DependentAges:       []int{2, 4, 9},
+		},
+		Expenses: tax.PersonalExpenses{
+			GeneralFamily: money.FromEuros(2000),
+			Health:        money.FromEuros(10000),
+			Education:     money.FromEuros(5000),
+		},
+		Withholding: money.FromEuros(10000),
+	})
+
+	if joint.Quotient != 2 || joint.TaxableIncome != single.TaxableIncome+money.FromEuros(10000) {
+		t.Fatalf("unexpected household taxable income %d (quotient %d)", joint.TaxableIncome, joint.Quotient)
+	}
+	if joint.CollectedTax >= single.CollectedTax {
+		t.Errorf("expected splitting to lower the collected tax, got %d vs %d alone", joint.CollectedTax, single.CollectedTax)
+	}
+
+	d := joint.Deductions
+	// 600 each, plus 126 for the first and 300 for the second child up to 6
+	if d.Dependents != money.FromEuros(2226) {
+		t.Errorf("expected 2226 EUR for dependents, got %d", d.Dependents)
+	}
+	if d.GeneralFamily != money.FromEuros(500) {
+		t.Errorf("expected general family expenses capped at 250 EUR each, got %d", d.GeneralFamily)
+	}
+	if d.Health != money.FromEuros(1000) || d.Education != money.FromEuros(800) {
+		t.Errorf("expected health and education at their caps, got %d and %d", d.Health, d.Education)
+	}
+	if d.Ceiling == 0 || d.Total != d.Dependents+d.GeneralFamily+d.Ceiling {
+		t.Errorf("expected health and education limited by the art. 78(7) ceiling, got %+v", d)
+	}
+
+	if joint.NetTax != joint.CollectedTax-d.Total || joint.Balance != joint.NetTax-joint.Withholding {
+		t.Errorf("inconsistent settlement %+v", joint)
+	}
+	if joint.Balance < 0 && joint.Refund != -joint.Balance {
+		t.Errorf("expected refund %d, got %d", -joint.Balance, joint.Refund)
+	}
+}
+
+func TestCompareRegimes_HighExpensesFavourOrganized(t *testing.T) {
+	repo := &mockSettlementRepo{
+		gross: money.FromEuros(60000),
+		expenses: []*Expense{
+			{Category: ExpenseCategoryRent, AmountCents: money.FromEuros(30000), InEFatura: true},
+		},
+	}
+	users := &mockUserRepo{user: &auth.User{TaxRegime: auth.TaxRegimeSimplified}}
+	svc := NewService(repo, nil, nil, users, tax.DefaultRegistry())
+
+	got, err := svc.CompareRegimes(context.Background(), uuid.New(), 2025)
+	if err != nil {
+		t.Fatalf("CompareRegimes failed: %v", err)
+	}
+
+	if got.Current != tax.RegimeSimplified || got.Recommended != tax.RegimeOrganized {
+		t.Errorf("expected a switch from simplified to organized, got %q -> %q", got.Current, got.Recommended)
+	}
+	if got.Simplified.TaxableIncome != money.FromEuros(45000) {
+		t.Errorf("expected 75%% of gross taxed under the simplified regime, got %d", got.Simplified.TaxableIncome)
+	}
+	// Monthly base 3,500 EUR at 21.4% for twelve months
+	contributions := money.FromEuros(8988)
+	if got.Organized.Deductions != money.FromEuros(30000)+contributions {
+		t.Errorf("expected expenses and contributions deducted, got %d", got.Organized.Deductions)
+	}
+	if got.Organized.TaxableIncome != money.FromEuros(60000-30000-8988) {
+		t.Errorf("unexpected organized taxable income %d", got.Organized.TaxableIncome)
+	}
+	if got.Savings != got.Simplified.TotalTax-got.Organized.TotalTax || got.Savings <= 0 {
+		t.Errorf("unexpected savings %d", got.Savings)
+	}
+	if got.BreakEvenExpenses != money.FromEuros(60000-8988-45000) {
+		t.Errorf("unexpected break-even expenses %d", got.BreakEvenExpenses)
+	}
+}
+
+func TestTaxIncome_IRSJovem(t *testing.T) {
+	first, born := 2023, 2000
+	repo := &mockSettlementRepo{gross: money.FromEuros(60000)}
+	users := &mockUserRepo{user: &auth.User{IRSJovemFirstYear: &first, BirthYear: &born}}
+	engine := tax.NewPortugalEngine()
+	svc := NewService(repo, nil, nil, users, tax.DefaultRegistry())
+
+	income, err := svc.TaxIncome(context.Background(), uuid.New(), 2025)
+	if err != nil {
+		t.Fatalf("TaxIncome failed: %v", err)
+	}
+	if income.IRSJovemYear != 3 || income.IFICI {
+		t.Fatalf("expected the third year of IRS Jovem, got %+v", income)
+	}
+
+	config := tax.ConfigForYear(2025)
+	summary := engine.CalculateAnnualSummary(config, income)
+	// 75% of 60,000 EUR is over the cap of 55 x IAS
+	if summary.IRSJovemExempt != money.FromEuros(55*522.50) {
+		t.Errorf("expected the exemption capped at 28737.50 EUR, got %d", summary.IRSJovemExempt)
+	}
+	full := engine.CalculateAnnualSummary(config, tax.Income{Gross: income.Gross})
+	if summary.IRSAmount >= full.IRSAmount || summary.SSAnnual != full.SSAnnual {
+		t.Errorf("expected less IRS and unchanged contributions, got %+v vs %+v", summary, full)
+	}
+
+	// Past the age limit the exemption no longer applies
+	born = 1985
+	if income, _ := svc.TaxIncome(context.Background(), uuid.New(), 2025); income.IRSJovemYear != 0 {
+		t.Errorf("expected no IRS Jovem at 40, got year %d", income.IRSJovemYear)
+	}
+}
+
+func TestTaxIncome_IFICI(t *testing.T) {
+	start := 2024
+	repo := &mockSettlementRepo{gross: money.FromEuros(100000)}
+	users := &mockUserRepo{user: &auth.User{IFICIStartYear: &start}}
+	engine := tax.NewPortugalEngine()
+	svc := NewService(repo, nil, nil, users, tax.DefaultRegistry())
+
+	income, err := svc.TaxIncome(context.Background(), uuid.New(), 2025)
+	if err != nil {
+		t.Fatalf("TaxIncome failed: %v", err)
+	}
+	if !income.IFICI {
+		t.Fatal("expected IFICI to apply in its second year")
+	}
+
+	summary := engine.CalculateAnnualSummary(tax.ConfigForYear(2025), income)
+	if summary.IFICITax != summary.TaxableIncome/5 || summary.IRSAmount != summary.IFICITax {
+		t.Errorf("expected IRS at a flat 20%% of %d, got %d (total %d)", summary.TaxableIncome, summary.IFICITax, summary.IRSAmount)
+	}
+
+	if income, _ := svc.TaxIncome(context.Background(), uuid.New(), 2034); income.IFICI {
+		t.Error("expected IFICI to end after ten years")
+	}
+}
+
+func TestTaxIncome_SalaryRecords(t *testing.T) {
+	repo := &mockSettlementRepo{
+		gross: money.FromEuros(30000),
+		salaries: []*SalaryRecord{
+			{GrossCents: money.FromEuros(2500), WithholdingCents: money.FromEuros(300), SocialSecurityCents: money.FromEuros(275)},
+			{GrossCents: money.FromEuros(2500), WithholdingCents: money.FromEuros(300), SocialSecurityCents: money.FromEuros(275)},
+		},
+	}
+	engine := tax.NewPortugalEngine()
+	svc := NewService(repo, nil, nil, &mockUserRepo{}, tax.DefaultRegistry())
+
+	income, err := svc.TaxIncome(context.Background(), uuid.New(), 2025)
+	if err != nil {
+		t.Fatalf("TaxIncome failed: %v", err)
+	}
+	want := tax.Employment{Gross: money.FromEuros(5000), Withholding: money.FromEuros(600), SocialSecurity: money.FromEuros(550)}
+	if income.Gross != repo.gross || income.Employment != want {
+		t.Fatalf("expected the payslips added up as Category A, got %+v", income)
+	}
+
+	summary := engine.CalculateAnnualSummary(tax.ConfigForYear(2025), income)
+	if summary.GrossIncome != money.FromEuros(35000) || summary.EmploymentIncome != want.Gross {
+		t.Errorf("expected both categories in the gross, got %d", summary.GrossIncome)
+	}
+	if alone := engine.CalculateIRS(tax.ConfigForYear(2025), tax.Income{Gross: repo.gross}); summary.IRSAmount <= alone.TotalTax {
+		t.Errorf("expected the salary to add to the IRS of %d, got %d", alone.TotalTax, summary.IRSAmount)
+	}
+	if net := summary.GrossIncome - summary.IRSAmount - summary.SSAnnual - want.SocialSecurity; summary.NetIncome != net {
+		t.Errorf("expected net income %d, got %d", net, summary.NetIncome)
+	}
+}
+
+func TestCreateSalaryRecord_Validates(t *testing.T) {
+	svc := NewService(&mockSettlementRepo{}, nil, nil, &mockUserRepo{}, tax.DefaultRegistry())
+
+	_, err := svc.CreateSalaryRecord(context.Background(), uuid.New(), CreateSalaryRecordInput{
+		Employer:            "Hospital",
+		Period:              time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		GrossCents:          100000,
+		WithholdingCents:    80000,
+		SocialSecurityCents: 30000,
+	})
+	if !errors.Is(err, ErrInvalidSalaryRecord) {
+		t.Errorf("expected deductions above the gross to be rejected, got %v", err)
+	}
+}
+
+// mockEstimateRepo earns the year's income from one workplace in January.
+type mockEstimateRepo struct {
+	mockSettlementRepo
+	workplaceID uuid.UUID
+}
+
+func (m *mockEstimateRepo) GetYearlyEarnings(_ context.Context, _ uuid.UUID, _ int, _ *time.Location) (*EarningsSummary, error) {
+	return &EarningsSummary{
+		GrossEarnings: m.gross,
+		ByWorkplace:   []WorkplaceEarnings{{WorkplaceID: m.workplaceID, Gross: m.gross}},
+	}, nil
+}
+
+func (m *mockEstimateRepo) GetEarningsSummary(_ context.Context, _ uuid.UUID, start, _ time.Time) (*EarningsSummary, error) {
+	if start.Month() == time.January {
+		return &EarningsSummary{GrossEarnings: m.gross}, nil
+	}
+	return &EarningsSummary{}, nil
+}
+
+func (m *mockEstimateRepo) GetMonthlyEarnings(_ context.Context, _ uuid.UUID, year int, _ *time.Location) ([]EarningsSummary, error) {
+	monthly := make([]EarningsSummary, 12)
+	for i := range monthly {
+		monthly[i].Period = time.Date(year, time.Month(i+1), 1, 0, 0, 0, 0, time.UTC).Format("2006-01")
+	}
+	monthly[0].GrossEarnings = m.gross
+	monthly[0].ByWorkplace = []WorkplaceEarnings{{WorkplaceID: m.workplaceID, Gross: m.gross}}
+	return monthly, nil
+}
+
+type mockWorkplaceRepo struct {
+	workplace.Repository
+	workplaces []*workplace.Workplace
+}
+
+func (m *mockWorkplaceRepo) GetWorkplaceByID(_ context.Context, id uuid.UUID) (*workplace.Workplace, error) {
+	for _, wp := range m.workplaces {
+		if wp.ID == id {
+			return wp, nil
+		}
+	}
+	return nil, workplace.ErrWorkplaceNotFound
+}
+
+func (m *mockWorkplaceRepo) ListWorkplacesByUser(_ context.Context, _ uuid.UUID, _ bool) ([]*workplace.Workplace, error) {
+	return m.workplaces, nil
+}
+
+func TestGetTaxEstimate_CreditsWithholdingAndDeclaredContributions(t *testing.T) {
+	hospital := &workplace.Workplace{ID: uuid.New(), Name: "Hospital"}
+	repo := &mockEstimateRepo{
+		mockSettlementRepo: mockSettlementRepo{
+			gross: money.FromEuros(40000),
+			invoices: []*Invoice{{
+				WorkplaceID:      hospital.ID,
+				PeriodStart:      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				GrossAmountCents: money.FromEuros(20000),
+				WithholdingCents: money.FromEuros(4600),
+			}},
+		},
+		workplaceID: hospital.ID,
+	}
+	engine := tax.NewPortugalEngine()
+	users := &mockUserRepo{}
+	svc := NewService(repo, &mockWorkplaceRepo{workplaces: []*workplace.Workplace{hospital}}, nil, users, tax.DefaultRegistry())
+
+	got, err := svc.GetTaxEstimate(context.Background(), uuid.New(), 2025, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetTaxEstimate failed: %v", err)
+	}
+	if users.loads != 1 {
+		t.Errorf("expected the user loaded once, got %d loads", users.loads)
+	}
+
+	// The uninvoiced 20,000 EUR is withheld at the year's default 23%
+	if got.WithholdingInvoiced != money.FromEuros(4600) || got.WithholdingTotal != money.FromEuros(9200) {
+		t.Errorf("expected 4600 EUR invoiced and 9200 EUR withheld, got %d and %d", got.WithholdingInvoiced, got.WithholdingTotal)
+	}
+
+	config := tax.ConfigForYear(2025)
+	// The whole year's income in one quarter hits the 12 x IAS ceiling
+	ss := engine.CalculateSocialSecurity(config, repo.gross)
+	if got.SocialSecurity != ss.MonthlyContribution*3 || got.SSMonthlyBase != ss.MonthlyBase {
+		t.Errorf("expected one quarter of contributions at %d, got %d (base %d)", ss.MonthlyContribution, got.SocialSecurity, got.SSMonthlyBase)
+	}
+	if even := engine.CalculateAnnualSummary(config, tax.Income{Gross: repo.gross}).SSAnnual; got.SocialSecurity >= even {
+		t.Errorf("expected less than the even-quarters estimate of %d, got %d", even, got.SocialSecurity)
+	}
+
+	irs := engine.CalculateIRS(config, tax.Income{Gross: repo.gross})
+	if got.IRSAmount != irs.TotalTax || len(got.BracketBreakdown) != len(irs.BracketBreakdown) {
+		t.Errorf("expected IRS %d with its bracket breakdown, got %d", irs.TotalTax, got.IRSAmount)
+	}
+	// Payments on account are estimated from 2023, when the mock earned the
+	// same and nothing was withheld
+	onAccount := tax.PaymentsOnAccount(config, tax.PaymentOnAccountBasis{
+		AssessedTax:     engine.CalculateIRS(tax.ConfigForYear(2023), tax.Income{Gross: repo.gross}).TotalTax,
+		CategoryBIncome: repo.gross,
+		TotalIncome:     repo.gross,
+	})
+	if want := onAccount[0] + onAccount[1] + onAccount[2]; want == 0 || got.PaymentsOnAccount != want {
+		t.Errorf("expected %d paid on account, got %d", want, got.PaymentsOnAccount)
+	}
+	if want := irs.TotalTax - got.WithholdingTotal - got.PaymentsOnAccount; got.SettlementBalance != want {
+		t.Errorf("expected settlement %d, got %d", want, got.SettlementBalance)
+	}
+	if got.NetAnnualIncome != repo.gross-irs.TotalTax-got.SocialSecurity {
+		t.Errorf("unexpected net income %d", got.NetAnnualIncome)
+	}
+}
+
+func TestTaxIncome_DeclaredContributions(t *testing.T) {
+	// Activity started in July 2024: contributions are exempt until July 2025
+	started := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	repo := &mockSettlementRepo{gross: money.FromEuros(60000)}
+	users := &mockUserRepo{user: &auth.User{ActivityStartDate: &started}}
+	svc := NewService(repo, nil, nil, users, tax.DefaultRegistry())
+
+	income, err := svc.TaxIncome(context.Background(), uuid.New(), 2025)
+	if err != nil {
+		t.Fatalf("TaxIncome failed: %v", err)
+	}
+	// The year's declarations set April 2025 to March 2026, of which April
+	// to June are exempt: nine months at 3,500 EUR x 21.4%
+	if income.SSContributions == nil || *income.SSContributions != money.FromEuros(6741) {
+		t.Fatalf("expected 6741 EUR declared, got %v", income.SSContributions)
+	}
+
+	// More earnings move the declared total by the estimated difference
+	engine := tax.NewPortugalEngine()
+	config := tax.ConfigForYear(2025)
+	more := WithGross(engine, config, income, money.FromEuros(72000))
+	change := engine.CalculateSocialSecurity(config, money.FromEuros(18000)).AnnualEstimate - money.FromEuros(8988)
+	if *more.SSContributions != money.FromEuros(6741)+change || *income.SSContributions != money.FromEuros(6741) {
+		t.Errorf("expected %d after the change, got %d", money.FromEuros(6741)+change, *more.SSContributions)
+	}
+}
@@ -0,0 +1,144 @@
+package finance
+
+import (
+	"time"
+
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+// Contributions for a month are paid between these days of the next month.
+const (
+	ssPaymentFromDay = 10
+	ssPaymentByDay   = 20
+)
+
+// SSPayment is one month's contribution and the window it must be paid in.
+type SSPayment struct {
+	Month   string      `json:"month"` // YYYY-MM the contribution is for
+	DueFrom time.Time   `json:"due_from"`
+	DueBy   time.Time   `json:"due_by"`
+	Amount  money.Cents `json:"amount"`
+	Exempt  bool        `json:"exempt"` // within the first months of activity
+}
+
+// SSDeclaration is a quarterly Social Security declaration. It is filed in
+// the month after the quarter ends and sets the base for the contributions of
+// that month and the two after it.
+type SSDeclaration struct {
+	Quarter          int       `json:"quarter"`
+	PeriodStart      time.Time `json:"period_start"`
+	PeriodEnd        time.Time `json:"period_end"`
+	DeclarationMonth string    `json:"declaration_month"` // YYYY-MM
+	DeclarationDue   time.Time `json:"declaration_due"`
+
+	Gross               money.Cents `json:"gross"`
+	Estimated           bool        `json:"estimated"` // the quarter has not ended yet
+	RelevantIncome      money.Cents `json:"relevant_income"`
+	BaseAdjustment      float64     `json:"base_adjustment"`
+	MonthlyBase         money.Cents `json:"monthly_base"`
+	MonthlyContribution money.Cents `json:"monthly_contribution"`
+	Exempt              bool        `json:"exempt"` // every contribution falls within the exemption
+
+	Payments []SSPayment `json:"payments"`
+}
+
+type SocialSecurityReport struct {
+	Year           int        `json:"year"`
+	BaseAdjustment float64    `json:"base_adjustment"`
+	ExemptUntil    *time.Time `json:"exempt_until,omitempty"` // end of the first-year exemption
+
+	Declarations       []SSDeclaration `json:"declarations"`
+	TotalContributions money.Cents     `json:"total_contributions"`
+}
+
+// BuildSocialSecurityReport lays out the declarations for a year's four
+// quarters of income, from April's for January-March to the following
+// January's for October-December, with the contributions each one sets.
+// Quarters that have not ended by asOf are estimated from scheduled shifts.
+// Contributions for months before exemptUntil are not due, and each
+// declaration uses the jurisdiction's configuration for the year it is filed
+// in.
+func BuildSocialSecurityReport(year int, quarterGross [4]money.Cents, asOf time.Time, exemptUntil *time.Time, baseAdjustment float64, jurisdiction tax.Jurisdiction, loc *time.Location) *SocialSecurityReport {
+	report := &SocialSecurityReport{Year: year, BaseAdjustment: baseAdjustment, ExemptUntil: exemptUntil}
+
+	for q := 0; q < 4; q++ {
+		start := time.Date(year, time.Month(3*q+1), 1, 0, 0, 0, 0, loc)
+		end := start.AddDate(0, 3, 0)
+		declared := end
+		ss := tax.SSResult{}
+		if gross := quarterGross[q]; gross > 0 {
+			ss = jurisdiction.Engine.CalculateSSDeclaration(jurisdiction.Config(declared.Year()), gross, baseAdjustment)
+		}
+
+		d := SSDeclaration{
+			Quarter:             q + 1,
+			PeriodStart:         start,
+			PeriodEnd:           end,
+			DeclarationMonth:    declared.Format("2006-01"),
+			DeclarationDue:      declared.AddDate(0, 1, -1),
+			Gross:               quarterGross[q],
+			Estimated:           asOf.Before(end),
+			RelevantIncome:      ss.RelevantIncome,
+			BaseAdjustment:      baseAdjustment,
+			MonthlyBase:         ss.MonthlyBase,
+			MonthlyContribution: ss.MonthlyContribution,
+			Exempt:              true,
+		}
+
+		for offset := 0; offset < 3; offset++ {
+			month := declared.AddDate(0, offset, 0)
+			next := month.AddDate(0, 1, 0)
+			payment := SSPayment{
+				Month:   month.Format("2006-01"),
+				DueFrom: time.Date(next.Year(), next.Month(), ssPaymentFromDay, 0, 0, 0, 0, loc),
+				DueBy:   time.Date(next.Year(), next.Month(), ssPaymentByDay, 0, 0, 0, 0, loc),
+				Exempt:  exemptUntil != nil && month.Before(*exemptUntil),
+			}
+			if !payment.Exempt {
+				payment.Amount = ss.MonthlyContribution
+				report.TotalContributions += payment.Amount
+				d.Exempt = false
+			}
+			d.Payments = append(d.Payments, payment)
+		}
+
+		report.Declarations = append(report.Declarations, d)
+	}
+
+	return report
+}
+
+// QuarterlyGross adds up a year's monthly earnings by calendar quarter.
+func QuarterlyGross(year int, monthly []EarningsSummary) [4]money.Cents {
+	var quarters [4]money.Cents
+	for _, summary := range monthly {
+		period, err := time.Parse("2006-01", summary.Period)
+		if err != nil || period.Year() != year {
+			continue
+		}
+		quarters[(period.Month()-1)/3] += summary.GrossEarnings
+	}
+	return quarters
+}
+
+// SSExemptUntil is the first month whose contributions are due for a worker
+// who started their activity on the calendar date start.
+func SSExemptUntil(start time.Time, months int, loc *time.Location) time.Time {
+	return time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, loc).AddDate(0, months, 0)
+}
+
+// WithGross is income with its Category B gross replaced. Contributions
+// already declared move by the change the engine estimates for the new gross,
+// so the first-year exemption and base adjustment behind them still count.
+func WithGross(engine tax.Engine, config tax.YearConfig, income tax.Income, gross money.Cents) tax.Income {
+	if income.SSContributions != nil {
+		estimate := func(g money.Cents) money.Cents {
+			return engine.CalculateSocialSecurity(config, g/4).AnnualEstimate
+		}
+		contributions := max(*income.SSContributions+estimate(gross)-estimate(income.Gross), 0)
+		income.SSContributions = &contributions
+	}
+	income.Gross = gross
+	return income
+}
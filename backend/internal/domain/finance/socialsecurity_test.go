@@ -0,0 +1,77 @@
+package finance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+func TestBuildSocialSecurityReport(t *testing.T) {
+	loc := time.UTC
+	started := time.Date(2024, 6, 10, 0, 0, 0, 0, loc)
+	exemptUntil := SSExemptUntil(started, 12, loc)
+	quarters := [4]money.Cents{money.FromEuros(9000), 0, money.FromEuros(9000), money.FromEuros(9000)}
+	asOf := time.Date(2025, 8, 15, 0, 0, 0, 0, loc)
+
+	report := BuildSocialSecurityReport(2025, quarters, asOf, &exemptUntil, -0.25, tax.Portugal(), loc)
+
+	if len(report.Declarations) != 4 {
+		t.Fatalf("expected 4 declarations, got %d", len(report.Declarations))
+	}
+
+	q1 := report.Declarations[0]
+	if q1.DeclarationMonth != "2025-04" || !q1.DeclarationDue.Equal(time.Date(2025, 4, 30, 0, 0, 0, 0, loc)) {
+		t.Errorf("expected Q1 declared by 30 April, got %s due %s", q1.DeclarationMonth, q1.DeclarationDue)
+	}
+	// 70% of 9,000 EUR over three months, reduced by 25%
+	if q1.RelevantIncome != money.FromEuros(6300) || q1.MonthlyBase != money.FromEuros(1575) {
+		t.Errorf("unexpected relevant income %d and base %d", q1.RelevantIncome, q1.MonthlyBase)
+	}
+	if q1.MonthlyContribution != money.FromEuros(337.05) {
+		t.Errorf("expected 337.05 EUR a month, got %d", q1.MonthlyContribution)
+	}
+
+	// The first twelve months run to May 2025
+	if !q1.Payments[0].Exempt || !q1.Payments[1].Exempt || q1.Payments[2].Exempt || q1.Exempt {
+		t.Errorf("expected April and May exempt and June due, got %+v", q1.Payments)
+	}
+	june := q1.Payments[2]
+	if june.Month != "2025-06" || june.Amount != q1.MonthlyContribution ||
+		!june.DueFrom.Equal(time.Date(2025, 7, 10, 0, 0, 0, 0, loc)) || !june.DueBy.Equal(time.Date(2025, 7, 20, 0, 0, 0, 0, loc)) {
+		t.Errorf("unexpected June payment %+v", june)
+	}
+
+	if q2 := report.Declarations[1]; q2.MonthlyContribution != 0 || q2.Payments[0].Amount != 0 {
+		t.Errorf("expected nothing to pay for a quarter without income, got %+v", q2)
+	}
+	if q3 := report.Declarations[2]; !q3.Estimated || report.Declarations[0].Estimated {
+		t.Error("expected only quarters still running to be estimated")
+	}
+
+	q4 := report.Declarations[3]
+	if q4.DeclarationMonth != "2026-01" || q4.Payments[2].Month != "2026-03" {
+		t.Errorf("expected Q4 declared in January and paid through March, got %s to %s", q4.DeclarationMonth, q4.Payments[2].Month)
+	}
+
+	if want := q1.MonthlyContribution * 7; report.TotalContributions != want {
+		t.Errorf("expected seven contributions due (%d), got %d", want, report.TotalContributions)
+	}
+}
+
+func TestQuarterlyGross(t *testing.T) {
+	monthly := []EarningsSummary{
+		{Period: "2025-12", GrossEarnings: money.FromEuros(900)},
+		{Period: "2026-01", GrossEarnings: money.FromEuros(1000)},
+		{Period: "2026-03", GrossEarnings: money.FromEuros(500)},
+		{Period: "2026-04", GrossEarnings: money.FromEuros(2000)},
+		{Period: "2026-12", GrossEarnings: money.FromEuros(300)},
+	}
+
+	got := QuarterlyGross(2026, monthly)
+	want := [4]money.Cents{money.FromEuros(1500), money.FromEuros(2000), 0, money.FromEuros(300)}
+	if got != want {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,315 @@
+package finance
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+var (
+	ErrUnsupportedStatementFormat = errors.New("unsupported bank statement format")
+	ErrInvalidStatement           = errors.New("invalid bank statement")
+)
+
+type StatementFormat string
+
+const (
+	StatementFormatCSV     StatementFormat = "csv"
+	StatementFormatCAMT053 StatementFormat = "camt053"
+)
+
+// BankTransaction is a single credit line from an imported bank statement.
+type BankTransaction struct {
+	Date         time.Time   `json:"date"`
+	AmountCents  money.Cents `json:"amount_cents"`
+	Description  string      `json:"description"`
+	Reference    string      `json:"reference,omitempty"`
+	Counterparty string      `json:"counterparty,omitempty"`
+}
+
+// CSVColumnMapping tells the CSV importer which header holds each field.
+// Banks export either a signed amount column or separate credit/debit columns,
+// so exactly one of Amount or Credit must be set.
+type CSVColumnMapping struct {
+	Date         string `json:"date"`
+	Amount       string `json:"amount,omitempty"`
+	Credit       string `json:"credit,omitempty"`
+	Description  string `json:"description"`
+	Reference    string `json:"reference,omitempty"`
+	Counterparty string `json:"counterparty,omitempty"`
+
+	DateFormat   string `json:"date_format,omitempty"` // Go layout, defaults to 02-01-2006
+	Delimiter    string `json:"delimiter,omitempty"`   // defaults to ";"
+	DecimalComma bool   `json:"decimal_comma"`         // "1.234,56" instead of "1,234.56"
+	SkipRows     int    `json:"skip_rows,omitempty"`   // preamble lines before the header row
+}
+
+// ParseStatement reads credit transactions from a bank statement export.
+// Debits are dropped: only incoming payments can settle invoices.
+func ParseStatement(format StatementFormat, r io.Reader, mapping *CSVColumnMapping) ([]BankTransaction, error) {
+	switch format {
+	case StatementFormatCSV:
+		if mapping == nil {
+			return nil, fmt.Errorf("%w: csv import requires a column mapping", ErrInvalidStatement)
+		}
+		return ParseCSVStatement(r, *mapping)
+	case StatementFormatCAMT053:
+		return ParseCAMT053(r)
+	}
+	return nil, ErrUnsupportedStatementFormat
+}
+
+// ParseCSVStatement parses a CSV export using the given column mapping.
+func ParseCSVStatement(r io.Reader, mapping CSVColumnMapping) ([]BankTransaction, error) {
+	if mapping.Date == "" || mapping.Description == "" || (mapping.Amount == "") == (mapping.Credit == "") {
+		return nil, fmt.Errorf("%w: mapping needs date, description and one of amount or credit", ErrInvalidStatement)
+	}
+
+	dateFormat := mapping.DateFormat
+	if dateFormat == "" {
+		dateFormat = "02-01-2006"
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = ';'
+	if mapping.Delimiter != "" {
+		reader.Comma = []rune(mapping.Delimiter)[0]
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidStatement, err)
+	}
+	if len(records) <= mapping.SkipRows {
+		return nil, fmt.Errorf("%w: missing header row", ErrInvalidStatement)
+	}
+
+	header := make(map[string]int)
+	for i, name := range records[mapping.SkipRows] {
+		header[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	column := func(name string) (int, error) {
+		if name == "" {
+			return -1, nil
+		}
+		idx, ok := header[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return -1, fmt.Errorf("%w: column %q not found", ErrInvalidStatement, name)
+		}
+		return idx, nil
+	}
+
+	dateCol, err := column(mapping.Date)
+	if err != nil {
+		return nil, err
+	}
+	amountCol, err := column(mapping.Amount)
+	if err != nil {
+		return nil, err
+	}
+	creditCol, err := column(mapping.Credit)
+	if err != nil {
+		return nil, err
+	}
+	descCol, err := column(mapping.Description)
+	if err != nil {
+		return nil, err
+	}
+	refCol, err := column(mapping.Reference)
+	if err != nil {
+		return nil, err
+	}
+	partyCol, err := column(mapping.Counterparty)
+	if err != nil {
+		return nil, err
+	}
+
+	field := func(record []string, idx int) string {
+		if idx < 0 || idx >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[idx])
+	}
+
+	var txs []BankTransaction
+	for i, record := range records[mapping.SkipRows+1:] {
+		line := mapping.SkipRows + i + 2
+
+		rawAmount := field(record, amountCol)
+		if creditCol >= 0 {
+			rawAmount = field(record, creditCol)
+		}
+		if rawAmount == "" {
+			continue // debit-only row or trailing blank line
+		}
+		amount, err := parseAmount(rawAmount, mapping.DecimalComma)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidStatement, line, err)
+		}
+		if amount <= 0 {
+			continue
+		}
+
+		date, err := time.Parse(dateFormat, field(record, dateCol))
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidStatement, line, err)
+		}
+
+		txs = append(txs, BankTransaction{
+			Date:         date,
+			AmountCents:  amount,
+			Description:  field(record, descCol),
+			Reference:    field(record, refCol),
+			Counterparty: field(record, partyCol),
+		})
+	}
+	return txs, nil
+}
+
+// parseAmount converts a bank-formatted decimal string into cents without
+// going through float64.
+func parseAmount(s string, decimalComma bool) (money.Cents, error) {
+	s = strings.NewReplacer("€", "", "EUR", "", " ", "", " ", "").Replace(s)
+	if decimalComma {
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.ReplaceAll(s, ",", ".")
+	} else {
+		s = strings.ReplaceAll(s, ",", "")
+	}
+
+	negative := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		negative = true
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" {
+		whole = "0"
+	}
+	if len(frac) > 2 {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	for len(frac) < 2 {
+		frac += "0"
+	}
+
+	euros, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	cents, err := strconv.ParseInt(frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+
+	total := money.Cents(euros*100 + cents)
+	if negative {
+		total = -total
+	}
+	return total, nil
+}
+
+// camtDocument maps the subset of ISO 20022 camt.053 needed to extract
+// credit entries. Tags carry no namespace so any camt.053.001.xx version
+// decodes the same way.
+type camtDocument struct {
+	Statements []struct {
+		Entries []camtEntry `xml:"Ntry"`
+	} `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtEntry struct {
+	Amount       string `xml:"Amt"`
+	CreditDebit  string `xml:"CdtDbtInd"`
+	BookingDate  string `xml:"BookgDt>Dt"`
+	BookingTime  string `xml:"BookgDt>DtTm"`
+	ValueDate    string `xml:"ValDt>Dt"`
+	AccountRef   string `xml:"AcctSvcrRef"`
+	AddtlInfo    string `xml:"AddtlNtryInf"`
+	Transactions []struct {
+		EndToEndID   string   `xml:"Refs>EndToEndId"`
+		Unstructured []string `xml:"RmtInf>Ustrd"`
+		CreditorRef  string   `xml:"RmtInf>Strd>CdtrRefInf>Ref"`
+		DebtorName   string   `xml:"RltdPties>Dbtr>Nm"`
+		DebtorPtyNm  string   `xml:"RltdPties>Dbtr>Pty>Nm"`
+	} `xml:"NtryDtls>TxDtls"`
+}
+
+// ParseCAMT053 parses an ISO 20022 camt.053 bank-to-customer statement.
+func ParseCAMT053(r io.Reader) ([]BankTransaction, error) {
+	var doc camtDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidStatement, err)
+	}
+
+	var txs []BankTransaction
+	for _, stmt := range doc.Statements {
+		for _, entry := range stmt.Entries {
+			if entry.CreditDebit != "CRDT" {
+				continue
+			}
+
+			amount, err := parseAmount(entry.Amount, false)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidStatement, err)
+			}
+
+			date, err := parseCAMTDate(entry)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidStatement, err)
+			}
+
+			tx := BankTransaction{
+				Date:        date,
+				AmountCents: amount,
+				Reference:   entry.AccountRef,
+			}
+			descriptions := []string{}
+			if entry.AddtlInfo != "" {
+				descriptions = append(descriptions, entry.AddtlInfo)
+			}
+			for _, detail := range entry.Transactions {
+				descriptions = append(descriptions, detail.Unstructured...)
+				if detail.CreditorRef != "" {
+					tx.Reference = detail.CreditorRef
+				} else if detail.EndToEndID != "" && detail.EndToEndID != "NOTPROVIDED" {
+					tx.Reference = detail.EndToEndID
+				}
+				if detail.DebtorName != "" {
+					tx.Counterparty = detail.DebtorName
+				} else if detail.DebtorPtyNm != "" {
+					tx.Counterparty = detail.DebtorPtyNm
+				}
+			}
+			tx.Description = strings.Join(descriptions, " ")
+
+			txs = append(txs, tx)
+		}
+	}
+	return txs, nil
+}
+
+func parseCAMTDate(entry camtEntry) (time.Time, error) {
+	switch {
+	case entry.BookingDate != "":
+		return time.Parse("2006-01-02", entry.BookingDate)
+	case entry.BookingTime != "":
+		return time.Parse(time.RFC3339, entry.BookingTime)
+	case entry.ValueDate != "":
+		return time.Parse("2006-01-02", entry.ValueDate)
+	}
+	return time.Time{}, errors.New("entry has no booking or value date")
+}
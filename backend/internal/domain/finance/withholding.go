@@ -0,0 +1,136 @@
+package finance
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/auth"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/workplace"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+// PayerWithholding is the rate a workplace withholds on the next payment.
+type PayerWithholding struct {
+	WorkplaceID   uuid.UUID `json:"workplace_id"`
+	WorkplaceName string    `json:"workplace_name"`
+	PrivateClient bool      `json:"private_client"`
+	Rate          float64   `json:"rate"`
+}
+
+// WithholdingStatus tracks a year's income against the art. 101-B
+// dispensation threshold.
+type WithholdingStatus struct {
+	Year      int         `json:"year"`
+	Dispensed bool        `json:"dispensed"` // the user opted for the dispensation
+	Threshold money.Cents `json:"threshold"`
+
+	IncomeToDate     money.Cents `json:"income_to_date"`
+	ProjectedIncome  money.Cents `json:"projected_income"` // including scheduled shifts
+	ThresholdCrossed bool        `json:"threshold_crossed"`
+	CrossingMonth    string      `json:"crossing_month,omitempty"` // YYYY-MM, possibly projected
+	Warning          string      `json:"warning,omitempty"`
+
+	Workplaces []PayerWithholding `json:"workplaces"`
+}
+
+// BuildWithholdingStatus compares the income earned up to asOf, and each
+// month's projected earnings, with the dispensation threshold. Once the
+// threshold is crossed, clients withhold again for the rest of the year.
+func BuildWithholdingStatus(year int, incomeToDate money.Cents, monthly []EarningsSummary, optedIn bool, workplaces []*workplace.Workplace, config tax.YearConfig) *WithholdingStatus {
+	status := &WithholdingStatus{
+		Year:             year,
+		Dispensed:        optedIn,
+		Threshold:        config.WithholdingDispensationCents,
+		IncomeToDate:     incomeToDate,
+		ThresholdCrossed: incomeToDate > config.WithholdingDispensationCents,
+	}
+
+	for _, summary := range monthly {
+		status.ProjectedIncome += summary.GrossEarnings
+		if status.CrossingMonth == "" && status.ProjectedIncome > status.Threshold {
+			status.CrossingMonth = summary.Period
+		}
+	}
+
+	if optedIn {
+		switch {
+		case status.ThresholdCrossed:
+			status.Warning = "income has passed the withholding dispensation threshold; clients must withhold on every payment for the rest of the year"
+		case status.CrossingMonth != "":
+			status.Warning = "scheduled shifts take income past the withholding dispensation threshold in " + status.CrossingMonth
+		}
+	}
+
+	dispensed := tax.Dispensed(config, optedIn, incomeToDate)
+	for _, wp := range workplaces {
+		status.Workplaces = append(status.Workplaces, PayerWithholding{
+			WorkplaceID:   wp.ID,
+			WorkplaceName: wp.Name,
+			PrivateClient: wp.PrivateClient,
+			Rate:          tax.WithholdingRate(config, payerOf(wp), dispensed),
+		})
+	}
+
+	return status
+}
+
+// EstimateWithholding is the IRS withheld on a year's earnings: what the
+// invoices whose period starts in [start, end) record, plus what each
+// workplace will withhold on the part of its earnings not invoiced yet.
+// Invoices are taken to cover each workplace's earliest earnings. A dispensed
+// taxpayer is not withheld on until the year's income, month by month, passes
+// the threshold; the earnings that cross it and all later ones are.
+func EstimateWithholding(monthly []EarningsSummary, invoices []*Invoice, workplaces map[uuid.UUID]*workplace.Workplace, config tax.YearConfig, optedIn bool, engine tax.Engine, start, end time.Time) (invoiced, total money.Cents) {
+	invoicedGross := make(map[uuid.UUID]money.Cents)
+	for _, inv := range invoices {
+		if inv.PeriodStart.Before(start) || !inv.PeriodStart.Before(end) {
+			continue
+		}
+		invoicedGross[inv.WorkplaceID] += inv.GrossAmountCents
+		invoiced += inv.WithholdingCents
+	}
+
+	total = invoiced
+	var incomeToDate money.Cents
+	for _, month := range monthly {
+		for _, we := range month.ByWorkplace {
+			incomeToDate += we.Gross
+			covered := min(invoicedGross[we.WorkplaceID], we.Gross)
+			invoicedGross[we.WorkplaceID] -= covered
+			pending := we.Gross - covered
+			if pending <= 0 {
+				continue
+			}
+			rate := tax.WithholdingRate(config, payerOf(workplaces[we.WorkplaceID]), tax.Dispensed(config, optedIn, incomeToDate))
+			total += engine.CalculateWithholding(pending, rate)
+		}
+	}
+	return invoiced, total
+}
+
+// payerOf describes a workplace as a withholding payer. An unknown workplace
+// is taken to be an entity withholding at the year's rate.
+func payerOf(wp *workplace.Workplace) tax.Payer {
+	if wp == nil {
+		return tax.Payer{}
+	}
+	return tax.Payer{Private: wp.PrivateClient, Rate: wp.WithholdingRate}
+}
+
+// DispensationCrossedWarning is returned with an invoice that takes a
+// dispensed user's income past the threshold.
+const DispensationCrossedWarning = "this invoice takes income past the withholding dispensation threshold; clients must withhold on it and on every later payment this year"
+
+// crossesDispensation reports whether a payment of gross, on top of
+// incomeToDate already earned in the year, takes a dispensed taxpayer past
+// the threshold.
+func crossesDispensation(config tax.YearConfig, optedIn bool, incomeToDate, gross money.Cents) bool {
+	return tax.Dispensed(config, optedIn, incomeToDate) && !tax.Dispensed(config, optedIn, incomeToDate+gross)
+}
+
+// withholdingDispensed reports whether the user opted for the art. 101-B
+// dispensation.
+func withholdingDispensed(user *auth.User) bool {
+	return user != nil && user.WithholdingDispensed
+}
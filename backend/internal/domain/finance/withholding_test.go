@@ -0,0 +1,184 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/auth"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/tax"
+	"github.com/joao-moreira/doctor-tracker/internal/domain/workplace"
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+func TestWithholdingRate(t *testing.T) {
+	config := tax.Portugal2026Config()
+
+	tests := []struct {
+		name      string
+		payer     tax.Payer
+		dispensed bool
+		want      float64
+	}{
+		{"entity at the year's default", tax.Payer{}, false, 0.23},
+		{"entity at an agreed rate", tax.Payer{Rate: agreedRate(0.25)}, false, 0.25},
+		{"entity agreed not to withhold", tax.Payer{Rate: agreedRate(0)}, false, 0},
+		{"private individual", tax.Payer{Private: true, Rate: agreedRate(0.25)}, false, 0},
+		{"dispensed taxpayer", tax.Payer{Rate: agreedRate(0.25)}, true, 0},
+	}
+	for _, tt := range tests {
+		if got := tax.WithholdingRate(config, tt.payer, tt.dispensed); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func agreedRate(r float64) *float64 { return &r }
+
+func TestBuildCashFlowForecast_WithholdingResumesPastDispensation(t *testing.T) {
+	clinic := &workplace.Workplace{ID: uuid.New(), Name: "Clinic"}
+	workplaces := map[uuid.UUID]*workplace.Workplace{clinic.ID: clinic}
+
+	var earnings []EarningsSummary
+	for _, period := range []string{"2026-01", "2026-02", "2026-03"} {
+		gross := money.FromEuros(6000)
+		earnings = append(earnings, EarningsSummary{
+			Period:        period,
+			GrossEarnings: gross,
+			ByWorkplace:   []WorkplaceEarnings{{WorkplaceID: clinic.ID, WorkplaceName: "Clinic", Gross: gross}},
+		})
+	}
+
+	forecast := BuildCashFlowForecast(2026, earnings, workplaces, tax.NewPortugalEngine(), tax.Portugal2026Config(), true, 0, [3]money.Cents{}, time.UTC)
+
+	// Paid on invoicing: 12,000 EUR by February is within the threshold and
+	// March takes it to 18,000
+	for i, want := range []money.Cents{0, 0, money.FromEuros(1380)} {
+		if got := forecast.Months[i].Withholding; got != want {
+			t.Errorf("expected %d withheld on %s's work, got %d", want, earnings[i].Period, got)
+		}
+	}
+}
+
+func TestBuildWithholdingStatus(t *testing.T) {
+	config := tax.Portugal2026Config()
+	hospital := &workplace.Workplace{ID: uuid.New(), Name: "Hospital", WithholdingRate: agreedRate(0.25)}
+	patient := &workplace.Workplace{ID: uuid.New(), Name: "Private practice", PrivateClient: true}
+
+	var monthly []EarningsSummary
+	for _, period := range []string{"2026-01", "2026-02", "2026-03", "2026-04"} {
+		monthly = append(monthly, EarningsSummary{Period: period, GrossEarnings: money.FromEuros(4000)})
+	}
+
+	status := BuildWithholdingStatus(2026, money.FromEuros(8000), monthly, true, []*workplace.Workplace{hospital, patient}, config)
+
+	if status.ThresholdCrossed || status.CrossingMonth != "2026-04" {
+		t.Errorf("expected a crossing scheduled for April, got %+v", status)
+	}
+	if !strings.Contains(status.Warning, "2026-04") {
+		t.Errorf("expected a warning about April, got %q", status.Warning)
+	}
+	if status.Workplaces[0].Rate != 0 || status.Workplaces[1].Rate != 0 {
+		t.Errorf("expected no withholding while dispensed, got %+v", status.Workplaces)
+	}
+
+	status = BuildWithholdingStatus(2026, money.FromEuros(16000), monthly, true, []*workplace.Workplace{hospital, patient}, config)
+	if !status.ThresholdCrossed || status.Warning == "" {
+		t.Errorf("expected the threshold crossed with a warning, got %+v", status)
+	}
+	if status.Workplaces[0].Rate != 0.25 || status.Workplaces[1].Rate != 0 {
+		t.Errorf("expected the hospital to withhold again and the private client not to, got %+v", status.Workplaces)
+	}
+
+	if status := BuildWithholdingStatus(2026, money.FromEuros(16000), monthly, false, nil, config); status.Warning != "" {
+		t.Errorf("expected no warning without the dispensation, got %q", status.Warning)
+	}
+}
+
+func TestEstimateWithholding_DispensationIsChronological(t *testing.T) {
+	config := tax.Portugal2026Config()
+	engine := tax.NewPortugalEngine()
+	hospital := &workplace.Workplace{ID: uuid.New(), Name: "Hospital"}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+
+	var monthly []EarningsSummary
+	for m := 1; m <= 5; m++ {
+		monthly = append(monthly, EarningsSummary{
+			Period:      time.Date(2026, time.Month(m), 1, 0, 0, 0, 0, time.UTC).Format("2006-01"),
+			ByWorkplace: []WorkplaceEarnings{{WorkplaceID: hospital.ID, Gross: money.FromEuros(4000)}},
+		})
+	}
+	// January's earnings are invoiced, within the threshold and not withheld
+	invoices := []*Invoice{{WorkplaceID: hospital.ID, PeriodStart: start, GrossAmountCents: money.FromEuros(4000)}}
+	workplaces := map[uuid.UUID]*workplace.Workplace{hospital.ID: hospital}
+
+	invoiced, total := EstimateWithholding(monthly, invoices, workplaces, config, true, engine, start, end)
+	if invoiced != 0 {
+		t.Errorf("expected nothing withheld on the invoice, got %d", invoiced)
+	}
+	// 4,000 EUR a month passes 15,000 EUR in April: April and May are withheld
+	if want := engine.CalculateWithholding(money.FromEuros(8000), 0.23); total != want {
+		t.Errorf("expected %d withheld from April on, got %d", want, total)
+	}
+
+	if _, total := EstimateWithholding(monthly, invoices, workplaces, config, false, engine, start, end); total != engine.CalculateWithholding(money.FromEuros(16000), 0.23) {
+		t.Errorf("expected every uninvoiced month withheld without the dispensation, got %d", total)
+	}
+}
+
+type mockInvoiceRepo struct {
+	mockSettlementRepo
+	created *Invoice
+}
+
+func (m *mockInvoiceRepo) CreateInvoice(_ context.Context, invoice *Invoice) error {
+	m.created = invoice
+	return nil
+}
+
+func TestCreateInvoice_WarnsWhenCrossingDispensation(t *testing.T) {
+	userID := uuid.New()
+	hospital := &workplace.Workplace{ID: uuid.New(), UserID: userID, Name: "Hospital"}
+	repo := &mockInvoiceRepo{mockSettlementRepo: mockSettlementRepo{
+		invoices: []*Invoice{{
+			PeriodStart:      time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			GrossAmountCents: money.FromEuros(12000),
+		}},
+	}}
+	users := &mockUserRepo{user: &auth.User{WithholdingDispensed: true}}
+	svc := NewService(repo, &mockWorkplaceRepo{workplaces: []*workplace.Workplace{hospital}}, nil, users, tax.DefaultRegistry())
+
+	input := CreateInvoiceInput{
+		WorkplaceID:      hospital.ID,
+		PeriodStart:      time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:        time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+		GrossAmountCents: int64(money.FromEuros(2000)),
+	}
+	invoice, err := svc.CreateInvoice(context.Background(), userID, input)
+	if err != nil {
+		t.Fatalf("CreateInvoice failed: %v", err)
+	}
+	if invoice.Warning != "" || invoice.WithholdingCents != 0 {
+		t.Errorf("expected a dispensed invoice without warning, got %q withholding %d", invoice.Warning, invoice.WithholdingCents)
+	}
+
+	input.GrossAmountCents = int64(money.FromEuros(4000))
+	invoice, err = svc.CreateInvoice(context.Background(), userID, input)
+	if err != nil {
+		t.Fatalf("CreateInvoice failed: %v", err)
+	}
+	if invoice.Warning != DispensationCrossedWarning || repo.created != invoice {
+		t.Errorf("expected the stored invoice to carry the threshold warning, got %q", invoice.Warning)
+	}
+	if invoice.WithholdingCents == 0 {
+		t.Error("expected withholding once past the threshold")
+	}
+
+	if _, err := svc.CreateInvoice(context.Background(), uuid.New(), input); !errors.Is(err, workplace.ErrWorkplaceNotFound) {
+		t.Errorf("expected another user's workplace to be rejected, got %v", err)
+	}
+}
@@ -17,6 +17,24 @@ const (
 	ShiftStatusCancelled ShiftStatus = "cancelled"
 )
 
+// shiftTransitions lists the statuses each status may move to. Confirmation
+// may be skipped for shifts only reviewed after they happened.
+var shiftTransitions = map[ShiftStatus][]ShiftStatus{
+	ShiftStatusScheduled: {ShiftStatusConfirmed, ShiftStatusCompleted, ShiftStatusCancelled},
+	ShiftStatusConfirmed: {ShiftStatusCompleted, ShiftStatusCancelled},
+	ShiftStatusCompleted: {ShiftStatusCancelled},
+}
+
+// CanTransition reports whether a shift may move from one status to another.
+func CanTransition(from, to ShiftStatus) bool {
+	for _, next := range shiftTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
 type Shift struct {
 	ID          uuid.UUID   `json:"id"`
 	UserID      uuid.UUID   `json:"user_id"`
@@ -73,18 +91,25 @@ const (
 )
 
 type ShiftEarning struct {
-	ID            uuid.UUID     `json:"id"`
-	ShiftID       uuid.UUID     `json:"shift_id"`
-	PricingRuleID *uuid.UUID    `json:"pricing_rule_id,omitempty"`
-	SegmentStart  time.Time     `json:"segment_start"`
-	SegmentEnd    time.Time     `json:"segment_end"`
-	Hours         float64       `json:"hours"`
-	RateCents     money.Cents   `json:"rate_cents"`
-	AmountCents   money.Cents   `json:"amount_cents"`
-	Status        EarningStatus `json:"status"`
-	Notes         *string       `json:"notes,omitempty"`
-	CreatedAt     time.Time     `json:"created_at"`
-	UpdatedAt     time.Time     `json:"updated_at"`
+	ID            uuid.UUID             `json:"id"`
+	ShiftID       uuid.UUID             `json:"shift_id"`
+	PricingRuleID *uuid.UUID            `json:"pricing_rule_id,omitempty"`
+	Kind          workplace.EarningKind `json:"kind"`
+	SegmentStart  time.Time             `json:"segment_start"`
+	SegmentEnd    time.Time             `json:"segment_end"`
+	Hours         float64               `json:"hours"`
+	RateCents     money.Cents           `json:"rate_cents"`
+	AmountCents   money.Cents           `json:"amount_cents"`
+	Status        EarningStatus         `json:"status"`
+
+	// Snapshot of how the rule priced the segment, kept if the rule later changes
+	RuleName       *string  `json:"rule_name,omitempty"`
+	RateMultiplier *float64 `json:"rate_multiplier,omitempty"`
+	Quantity       *int     `json:"quantity,omitempty"` // patients or visits, for add-ons
+
+	Notes     *string   `json:"notes,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type CreateShiftInput struct {
@@ -111,6 +136,10 @@ type UpdateShiftInput struct {
 	OutsideVisits *int         `json:"outside_visits"`
 }
 
+type UpdateShiftStatusInput struct {
+	Status ShiftStatus `json:"status" validate:"required,oneof=scheduled confirmed completed cancelled"`
+}
+
 type CreateRecurrenceInput struct {
 	RRuleString string `json:"rrule_string" validate:"required"`
 	UntilDate   *time.Time `json:"until_date,omitempty"`
@@ -125,3 +154,21 @@ type ShiftFilter struct {
 	Status       *ShiftStatus
 	ExpandRecurrences bool
 }
+
+// ReviewRun is the outcome of the last nightly review of past shifts, as one
+// user sees it. Pending and OldestStart come from the last run that
+// succeeded.
+type ReviewRun struct {
+	RanAt       time.Time  `json:"ran_at"`
+	Succeeded   bool       `json:"succeeded"`
+	Error       string     `json:"error,omitempty"`
+	Pending     int        `json:"pending"` // the user's shifts found awaiting review
+	OldestStart *time.Time `json:"oldest_start,omitempty"`
+}
+
+// ReviewReminder is what a nightly review found for one user.
+type ReviewReminder struct {
+	UserID      uuid.UUID
+	Pending     int
+	OldestStart time.Time
+}
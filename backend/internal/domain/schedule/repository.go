@@ -2,6 +2,7 @@ package schedule
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -14,6 +15,16 @@ type Repository interface {
 	UpdateShift(ctx context.Context, shift *Shift) error
 	DeleteShift(ctx context.Context, id uuid.UUID) error
 	BulkCreateShifts(ctx context.Context, shifts []*Shift) error
+	// ListUnreviewedShifts returns shifts still scheduled or confirmed that
+	// ended before the given time, for one user or, when userID is nil, all.
+	ListUnreviewedShifts(ctx context.Context, userID *uuid.UUID, endedBefore time.Time) ([]*Shift, error)
+
+	// Nightly review. SaveReviewRun records a run; one that succeeded
+	// replaces every user's reminders with the given ones.
+	SaveReviewRun(ctx context.Context, ranAt time.Time, succeeded bool, reminders []ReviewReminder) error
+	// GetLastReviewRun returns the latest run with the user's reminder, or
+	// nil when the review has never run.
+	GetLastReviewRun(ctx context.Context, userID uuid.UUID) (*ReviewRun, error)
 
 	// Recurrence Rules
 	CreateRecurrenceRule(ctx context.Context, rule *RecurrenceRule) error
@@ -25,5 +36,7 @@ type Repository interface {
 	CreateShiftEarnings(ctx context.Context, earnings []*ShiftEarning) error
 	GetShiftEarnings(ctx context.Context, shiftID uuid.UUID) ([]*ShiftEarning, error)
 	DeleteShiftEarnings(ctx context.Context, shiftID uuid.UUID) error
-	UpdateEarningStatus(ctx context.Context, shiftID uuid.UUID, status EarningStatus) error
+	// ConfirmProjectedEarnings moves a shift's projected earnings to
+	// confirmed. Earnings already paid keep their status.
+	ConfirmProjectedEarnings(ctx context.Context, shiftID uuid.UUID) error
 }
@@ -14,6 +14,9 @@ var (
 	ErrShiftNotFound      = errors.New("shift not found")
 	ErrShiftOverlap       = errors.New("shift overlaps with an existing shift")
 	ErrInvalidTimeRange   = errors.New("end time must be after start time")
+	ErrInvalidTransition  = errors.New("invalid shift status transition")
+	ErrShiftNotEnded      = errors.New("shift has not ended yet")
+	ErrNoReviewRun        = errors.New("the nightly review has not run yet")
 )
 
 // CalendarSyncer pushes shift changes to an external calendar (e.g. Google Calendar).
@@ -84,13 +87,23 @@ func (s *Service) GetShift(ctx context.Context, id uuid.UUID) (*Shift, error) {
 
 	earnings, _ := s.repo.GetShiftEarnings(ctx, shift.ID)
 	for _, e := range earnings {
-		shift.Earnings = append(shift.Earnings, workplace.EarningSegment{
-			Start:  e.SegmentStart,
-			End:    e.SegmentEnd,
-			Hours:  e.Hours,
-			Rate:   e.RateCents,
-			Amount: e.AmountCents,
-		})
+		seg := workplace.EarningSegment{
+			Kind:           e.Kind,
+			Start:          e.SegmentStart,
+			End:            e.SegmentEnd,
+			Hours:          e.Hours,
+			Rate:           e.RateCents,
+			Amount:         e.AmountCents,
+			PricingRuleID:  e.PricingRuleID,
+			RateMultiplier: e.RateMultiplier,
+		}
+		if e.RuleName != nil {
+			seg.RuleName = *e.RuleName
+		}
+		if e.Quantity != nil {
+			seg.Quantity = *e.Quantity
+		}
+		shift.Earnings = append(shift.Earnings, seg)
 	}
 	shift.TotalEarnings = workplace.TotalEarnings(shift.Earnings)
 
@@ -113,7 +126,11 @@ func (s *Service) UpdateShift(ctx context.Context, id uuid.UUID, input UpdateShi
 	if input.EndTime != nil {
 		shift.EndTime = *input.EndTime
 	}
-	if input.Status != nil {
+	previousStatus := shift.Status
+	if input.Status != nil && *input.Status != shift.Status {
+		if err := checkTransition(shift, *input.Status, time.Now()); err != nil {
+			return nil, err
+		}
 		shift.Status = *input.Status
 	}
 	if input.Title != nil {
@@ -144,6 +161,10 @@ func (s *Service) UpdateShift(ctx context.Context, id uuid.UUID, input UpdateShi
 		if err := s.calculateAndStoreEarnings(ctx, shift); err != nil {
 			return nil, err
 		}
+	} else if shift.Status == ShiftStatusCompleted && previousStatus != ShiftStatusCompleted {
+		if err := s.repo.ConfirmProjectedEarnings(ctx, shift.ID); err != nil {
+			return nil, err
+		}
 	}
 
 	if shift.Status == ShiftStatusCancelled {
@@ -155,6 +176,126 @@ func (s *Service) UpdateShift(ctx context.Context, id uuid.UUID, input UpdateShi
 	return shift, nil
 }
 
+// UpdateShiftStatus moves a shift along scheduled -> confirmed -> completed,
+// or cancels it. Completing a shift confirms its projected earnings.
+func (s *Service) UpdateShiftStatus(ctx context.Context, userID, id uuid.UUID, status ShiftStatus) (*Shift, error) {
+	shift, err := s.repo.GetShiftByID(ctx, id)
+	if err != nil || shift.UserID != userID {
+		return nil, ErrShiftNotFound
+	}
+	if shift.Status == status {
+		return s.GetShift(ctx, id)
+	}
+	if err := checkTransition(shift, status, time.Now()); err != nil {
+		return nil, err
+	}
+
+	shift.Status = status
+	shift.UpdatedAt = time.Now()
+	if err := s.repo.UpdateShift(ctx, shift); err != nil {
+		return nil, err
+	}
+
+	if status == ShiftStatusCompleted {
+		if err := s.repo.ConfirmProjectedEarnings(ctx, shift.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if status == ShiftStatusCancelled {
+		s.removeFromCalendar(ctx, shift)
+	} else {
+		s.syncToCalendar(ctx, shift)
+	}
+
+	return s.GetShift(ctx, id)
+}
+
+// ConfirmEarnings records that a shift took place as planned: it is marked
+// completed and its earnings confirmed.
+func (s *Service) ConfirmEarnings(ctx context.Context, userID, id uuid.UUID) (*Shift, error) {
+	shift, err := s.repo.GetShiftByID(ctx, id)
+	if err != nil || shift.UserID != userID {
+		return nil, ErrShiftNotFound
+	}
+	if shift.Status != ShiftStatusCompleted {
+		return s.UpdateShiftStatus(ctx, userID, id, ShiftStatusCompleted)
+	}
+
+	// Already completed, e.g. earnings recalculated after editing the shift
+	if err := s.repo.ConfirmProjectedEarnings(ctx, shift.ID); err != nil {
+		return nil, err
+	}
+	return s.GetShift(ctx, id)
+}
+
+// ListPendingReview returns the user's shifts that have ended but are still
+// scheduled or confirmed.
+func (s *Service) ListPendingReview(ctx context.Context, userID uuid.UUID, asOf time.Time) ([]*Shift, error) {
+	return s.repo.ListUnreviewedShifts(ctx, &userID, asOf)
+}
+
+// RemindPendingReview is run nightly. It records, per user, the past shifts
+// that still need to be completed or cancelled so their earnings count as
+// actual, for the app to prompt with. It returns how many shifts are
+// awaiting review.
+func (s *Service) RemindPendingReview(ctx context.Context, asOf time.Time) (int, error) {
+	shifts, err := s.repo.ListUnreviewedShifts(ctx, nil, asOf)
+	if err != nil {
+		if saveErr := s.repo.SaveReviewRun(ctx, asOf, false, nil); saveErr != nil {
+			slog.Error("failed to record pending review run", "error", saveErr)
+		}
+		return 0, err
+	}
+
+	var reminders []ReviewReminder
+	byUser := make(map[uuid.UUID]int)
+	for _, shift := range shifts {
+		i, ok := byUser[shift.UserID]
+		if !ok {
+			i = len(reminders)
+			byUser[shift.UserID] = i
+			reminders = append(reminders, ReviewReminder{UserID: shift.UserID, OldestStart: shift.StartTime})
+		}
+		reminders[i].Pending++
+		if shift.StartTime.Before(reminders[i].OldestStart) {
+			reminders[i].OldestStart = shift.StartTime
+		}
+	}
+	if err := s.repo.SaveReviewRun(ctx, asOf, true, reminders); err != nil {
+		return 0, err
+	}
+
+	return len(shifts), nil
+}
+
+// LastReviewRun reports how the last nightly review went for the user and
+// how many of their shifts it found awaiting review, so the app can prompt
+// for them and a failed run shows up rather than only in the logs.
+func (s *Service) LastReviewRun(ctx context.Context, userID uuid.UUID) (*ReviewRun, error) {
+	run, err := s.repo.GetLastReviewRun(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, ErrNoReviewRun
+	}
+	if !run.Succeeded {
+		run.Error = "the shifts awaiting review could not be checked"
+	}
+	return run, nil
+}
+
+func checkTransition(shift *Shift, to ShiftStatus, now time.Time) error {
+	if !CanTransition(shift.Status, to) {
+		return ErrInvalidTransition
+	}
+	if to == ShiftStatusCompleted && shift.EndTime.After(now) {
+		return ErrShiftNotEnded
+	}
+	return nil
+}
+
 func (s *Service) DeleteShift(ctx context.Context, id uuid.UUID) error {
 	shift, err := s.repo.GetShiftByID(ctx, id)
 	if err != nil {
@@ -204,6 +345,22 @@ func (s *Service) calculateAndStoreEarnings(ctx context.Context, shift *Shift) e
 		return err
 	}
 
+	// Edits to a completed shift keep its earnings confirmed, and earnings
+	// already paid stay paid
+	status := EarningStatusProjected
+	if shift.Status == ShiftStatusCompleted {
+		status = EarningStatusConfirmed
+	}
+	existing, err := s.repo.GetShiftEarnings(ctx, shift.ID)
+	if err != nil {
+		return err
+	}
+	for _, e := range existing {
+		if e.Status == EarningStatusPaid {
+			status = EarningStatusPaid
+		}
+	}
+
 	// Delete existing earnings for this shift
 	_ = s.repo.DeleteShiftEarnings(ctx, shift.ID)
 
@@ -220,18 +377,28 @@ func (s *Service) calculateAndStoreEarnings(ctx context.Context, shift *Shift) e
 
 	var shiftEarnings []*ShiftEarning
 	for _, seg := range segments {
-		shiftEarnings = append(shiftEarnings, &ShiftEarning{
-			ID:           uuid.New(),
-			ShiftID:      shift.ID,
-			SegmentStart: seg.Start,
-			SegmentEnd:   seg.End,
-			Hours:        seg.Hours,
-			RateCents:    seg.Rate,
-			AmountCents:  seg.Amount,
-			Status:       EarningStatusProjected,
-			CreatedAt:    time.Now(),
-			UpdatedAt:    time.Now(),
-		})
+		earning := &ShiftEarning{
+			ID:             uuid.New(),
+			ShiftID:        shift.ID,
+			PricingRuleID:  seg.PricingRuleID,
+			Kind:           seg.Kind,
+			SegmentStart:   seg.Start,
+			SegmentEnd:     seg.End,
+			Hours:          seg.Hours,
+			RateCents:      seg.Rate,
+			AmountCents:    seg.Amount,
+			Status:         status,
+			RateMultiplier: seg.RateMultiplier,
+			CreatedAt:      time.Now(),
+			UpdatedAt:      time.Now(),
+		}
+		if seg.PricingRuleID != nil {
+			earning.RuleName = &seg.RuleName
+		}
+		if seg.Kind != workplace.EarningKindTime {
+			earning.Quantity = &seg.Quantity
+		}
+		shiftEarnings = append(shiftEarnings, earning)
 	}
 
 	if len(shiftEarnings) > 0 {
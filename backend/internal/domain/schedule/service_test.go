@@ -17,13 +17,19 @@ import (
 // ---------------------------------------------------------------------------
 
 type mockScheduleRepo struct {
-	mu     sync.Mutex
-	shifts map[uuid.UUID]*Shift
+	mu       sync.Mutex
+	shifts   map[uuid.UUID]*Shift
+	earnings map[uuid.UUID][]*ShiftEarning
+	listErr  error
+
+	reviewRuns []ReviewRun
+	reminders  map[uuid.UUID]ReviewReminder
 }
 
 func newMockScheduleRepo() *mockScheduleRepo {
 	return &mockScheduleRepo{
-		shifts: make(map[uuid.UUID]*Shift),
+		shifts:   make(map[uuid.UUID]*Shift),
+		earnings: make(map[uuid.UUID][]*ShiftEarning),
 	}
 }
 
@@ -80,6 +86,50 @@ func (m *mockScheduleRepo) BulkCreateShifts(_ context.Context, shifts []*Shift)
 	return nil
 }
 
+func (m *mockScheduleRepo) ListUnreviewedShifts(_ context.Context, userID *uuid.UUID, endedBefore time.Time) ([]*Shift, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.listErr != nil {
+		return nil, m.listErr
+	}
+	var result []*Shift
+	for _, s := range m.shifts {
+		if userID != nil && s.UserID != *userID {
+			continue
+		}
+		if (s.Status == ShiftStatusScheduled || s.Status == ShiftStatusConfirmed) && s.EndTime.Before(endedBefore) {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockScheduleRepo) SaveReviewRun(_ context.Context, ranAt time.Time, succeeded bool, reminders []ReviewReminder) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reviewRuns = append(m.reviewRuns, ReviewRun{RanAt: ranAt, Succeeded: succeeded})
+	if succeeded {
+		m.reminders = make(map[uuid.UUID]ReviewReminder)
+		for _, rem := range reminders {
+			m.reminders[rem.UserID] = rem
+		}
+	}
+	return nil
+}
+
+func (m *mockScheduleRepo) GetLastReviewRun(_ context.Context, userID uuid.UUID) (*ReviewRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.reviewRuns) == 0 {
+		return nil, nil
+	}
+	run := m.reviewRuns[len(m.reviewRuns)-1]
+	if rem, ok := m.reminders[userID]; ok {
+		run.Pending, run.OldestStart = rem.Pending, &rem.OldestStart
+	}
+	return &run, nil
+}
+
 func (m *mockScheduleRepo) CreateRecurrenceRule(_ context.Context, _ *RecurrenceRule) error {
 	return nil
 }
@@ -96,22 +146,50 @@ func (m *mockScheduleRepo) DeleteRecurrenceRule(_ context.Context, _ uuid.UUID)
 	return nil
 }
 
-func (m *mockScheduleRepo) CreateShiftEarnings(_ context.Context, _ []*ShiftEarning) error {
+func (m *mockScheduleRepo) CreateShiftEarnings(_ context.Context, earnings []*ShiftEarning) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range earnings {
+		m.earnings[e.ShiftID] = append(m.earnings[e.ShiftID], e)
+	}
 	return nil
 }
 
-func (m *mockScheduleRepo) GetShiftEarnings(_ context.Context, _ uuid.UUID) ([]*ShiftEarning, error) {
-	return nil, nil
+func (m *mockScheduleRepo) GetShiftEarnings(_ context.Context, shiftID uuid.UUID) ([]*ShiftEarning, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.earnings[shiftID], nil
 }
 
-func (m *mockScheduleRepo) DeleteShiftEarnings(_ context.Context, _ uuid.UUID) error {
+func (m *mockScheduleRepo) DeleteShiftEarnings(_ context.Context, shiftID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.earnings, shiftID)
 	return nil
 }
 
-func (m *mockScheduleRepo) UpdateEarningStatus(_ context.Context, _ uuid.UUID, _ EarningStatus) error {
+func (m *mockScheduleRepo) ConfirmProjectedEarnings(_ context.Context, shiftID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range m.earnings[shiftID] {
+		if e.Status == EarningStatusProjected {
+			e.Status = EarningStatusConfirmed
+		}
+	}
 	return nil
 }
 
+// earningStatuses returns the distinct statuses of a shift's earnings.
+func (m *mockScheduleRepo) earningStatuses(shiftID uuid.UUID) map[EarningStatus]bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	statuses := make(map[EarningStatus]bool)
+	for _, e := range m.earnings[shiftID] {
+		statuses[e.Status] = true
+	}
+	return statuses
+}
+
 // ---------------------------------------------------------------------------
 // Mock workplace repository
 // ---------------------------------------------------------------------------
@@ -119,6 +197,7 @@ func (m *mockScheduleRepo) UpdateEarningStatus(_ context.Context, _ uuid.UUID, _
 type mockWorkplaceRepo struct {
 	mu         sync.Mutex
 	workplaces map[uuid.UUID]*workplace.Workplace
+	rules      []*workplace.PricingRule
 }
 
 func newMockWorkplaceRepo() *mockWorkplaceRepo {
@@ -186,8 +265,10 @@ func (m *mockWorkplaceRepo) GetPricingRuleByID(_ context.Context, _ uuid.UUID) (
 }
 
 func (m *mockWorkplaceRepo) ListPricingRules(_ context.Context, _ uuid.UUID, _ bool) ([]*workplace.PricingRule, error) {
-	// Return empty rules so earnings calculation uses base rate only.
-	return nil, nil
+	// Empty unless a test seeds rules, so earnings calculation uses base rate only.
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.rules, nil
 }
 
 func (m *mockWorkplaceRepo) UpdatePricingRule(_ context.Context, _ *workplace.PricingRule) error {
@@ -439,3 +520,265 @@ func TestUpdateShift_NotFound(t *testing.T) {
 		t.Fatalf("expected ErrShiftNotFound, got: %v", err)
 	}
 }
+
+func TestUpdateShiftStatus_Transitions(t *testing.T) {
+	svc, schedRepo, wpRepo := newTestScheduleService()
+	ctx := context.Background()
+
+	wp := seedWorkplace(wpRepo)
+	start := time.Now().Add(-10 * time.Hour)
+
+	shift, err := svc.CreateShift(ctx, wp.UserID, CreateShiftInput{
+		WorkplaceID: wp.ID,
+		StartTime:   start,
+		EndTime:     start.Add(8 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("CreateShift failed: %v", err)
+	}
+
+	if _, err := svc.UpdateShiftStatus(ctx, uuid.New(), shift.ID, ShiftStatusConfirmed); !errors.Is(err, ErrShiftNotFound) {
+		t.Fatalf("expected ErrShiftNotFound for another user, got: %v", err)
+	}
+
+	updated, err := svc.UpdateShiftStatus(ctx, wp.UserID, shift.ID, ShiftStatusConfirmed)
+	if err != nil {
+		t.Fatalf("confirming shift failed: %v", err)
+	}
+	if updated.Status != ShiftStatusConfirmed {
+		t.Errorf("expected status %q, got %q", ShiftStatusConfirmed, updated.Status)
+	}
+	if got := schedRepo.earningStatuses(shift.ID); len(got) != 1 || !got[EarningStatusProjected] {
+		t.Errorf("expected earnings to stay projected until the shift is completed, got %v", got)
+	}
+
+	if _, err := svc.UpdateShiftStatus(ctx, wp.UserID, shift.ID, ShiftStatusScheduled); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition going back to scheduled, got: %v", err)
+	}
+
+	if _, err := svc.UpdateShiftStatus(ctx, wp.UserID, shift.ID, ShiftStatusCompleted); err != nil {
+		t.Fatalf("completing shift failed: %v", err)
+	}
+	if got := schedRepo.earningStatuses(shift.ID); len(got) != 1 || !got[EarningStatusConfirmed] {
+		t.Errorf("expected earnings confirmed on completion, got %v", got)
+	}
+
+	if _, err := svc.UpdateShiftStatus(ctx, wp.UserID, shift.ID, ShiftStatusCancelled); err != nil {
+		t.Fatalf("cancelling shift failed: %v", err)
+	}
+	if _, err := svc.UpdateShiftStatus(ctx, wp.UserID, shift.ID, ShiftStatusScheduled); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected cancelled shift to stay cancelled, got: %v", err)
+	}
+}
+
+func TestConfirmEarnings_PaidEarningsStayPaid(t *testing.T) {
+	svc, schedRepo, wpRepo := newTestScheduleService()
+	ctx := context.Background()
+
+	wp := seedWorkplace(wpRepo)
+	start := time.Now().Add(-10 * time.Hour)
+	shift, err := svc.CreateShift(ctx, wp.UserID, CreateShiftInput{
+		WorkplaceID: wp.ID,
+		StartTime:   start,
+		EndTime:     start.Add(8 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("CreateShift failed: %v", err)
+	}
+	if _, err := svc.ConfirmEarnings(ctx, wp.UserID, shift.ID); err != nil {
+		t.Fatalf("ConfirmEarnings failed: %v", err)
+	}
+
+	// Payment reconciliation marks the earnings paid
+	for _, e := range schedRepo.earnings[shift.ID] {
+		e.Status = EarningStatusPaid
+	}
+
+	if _, err := svc.ConfirmEarnings(ctx, wp.UserID, shift.ID); err != nil {
+		t.Fatalf("confirming again failed: %v", err)
+	}
+	if got := schedRepo.earningStatuses(shift.ID); len(got) != 1 || !got[EarningStatusPaid] {
+		t.Errorf("expected earnings to stay paid when confirmed again, got %v", got)
+	}
+
+	// Editing the shift recalculates its earnings without unpaying them
+	patients := 12
+	if _, err := svc.UpdateShift(ctx, shift.ID, UpdateShiftInput{PatientsSeen: &patients}); err != nil {
+		t.Fatalf("UpdateShift failed: %v", err)
+	}
+	if got := schedRepo.earningStatuses(shift.ID); len(got) != 1 || !got[EarningStatusPaid] {
+		t.Errorf("expected recalculated earnings to stay paid, got %v", got)
+	}
+}
+
+func TestConfirmEarnings_FutureShift(t *testing.T) {
+	svc, _, wpRepo := newTestScheduleService()
+	ctx := context.Background()
+
+	wp := seedWorkplace(wpRepo)
+	start := time.Now().Add(24 * time.Hour)
+
+	shift, err := svc.CreateShift(ctx, wp.UserID, CreateShiftInput{
+		WorkplaceID: wp.ID,
+		StartTime:   start,
+		EndTime:     start.Add(8 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("CreateShift failed: %v", err)
+	}
+
+	if _, err := svc.ConfirmEarnings(ctx, wp.UserID, shift.ID); !errors.Is(err, ErrShiftNotEnded) {
+		t.Fatalf("expected ErrShiftNotEnded, got: %v", err)
+	}
+}
+
+func TestListPendingReview(t *testing.T) {
+	svc, _, wpRepo := newTestScheduleService()
+	ctx := context.Background()
+
+	wp := seedWorkplace(wpRepo)
+	now := time.Date(2025, 6, 20, 3, 0, 0, 0, time.UTC)
+
+	create := func(day int) *Shift {
+		start := time.Date(2025, 6, day, 8, 0, 0, 0, time.UTC)
+		shift, err := svc.CreateShift(ctx, wp.UserID, CreateShiftInput{WorkplaceID: wp.ID, StartTime: start, EndTime: start.Add(8 * time.Hour)})
+		if err != nil {
+			t.Fatalf("CreateShift failed: %v", err)
+		}
+		return shift
+	}
+	past := create(15)
+	done := create(16)
+	create(25) // still ahead
+
+	if _, err := svc.UpdateShiftStatus(ctx, wp.UserID, done.ID, ShiftStatusCompleted); err != nil {
+		t.Fatalf("completing shift failed: %v", err)
+	}
+
+	pending, err := svc.ListPendingReview(ctx, wp.UserID, now)
+	if err != nil {
+		t.Fatalf("ListPendingReview failed: %v", err)
+	}
+	if len(pending) != 1 || pending[0].ID != past.ID {
+		t.Fatalf("expected only the unreviewed past shift, got %d shifts", len(pending))
+	}
+
+	if _, err := svc.LastReviewRun(ctx, wp.UserID); !errors.Is(err, ErrNoReviewRun) {
+		t.Fatalf("expected ErrNoReviewRun before the first run, got %v", err)
+	}
+	count, err := svc.RemindPendingReview(ctx, now)
+	if err != nil || count != 1 {
+		t.Fatalf("expected 1 shift awaiting review, got %d (err %v)", count, err)
+	}
+	run, err := svc.LastReviewRun(ctx, wp.UserID)
+	if err != nil || !run.Succeeded || run.Pending != 1 || !run.RanAt.Equal(now) || run.OldestStart == nil || !run.OldestStart.Equal(past.StartTime) {
+		t.Errorf("expected a successful run finding 1 shift, got %+v (err %v)", run, err)
+	}
+
+	// Reviewing the shift clears the reminder on the next run
+	if _, err := svc.UpdateShiftStatus(ctx, wp.UserID, past.ID, ShiftStatusCompleted); err != nil {
+		t.Fatalf("completing shift failed: %v", err)
+	}
+	if _, err := svc.RemindPendingReview(ctx, now.AddDate(0, 0, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if run, err := svc.LastReviewRun(ctx, wp.UserID); err != nil || run.Pending != 0 || run.OldestStart != nil {
+		t.Errorf("expected no shifts awaiting review, got %+v (err %v)", run, err)
+	}
+}
+
+func TestRemindPendingReview_FailureIsReported(t *testing.T) {
+	svc, schedRepo, _ := newTestScheduleService()
+	ctx := context.Background()
+	userID := uuid.New()
+	earlier := time.Date(2025, 6, 19, 3, 0, 0, 0, time.UTC)
+	schedRepo.SaveReviewRun(ctx, earlier, true, []ReviewReminder{{UserID: userID, Pending: 2, OldestStart: earlier.AddDate(0, 0, -3)}})
+	schedRepo.listErr = errors.New("connection refused")
+
+	now := earlier.AddDate(0, 0, 1)
+	if _, err := svc.RemindPendingReview(ctx, now); err == nil {
+		t.Fatal("expected the run to fail")
+	}
+	run, err := svc.LastReviewRun(ctx, userID)
+	if err != nil || run.Succeeded || run.Error == "" || !run.RanAt.Equal(now) {
+		t.Errorf("expected the failed run to be reported, got %+v (err %v)", run, err)
+	}
+	// The last successful run's reminder is kept
+	if run != nil && run.Pending != 2 {
+		t.Errorf("expected the earlier reminder to stay, got %d pending", run.Pending)
+	}
+}
+
+func TestGetShift_EarningsAttribution(t *testing.T) {
+	svc, _, wpRepo := newTestScheduleService()
+	ctx := context.Background()
+
+	wp := seedWorkplace(wpRepo)
+	wp.HasConsultationPay = true
+	wp.HasOutsideVisitPay = true
+
+	nightStart, nightEnd := "20:00", "08:00"
+	multiplier := 1.5
+	consultRate := money.Cents(500)
+	visitRate := money.Cents(3000)
+	night := &workplace.PricingRule{
+		ID:                    uuid.New(),
+		WorkplaceID:           wp.ID,
+		Name:                  "Night",
+		Priority:              1,
+		TimeStart:             &nightStart,
+		TimeEnd:               &nightEnd,
+		RateMultiplier:        &multiplier,
+		ConsultationRateCents: &consultRate,
+		OutsideVisitRateCents: &visitRate,
+		IsActive:              true,
+	}
+	wpRepo.rules = []*workplace.PricingRule{night}
+
+	// 16:00-24:00: four hours at base rate, four at night rate
+	start := time.Date(2025, 6, 16, 16, 0, 0, 0, time.UTC)
+	patients, visits := 10, 2
+	shift, err := svc.CreateShift(ctx, wp.UserID, CreateShiftInput{
+		WorkplaceID:   wp.ID,
+		StartTime:     start,
+		EndTime:       start.Add(8 * time.Hour),
+		PatientsSeen:  &patients,
+		OutsideVisits: &visits,
+	})
+	if err != nil {
+		t.Fatalf("CreateShift failed: %v", err)
+	}
+
+	got, err := svc.GetShift(ctx, shift.ID)
+	if err != nil {
+		t.Fatalf("GetShift failed: %v", err)
+	}
+	if len(got.Earnings) != 4 {
+		t.Fatalf("expected 2 time segments and 2 add-ons, got %d", len(got.Earnings))
+	}
+
+	base, nightSeg := got.Earnings[0], got.Earnings[1]
+	if base.Kind != workplace.EarningKindTime || base.PricingRuleID != nil || base.RuleName != "" || base.Amount != 10000 {
+		t.Errorf("expected an unattributed base segment of 10000, got %+v", base)
+	}
+	if nightSeg.PricingRuleID == nil || *nightSeg.PricingRuleID != night.ID || nightSeg.RuleName != "Night" {
+		t.Errorf("expected night segment attributed to the night rule, got %+v", nightSeg)
+	}
+	if nightSeg.RateMultiplier == nil || *nightSeg.RateMultiplier != 1.5 || nightSeg.Amount != 15000 {
+		t.Errorf("expected night segment at 1.5x for 15000, got %+v", nightSeg)
+	}
+
+	// Consultations only pay in the half of the shift the night rule covers
+	consult := got.Earnings[2]
+	if consult.Kind != workplace.EarningKindConsultation || consult.Quantity != 10 || consult.Hours != 0 || consult.Amount != 2500 {
+		t.Errorf("unexpected consultation line %+v", consult)
+	}
+	visit := got.Earnings[3]
+	if visit.Kind != workplace.EarningKindOutsideVisit || visit.Quantity != 2 || visit.Amount != 6000 {
+		t.Errorf("unexpected outside visit line %+v", visit)
+	}
+
+	if got.TotalEarnings != 33500 {
+		t.Errorf("expected total 33500, got %d", got.TotalEarnings)
+	}
+}
@@ -5,6 +5,7 @@ import "github.com/joao-moreira/doctor-tracker/pkg/money"
 // Portugal2026Config returns the tax configuration for fiscal year 2026.
 func Portugal2026Config() YearConfig {
 	return YearConfig{
+		Country:    "PT",
 		FiscalYear: 2026,
 		Brackets: []IRSBracket{
 			{LowerLimit: 0, UpperLimit: money.FromEuros(7703), Rate: 0.125, Deduction: 0},
@@ -17,18 +18,40 @@ func Portugal2026Config() YearConfig {
 			{LowerLimit: money.FromEuros(51997), UpperLimit: money.FromEuros(81199), Rate: 0.435, Deduction: money.FromEuros(8129.40)},
 			{LowerLimit: money.FromEuros(81199), UpperLimit: money.Cents(999999999999), Rate: 0.480, Deduction: money.FromEuros(11782.35)},
 		},
+		SolidarityBrackets:     portugalSolidarity(),
 		SSRate:                 0.214,
 		SSIncomeCoefficient:    0.70,
 		IASValueCents:          money.FromEuros(537.13),
 		DefaultWithholdingRate: 0.23,
 		MinExistenceCents:      money.FromEuros(12880),
 		SimplifiedCoefficient:  0.75,
+
+		ExpenseJustificationRate:    0.15,
+		AutomaticJustificationCents: money.FromEuros(4104),
+		SpecificDeductionCents:      money.FromEuros(4104),
+
+		Deductions: portugalDeductions(),
+
+		IRSJovemExemptions: []float64{1, 0.75, 0.75, 0.75, 0.50, 0.50, 0.50, 0.25, 0.25, 0.25},
+		IRSJovemCapIAS:     55,
+		IRSJovemMaxAge:     35,
+
+		IFICIRate:  0.20,
+		IFICIYears: 10,
+
+		SSExemptionMonths: 12,
+
+		WithholdingDispensationCents: money.FromEuros(15000),
+
+		PaymentOnAccountRate:         0.765,
+		PaymentOnAccountMinimumCents: money.FromEuros(50),
 	}
 }
 
 // Portugal2025Config returns the tax configuration for fiscal year 2025.
 func Portugal2025Config() YearConfig {
 	return YearConfig{
+		Country:    "PT",
 		FiscalYear: 2025,
 		Brackets: []IRSBracket{
 			{LowerLimit: 0, UpperLimit: money.FromEuros(7479), Rate: 0.130, Deduction: 0},
@@ -41,11 +64,100 @@ func Portugal2025Config() YearConfig {
 			{LowerLimit: money.FromEuros(50483), UpperLimit: money.FromEuros(78834), Rate: 0.435, Deduction: money.FromEuros(7853.77)},
 			{LowerLimit: money.FromEuros(78834), UpperLimit: money.Cents(999999999999), Rate: 0.480, Deduction: money.FromEuros(11401.30)},
 		},
+		SolidarityBrackets:     portugalSolidarity(),
 		SSRate:                 0.214,
 		SSIncomeCoefficient:    0.70,
 		IASValueCents:          money.FromEuros(522.50),
 		DefaultWithholdingRate: 0.23,
 		MinExistenceCents:      money.FromEuros(12180),
 		SimplifiedCoefficient:  0.75,
+
+		ExpenseJustificationRate:    0.15,
+		AutomaticJustificationCents: money.FromEuros(4104),
+		SpecificDeductionCents:      money.FromEuros(4104),
+
+		Deductions: portugalDeductions(),
+
+		IRSJovemExemptions: []float64{1, 0.75, 0.75, 0.75, 0.50, 0.50, 0.50, 0.25, 0.25, 0.25},
+		IRSJovemCapIAS:     55,
+		IRSJovemMaxAge:     35,
+
+		IFICIRate:  0.20,
+		IFICIYears: 10,
+
+		SSExemptionMonths: 12,
+
+		WithholdingDispensationCents: money.FromEuros(15000),
+
+		PaymentOnAccountRate:         0.765,
+		PaymentOnAccountMinimumCents: money.FromEuros(50),
+	}
+}
+
+// portugalDeductions returns the personal deductions, unchanged since 2022.
+func portugalDeductions() DeductionConfig {
+	return DeductionConfig{
+		DependentCents:             money.FromEuros(600),
+		YoungDependentExtraCents:   money.FromEuros(126),
+		SubsequentYoungExtraCents:  money.FromEuros(300),
+		YoungDependentMaxAge:       6,
+		GeneralFamilyRate:          0.35,
+		GeneralFamilyCapCents:      money.FromEuros(250),
+		SingleParentFamilyRate:     0.45,
+		SingleParentFamilyCapCents: money.FromEuros(335),
+		HealthRate:                 0.15,
+		HealthCapCents:             money.FromEuros(1000),
+		EducationRate:              0.30,
+		EducationCapCents:          money.FromEuros(800),
+
+		CeilingUpperCents:     money.FromEuros(2500),
+		CeilingLowerCents:     money.FromEuros(1000),
+		CeilingThresholdCents: money.FromEuros(80000),
+	}
+}
+
+// portugalSolidarity returns the solidarity surcharge bands, unchanged since
+// 2013.
+func portugalSolidarity() []IRSBracket {
+	return []IRSBracket{
+		{LowerLimit: money.FromEuros(80000), UpperLimit: money.FromEuros(250000), Rate: 0.025},
+		{LowerLimit: money.FromEuros(250000), UpperLimit: money.Cents(999999999999), Rate: 0.05},
+	}
+}
+
+// IRSJovemYear returns the year of activity under IRS Jovem for a fiscal year,
+// or zero when the taxpayer is past the benefit period or the age limit. An
+// unknown birth year is taken as within the limit.
+func IRSJovemYear(config YearConfig, firstYear, birthYear *int) int {
+	if firstYear == nil {
+		return 0
+	}
+	if birthYear != nil && config.FiscalYear-*birthYear > config.IRSJovemMaxAge {
+		return 0
+	}
+	activityYear := config.FiscalYear - *firstYear + 1
+	if activityYear < 1 || activityYear > len(config.IRSJovemExemptions) {
+		return 0
+	}
+	return activityYear
+}
+
+// IFICIApplies reports whether a fiscal year falls in the IFICI period that
+// started in startYear.
+func IFICIApplies(config YearConfig, startYear *int) bool {
+	return startYear != nil && config.FiscalYear >= *startYear && config.FiscalYear < *startYear+config.IFICIYears
+}
+
+// ConfigForYear returns the configuration for a fiscal year, falling back to
+// the most recent known year for years without published tables.
+func ConfigForYear(year int) YearConfig {
+	switch year {
+	case 2025:
+		return Portugal2025Config()
+	case 2026:
+		return Portugal2026Config()
 	}
+	config := Portugal2026Config()
+	config.FiscalYear = year
+	return config
 }
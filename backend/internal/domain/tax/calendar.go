@@ -0,0 +1,118 @@
+package tax
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+// DeadlineKind is the obligation a deadline belongs to.
+type DeadlineKind string
+
+const (
+	DeadlineSSDeclaration    DeadlineKind = "ss_declaration"
+	DeadlineSSPayment        DeadlineKind = "ss_payment"
+	DeadlineIRSReturn        DeadlineKind = "irs_return"
+	DeadlineIRSPayment       DeadlineKind = "irs_payment"
+	DeadlinePaymentOnAccount DeadlineKind = "payment_on_account"
+)
+
+// The Portuguese IRS dates (arts. 60, 104 and 105 CIRS): the return for a
+// year is filed between 1 April and 30 June of the next, tax assessed on it
+// is paid by 31 August, and payments on account fall due on the 20th of
+// July, September and December.
+var (
+	irsReturnOpens       = [2]int{4, 1}
+	irsReturnDue         = [2]int{6, 30}
+	irsPaymentDue        = [2]int{8, 31}
+	paymentsOnAccountDue = [3][2]int{{7, 20}, {9, 20}, {12, 20}}
+)
+
+// Deadline is a date by which something must be filed or paid. Filing and
+// payment windows also give the day they open.
+type Deadline struct {
+	Kind   DeadlineKind `json:"kind"`
+	Title  string       `json:"title"`
+	Period string       `json:"period"` // what it concerns: YYYY-MM, YYYY-Qn or YYYY
+	Opens  *time.Time   `json:"opens,omitempty"`
+	Due    time.Time    `json:"due"`
+
+	Income    money.Cents `json:"income,omitempty"` // income to declare
+	Amount    money.Cents `json:"amount,omitempty"` // amount to pay
+	Estimated bool        `json:"estimated"`        // rests on income not earned or assessed yet
+	Done      bool        `json:"done"`             // recorded as filed or paid
+}
+
+// Calendar is a taxpayer's deadlines falling within a year, in date order.
+type Calendar struct {
+	Year      int        `json:"year"`
+	Country   string     `json:"country"`
+	Deadlines []Deadline `json:"deadlines"`
+}
+
+// NewCalendar starts an empty calendar for a year in a jurisdiction.
+func NewCalendar(year int, country string) *Calendar {
+	return &Calendar{Year: year, Country: country, Deadlines: []Deadline{}}
+}
+
+// Add files deadlines that fall within the calendar's year and drops the
+// rest, keeping the calendar in date order.
+func (c *Calendar) Add(deadlines ...Deadline) {
+	for _, d := range deadlines {
+		if d.Due.Year() == c.Year {
+			c.Deadlines = append(c.Deadlines, d)
+		}
+	}
+	sort.SliceStable(c.Deadlines, func(i, j int) bool {
+		return c.Deadlines[i].Due.Before(c.Deadlines[j].Due)
+	})
+}
+
+// IRSDeadlines are the year's Portuguese IRS deadlines: the return for the
+// previous year, the payment of what it leaves due when settlement is
+// positive, and the instalments paid on account of the year's own tax.
+// Settlement and instalments are estimates until the tax office assesses
+// them, and instalments only stop being estimates once paid; instalments of
+// zero are not due.
+func IRSDeadlines(year int, settlement money.Cents, instalments [3]Instalment, loc *time.Location) []Deadline {
+	date := func(md [2]int) time.Time {
+		return time.Date(year, time.Month(md[0]), md[1], 0, 0, 0, 0, loc)
+	}
+	prior := fmt.Sprint(year - 1)
+
+	opens := date(irsReturnOpens)
+	deadlines := []Deadline{{
+		Kind:   DeadlineIRSReturn,
+		Title:  "IRS return for " + prior,
+		Period: prior,
+		Opens:  &opens,
+		Due:    date(irsReturnDue),
+	}}
+	if settlement > 0 {
+		deadlines = append(deadlines, Deadline{
+			Kind:      DeadlineIRSPayment,
+			Title:     "IRS payment for " + prior,
+			Period:    prior,
+			Due:       date(irsPaymentDue),
+			Amount:    settlement,
+			Estimated: true,
+		})
+	}
+	for i, instalment := range instalments {
+		if instalment.Amount <= 0 {
+			continue
+		}
+		deadlines = append(deadlines, Deadline{
+			Kind:      DeadlinePaymentOnAccount,
+			Title:     fmt.Sprintf("IRS payment on account %d/3", i+1),
+			Period:    fmt.Sprint(year),
+			Due:       PaymentOnAccountDue(year, i+1, loc),
+			Amount:    instalment.Amount,
+			Estimated: !instalment.Paid,
+			Done:      instalment.Paid,
+		})
+	}
+	return deadlines
+}
@@ -0,0 +1,97 @@
+package tax
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+func TestIRSDeadlines(t *testing.T) {
+	instalments := [3]Instalment{{Amount: money.FromEuros(480), Paid: true}, {Amount: money.FromEuros(500)}, {Amount: money.FromEuros(500)}}
+	deadlines := IRSDeadlines(2026, money.FromEuros(1200), instalments, time.UTC)
+
+	if len(deadlines) != 5 {
+		t.Fatalf("expected the return, its payment and three instalments, got %d", len(deadlines))
+	}
+	ret := deadlines[0]
+	if ret.Kind != DeadlineIRSReturn || ret.Period != "2025" || ret.Opens == nil ||
+		!ret.Opens.Equal(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)) || !ret.Due.Equal(time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected return deadline %+v", ret)
+	}
+	if pay := deadlines[1]; pay.Kind != DeadlineIRSPayment || pay.Amount != money.FromEuros(1200) || pay.Due.Month() != time.August {
+		t.Errorf("unexpected payment deadline %+v", pay)
+	}
+	for i, month := range []time.Month{time.July, time.September, time.December} {
+		if d := deadlines[2+i]; d.Kind != DeadlinePaymentOnAccount || d.Due.Month() != month || d.Due.Day() != 20 {
+			t.Errorf("unexpected payment on account %+v", d)
+		}
+	}
+	if paid := deadlines[2]; !paid.Done || paid.Estimated || paid.Amount != money.FromEuros(480) {
+		t.Errorf("expected the first instalment recorded as paid, got %+v", paid)
+	}
+
+	// A refund and no payments on account leave only the return
+	if got := IRSDeadlines(2026, -money.FromEuros(300), [3]Instalment{}, time.UTC); len(got) != 1 {
+		t.Errorf("expected only the return, got %+v", got)
+	}
+}
+
+func TestCalendar_Add(t *testing.T) {
+	calendar := NewCalendar(2026, "PT")
+	calendar.Add(
+		Deadline{Title: "June", Due: time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)},
+		Deadline{Title: "next year", Due: time.Date(2027, 1, 20, 0, 0, 0, 0, time.UTC)},
+		Deadline{Title: "January", Due: time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)},
+	)
+	if len(calendar.Deadlines) != 2 || calendar.Deadlines[0].Title != "January" || calendar.Deadlines[1].Title != "June" {
+		t.Errorf("expected the year's deadlines in date order, got %+v", calendar.Deadlines)
+	}
+}
+
+func TestCalendar_WriteICS(t *testing.T) {
+	opens := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	calendar := NewCalendar(2026, "PT")
+	calendar.Add(Deadline{
+		Kind:      DeadlineIRSReturn,
+		Title:     "IRS return for 2025, including Anexo B; the simplified regime's expenses must be confirmed on e-Fatura",
+		Period:    "2025",
+		Opens:     &opens,
+		Due:       time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC),
+		Amount:    money.FromEuros(1200),
+		Estimated: true,
+	})
+
+	var buf bytes.Buffer
+	if err := calendar.WriteICS(&buf, time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	ics := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:PT-irs_return-20260630@doctor-tracker\r\n",
+		"DTSTAMP:20260301T093000Z\r\n",
+		"DTSTART;VALUE=DATE:20260630\r\n",
+		"DTEND;VALUE=DATE:20260701\r\n",
+		"TRIGGER:-P7D\r\n",
+		"TRIGGER:-P1D\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("expected %q in the feed", want)
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		if len(line) > icsLineLimit {
+			t.Errorf("line longer than %d octets: %q", icsLineLimit, line)
+		}
+	}
+	unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+	if !strings.Contains(unfolded, `SUMMARY:IRS return for 2025\, including Anexo B\; the simplified regime's expenses must be confirmed on e-Fatura (EUR 1200.00)`+"\r\n") {
+		t.Errorf("expected the escaped summary to unfold intact, got %q", unfolded)
+	}
+}
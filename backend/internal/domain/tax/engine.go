@@ -2,37 +2,184 @@ package tax
 
 import "github.com/joao-moreira/doctor-tracker/pkg/money"
 
-// Engine calculates Portuguese taxes for independent workers.
+// Engine calculates a jurisdiction's taxes for independent workers. Income,
+// results and YearConfig are shared; each engine reads the fields its rules
+// use.
 type Engine interface {
-	CalculateIRS(config YearConfig, annualGrossIncome money.Cents) IRSResult
+	CalculateIRS(config YearConfig, income Income) IRSResult
 	CalculateSocialSecurity(config YearConfig, quarterlyGrossIncome money.Cents) SSResult
+	CalculateSSDeclaration(config YearConfig, quarterlyGrossIncome money.Cents, baseAdjustment float64) SSResult
 	CalculateWithholding(grossAmount money.Cents, rate float64) money.Cents
-	CalculateAnnualSummary(config YearConfig, annualGrossIncome money.Cents) AnnualSummary
+	CalculateExpenseJustification(config YearConfig, income Income) JustificationResult
+	CalculateAnnualSummary(config YearConfig, income Income) AnnualSummary
+	SimulateSettlement(config YearConfig, input SettlementInput) Settlement
+	CompareRegimes(config YearConfig, income Income) RegimeComparison
+}
+
+// Regime is how Category B taxable income is determined.
+type Regime string
+
+const (
+	// RegimeSimplified taxes a coefficient of gross income (art. 31 CIRS).
+	RegimeSimplified Regime = "simplified"
+	// RegimeOrganized taxes gross income less deductible expenses, as
+	// determined by a certified accountant (art. 32 CIRS).
+	RegimeOrganized Regime = "organized"
+)
+
+// Income is a fiscal year's Category B income together with the expenses
+// that affect how much of it is taxed.
+type Income struct {
+	Gross money.Cents `json:"gross"`
+	// Regime defaults to the simplified regime when empty.
+	Regime Regime `json:"regime,omitempty"`
+	// Activity expenses communicated on e-Fatura, counted toward the
+	// simplified regime's expense justification requirement.
+	JustifiedExpenses money.Cents `json:"justified_expenses"`
+	// Activity expenses deductible under organized accounting, after the
+	// limits on mixed personal and professional use.
+	DeductibleExpenses money.Cents `json:"deductible_expenses"`
+	// Year of activity under IRS Jovem, from 1; zero when it does not apply.
+	IRSJovemYear int `json:"irs_jovem_year,omitempty"`
+	// IFICI taxes the income at a flat rate instead of the brackets.
+	IFICI bool `json:"ifici,omitempty"`
+	// WithholdingDispensed is the art. 101-B dispensation from withholding.
+	WithholdingDispensed bool `json:"withholding_dispensed,omitempty"`
+	// Category A income, which the brackets tax together with Gross.
+	Employment Employment `json:"employment"`
+	// SSContributions are the year's contributions on Category B income as
+	// set by its quarterly declarations. When nil they are estimated from
+	// Gross spread evenly over the quarters.
+	SSContributions *money.Cents `json:"ss_contributions,omitempty"`
+}
+
+// Employment is a year's Category A income from employment contracts, as
+// the employers declare it.
+type Employment struct {
+	Gross          money.Cents `json:"gross"`
+	Withholding    money.Cents `json:"withholding"`
+	SocialSecurity money.Cents `json:"social_security"` // the employee's contributions
+}
+
+// Organized reports whether income is taxed under organized accounting.
+func (i Income) Organized() bool {
+	return i.Regime == RegimeOrganized
+}
+
+// AnnualContributions is the year's Social Security on Category B income:
+// as declared when the income carries it, otherwise the engine's estimate.
+func AnnualContributions(e Engine, config YearConfig, income Income) money.Cents {
+	if income.SSContributions != nil {
+		return *income.SSContributions
+	}
+	return e.CalculateSocialSecurity(config, income.Gross/4).AnnualEstimate
 }
 
 type YearConfig struct {
+	Country                string      `json:"country"` // ISO 3166 code of the jurisdiction
 	FiscalYear             int         `json:"fiscal_year"`
 	Brackets               []IRSBracket `json:"brackets"`
+	// Taxa adicional de solidariedade (art. 68-A CIRS), in the same form
+	SolidarityBrackets []IRSBracket `json:"solidarity_brackets"`
 	SSRate                 float64     `json:"ss_rate"`                  // 0.214
 	SSIncomeCoefficient    float64     `json:"ss_income_coefficient"`    // 0.70
 	IASValueCents          money.Cents `json:"ias_value_cents"`          // IAS in cents
 	DefaultWithholdingRate float64     `json:"default_withholding_rate"` // 0.23
 	MinExistenceCents      money.Cents `json:"min_existence_cents"`
 	SimplifiedCoefficient  float64     `json:"simplified_coefficient"`   // 0.75 for Cat B services
+
+	// Art. 31(13) CIRS: part of the deemed 25% must be backed by real expenses
+	ExpenseJustificationRate    float64     `json:"expense_justification_rate"`    // 0.15 of gross
+	AutomaticJustificationCents money.Cents `json:"automatic_justification_cents"` // 4,104 EUR counted without receipts
+
+	// Deducted from Category A income before it is taxed: the art. 25 CIRS
+	// specific deduction, or the contributions when higher. In Spain, the
+	// other expenses of employment (art. 19.2.f LIRPF) on top of them
+	SpecificDeductionCents money.Cents `json:"specific_deduction_cents"` // 4,104 EUR; 2,000 EUR in Spain
+
+	Deductions DeductionConfig `json:"deductions"`
+
+	// IRS Jovem (art. 12-B CIRS): share of income exempt in each year of
+	// activity, the first at index 0, capped at a multiple of the IAS
+	IRSJovemExemptions []float64 `json:"irs_jovem_exemptions"`
+	IRSJovemCapIAS     float64   `json:"irs_jovem_cap_ias"` // 55
+	IRSJovemMaxAge     int       `json:"irs_jovem_max_age"` // 35
+
+	// IFICI (art. 58-A EBF), the successor to the NHR regime
+	IFICIRate  float64 `json:"ifici_rate"`  // 0.20
+	IFICIYears int     `json:"ifici_years"` // 10
+
+	// New independent workers pay no contributions for their first months
+	// of activity (art. 164 Código Contributivo)
+	SSExemptionMonths int `json:"ss_exemption_months"` // 12
+
+	// Taxpayers whose yearly Category B income stays under this amount may
+	// be dispensed from withholding (art. 101-B CIRS)
+	WithholdingDispensationCents money.Cents `json:"withholding_dispensation_cents"` // 15,000 EUR
+
+	// Payments on account (art. 102 CIRS): the share of the tax assessed two
+	// years before that is paid in advance, unless each of the three
+	// instalments would come to less than the minimum
+	PaymentOnAccountRate         float64     `json:"payment_on_account_rate,omitempty"`          // 0.765
+	PaymentOnAccountMinimumCents money.Cents `json:"payment_on_account_minimum_cents,omitempty"` // 50 EUR
+
+	// Spain: the mínimo personal is taxed at zero, the simplified direct
+	// estimate deducts a share of net income for expenses hard to justify,
+	// and RETA contributions follow bands of monthly net income
+	PersonalAllowanceCents money.Cents  `json:"personal_allowance_cents,omitempty"` // 5,550 EUR
+	FlatExpenseRate        float64      `json:"flat_expense_rate,omitempty"`        // 0.05
+	FlatExpenseCapCents    money.Cents  `json:"flat_expense_cap_cents,omitempty"`   // 2,000 EUR
+	SSBaseBands            []SSBaseBand `json:"ss_base_bands,omitempty"`
+}
+
+// SSBaseBand is a band of monthly relevant income and the contribution base
+// a worker in it may choose between. The last band has no upper limit.
+type SSBaseBand struct {
+	UpperLimit money.Cents `json:"upper_limit"`
+	MinBase    money.Cents `json:"min_base"`
+	MaxBase    money.Cents `json:"max_base"`
+}
+
+// DeductionConfig holds the personal deductions from the collected tax
+// (dedução à coleta, arts. 78-A to 78-D CIRS).
+type DeductionConfig struct {
+	DependentCents             money.Cents `json:"dependent_cents"`              // 600 EUR per dependent
+	YoungDependentExtraCents   money.Cents `json:"young_dependent_extra_cents"`  // up to 6 years old
+	SubsequentYoungExtraCents  money.Cents `json:"subsequent_young_extra_cents"` // second and later, up to 6 years old
+	YoungDependentMaxAge       int         `json:"young_dependent_max_age"`
+	GeneralFamilyRate          float64     `json:"general_family_rate"`            // 0.35
+	GeneralFamilyCapCents      money.Cents `json:"general_family_cap_cents"`       // per taxpayer
+	SingleParentFamilyRate     float64     `json:"single_parent_family_rate"`      // 0.45
+	SingleParentFamilyCapCents money.Cents `json:"single_parent_family_cap_cents"` // per taxpayer
+	HealthRate                 float64     `json:"health_rate"`                    // 0.15
+	HealthCapCents             money.Cents `json:"health_cap_cents"`
+	EducationRate              float64     `json:"education_rate"` // 0.30
+	EducationCapCents          money.Cents `json:"education_cap_cents"`
+
+	// Art. 78(7): above the first bracket, health and education deductions
+	// together are capped, tapering from the upper to the lower cap as
+	// taxable income approaches the threshold.
+	CeilingUpperCents     money.Cents `json:"ceiling_upper_cents"`     // 2,500 EUR
+	CeilingLowerCents     money.Cents `json:"ceiling_lower_cents"`     // 1,000 EUR
+	CeilingThresholdCents money.Cents `json:"ceiling_threshold_cents"` // 80,000 EUR
 }
 
 type IRSBracket struct {
 	LowerLimit money.Cents `json:"lower_limit"`
 	UpperLimit money.Cents `json:"upper_limit"`
 	Rate       float64     `json:"rate"`
-	Deduction  money.Cents `json:"deduction"`
+	Deduction  money.Cents `json:"deduction"` // parcela a abater
 }
 
 type IRSResult struct {
-	TaxableIncome    money.Cents      `json:"taxable_income"`
-	TotalTax         money.Cents      `json:"total_tax"`
-	EffectiveRate    float64          `json:"effective_rate"`
-	BracketBreakdown []BracketResult  `json:"bracket_breakdown"`
+	TaxableIncome     money.Cents     `json:"taxable_income"`
+	IRSJovemExempt    money.Cents     `json:"irs_jovem_exempt,omitempty"`
+	EmploymentTaxable money.Cents     `json:"employment_taxable,omitempty"` // Category A after its deduction, included in taxable_income
+	IFICITax          money.Cents     `json:"ifici_tax,omitempty"`
+	SolidarityTax     money.Cents     `json:"solidarity_tax,omitempty"` // included in total_tax
+	TotalTax          money.Cents     `json:"total_tax"`
+	EffectiveRate     float64         `json:"effective_rate"`
+	BracketBreakdown  []BracketResult `json:"bracket_breakdown"`
 }
 
 type BracketResult struct {
@@ -42,20 +189,62 @@ type BracketResult struct {
 	TaxAmount       money.Cents `json:"tax_amount"`
 }
 
+// JustificationResult compares the expenses the simplified regime requires
+// with those on record. Any shortfall is added back to taxable income.
+type JustificationResult struct {
+	Required  money.Cents `json:"required"`
+	Automatic money.Cents `json:"automatic"`
+	Justified money.Cents `json:"justified"`
+	Shortfall money.Cents `json:"shortfall"`
+}
+
 type SSResult struct {
 	RelevantIncome      money.Cents `json:"relevant_income"`
+	BaseAdjustment      float64     `json:"base_adjustment,omitempty"` // chosen by the worker, -0.25 to 0.25
 	MonthlyBase         money.Cents `json:"monthly_base"`
 	MonthlyContribution money.Cents `json:"monthly_contribution"`
 	QuarterlyPayment    money.Cents `json:"quarterly_payment"`
 	AnnualEstimate      money.Cents `json:"annual_estimate"`
 }
 
+// RegimeResult is a year's tax burden under one regime.
+type RegimeResult struct {
+	Regime           Regime      `json:"regime"`
+	TaxableIncome    money.Cents `json:"taxable_income"`
+	ExpenseShortfall money.Cents `json:"expense_shortfall"` // simplified regime only
+	Deductions       money.Cents `json:"deductions"`        // expenses and contributions deducted, organized regime only
+	IRSAmount        money.Cents `json:"irs_amount"`
+	SSAnnual         money.Cents `json:"ss_annual"`
+	TotalTax         money.Cents `json:"total_tax"` // IRS plus Social Security
+	NetIncome        money.Cents `json:"net_income"`
+}
+
+// RegimeComparison sets the simplified and organized regimes side by side for
+// the same income. Savings is how much less the recommended regime costs.
+type RegimeComparison struct {
+	FiscalYear  int          `json:"fiscal_year"`
+	GrossIncome money.Cents  `json:"gross_income"`
+	Current     Regime       `json:"current,omitempty"`
+	Simplified  RegimeResult `json:"simplified"`
+	Organized   RegimeResult `json:"organized"`
+	Recommended Regime       `json:"recommended"`
+	Savings     money.Cents  `json:"savings"`
+	// Expense level at which the organized regime starts to pay off, before
+	// any accountant fees
+	BreakEvenExpenses money.Cents `json:"break_even_expenses"`
+}
+
 type AnnualSummary struct {
-	GrossIncome      money.Cents `json:"gross_income"`
+	GrossIncome      money.Cents `json:"gross_income"`                // both categories
+	EmploymentIncome money.Cents `json:"employment_income,omitempty"` // Category A, included in gross_income
 	TaxableIncome    money.Cents `json:"taxable_income"`
+	ExpenseShortfall money.Cents `json:"expense_shortfall"`
+	IRSJovemExempt   money.Cents `json:"irs_jovem_exempt,omitempty"` // income left out of IRS Jovem's taxable income
+	IFICITax         money.Cents `json:"ifici_tax,omitempty"`        // IRS at the IFICI flat rate, included in irs_amount
 	IRSAmount        money.Cents `json:"irs_amount"`
 	IRSEffectiveRate float64     `json:"irs_effective_rate"`
 	SSAnnual         money.Cents `json:"ss_annual"`
+	EmploymentSS     money.Cents `json:"employment_ss,omitempty"` // the employee's contributions on Category A
 	WithholdingTotal money.Cents `json:"withholding_total"`
 	NetIncome        money.Cents `json:"net_income"`
 	MonthlyNet       money.Cents `json:"monthly_net"`
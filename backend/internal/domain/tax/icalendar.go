@@ -0,0 +1,110 @@
+package tax
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ReminderDays are how many days ahead of each deadline a calendar
+// application is asked to remind the user.
+var ReminderDays = []int{7, 1}
+
+// icsLineLimit is the longest content line RFC 5545 allows, in octets.
+const icsLineLimit = 75
+
+// WriteICS writes the calendar as an iCalendar (RFC 5545) feed of all-day
+// events on each due date, with reminders ahead of them. stamp is when the
+// feed was generated.
+func (c *Calendar) WriteICS(w io.Writer, stamp time.Time) error {
+	bw := bufio.NewWriter(w)
+	line := func(s string) {
+		writeICSLine(bw, s)
+	}
+	dtstamp := stamp.UTC().Format("20060102T150405Z")
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//doctor-tracker//tax calendar//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line(fmt.Sprintf("X-WR-CALNAME:Tax deadlines %d", c.Year))
+
+	for _, d := range c.Deadlines {
+		summary := d.Title
+		if d.Amount > 0 {
+			summary += " (" + d.Amount.String() + ")"
+		}
+
+		line("BEGIN:VEVENT")
+		line(fmt.Sprintf("UID:%s-%s-%s@doctor-tracker", c.Country, d.Kind, d.Due.Format("20060102")))
+		line("DTSTAMP:" + dtstamp)
+		line("DTSTART;VALUE=DATE:" + d.Due.Format("20060102"))
+		line("DTEND;VALUE=DATE:" + d.Due.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:" + icsEscape(summary))
+		line("DESCRIPTION:" + icsEscape(d.description()))
+		line("CATEGORIES:" + string(d.Kind))
+		line("TRANSP:TRANSPARENT")
+		for _, days := range ReminderDays {
+			line("BEGIN:VALARM")
+			line("ACTION:DISPLAY")
+			line(fmt.Sprintf("TRIGGER:-P%dD", days))
+			line("DESCRIPTION:" + icsEscape(summary))
+			line("END:VALARM")
+		}
+		line("END:VEVENT")
+	}
+
+	line("END:VCALENDAR")
+	return bw.Flush()
+}
+
+// description spells out the deadline for the event body.
+func (d Deadline) description() string {
+	var parts []string
+	if d.Opens != nil {
+		parts = append(parts, "Opens "+d.Opens.Format("2 January 2006")+".")
+	}
+	parts = append(parts, "Due "+d.Due.Format("2 January 2006")+".")
+	if d.Income > 0 {
+		parts = append(parts, "Income to declare: "+d.Income.String()+".")
+	}
+	if d.Amount > 0 {
+		parts = append(parts, "Amount: "+d.Amount.String()+".")
+	}
+	if d.Estimated {
+		parts = append(parts, "Estimated from the data recorded so far.")
+	}
+	if d.Done {
+		parts = append(parts, "Recorded as done.")
+	}
+	return strings.Join(parts, " ")
+}
+
+// icsEscape escapes a TEXT value.
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// writeICSLine ends a content line with CRLF, folding it so no physical line
+// is longer than the limit. Folds never split a UTF-8 sequence.
+func writeICSLine(w *bufio.Writer, s string) {
+	limit := icsLineLimit
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+		// the leading space of a continuation line counts towards its length
+		limit = icsLineLimit - 1
+	}
+	w.WriteString(s + "\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,56 @@
+package tax
+
+// Jurisdiction is a country's tax system: the engine that applies its rules
+// and its configuration for each fiscal year.
+type Jurisdiction struct {
+	Code   string // ISO 3166 country code
+	Name   string
+	Engine Engine
+	Config func(year int) YearConfig
+}
+
+// Portugal is the Portuguese tax system for Category B income.
+func Portugal() Jurisdiction {
+	return Jurisdiction{Code: "PT", Name: "Portugal", Engine: NewPortugalEngine(), Config: ConfigForYear}
+}
+
+// Spain is the Spanish tax system for autónomos.
+func Spain() Jurisdiction {
+	return Jurisdiction{Code: "ES", Name: "Spain", Engine: NewSpainEngine(), Config: SpainConfigForYear}
+}
+
+// Registry selects a jurisdiction by country code. The first jurisdiction
+// registered is the default, used for users and income sources that do not
+// name one.
+type Registry struct {
+	byCode   map[string]Jurisdiction
+	fallback Jurisdiction
+}
+
+func NewRegistry(fallback Jurisdiction, others ...Jurisdiction) *Registry {
+	r := &Registry{byCode: map[string]Jurisdiction{fallback.Code: fallback}, fallback: fallback}
+	for _, j := range others {
+		r.byCode[j.Code] = j
+	}
+	return r
+}
+
+// DefaultRegistry holds every jurisdiction with an engine, Portugal first.
+func DefaultRegistry() *Registry {
+	return NewRegistry(Portugal(), Spain())
+}
+
+// Lookup returns the jurisdiction for a country code, if one is registered.
+func (r *Registry) Lookup(code string) (Jurisdiction, bool) {
+	j, ok := r.byCode[code]
+	return j, ok
+}
+
+// Resolve returns the jurisdiction for a country code, or the default one
+// when the code is empty or has no engine.
+func (r *Registry) Resolve(code string) Jurisdiction {
+	if j, ok := r.byCode[code]; ok {
+		return j
+	}
+	return r.fallback
+}
@@ -0,0 +1,55 @@
+package tax
+
+import (
+	"time"
+
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+// PaymentOnAccountBasis is what the payments on account of a year are worked
+// out from (art. 102 CIRS): the return for the year before last, the latest
+// one assessed by the time the first instalment falls due.
+type PaymentOnAccountBasis struct {
+	FiscalYear      int         `json:"fiscal_year"`
+	AssessedTax     money.Cents `json:"assessed_tax"`      // coleta líquida
+	CategoryBIncome money.Cents `json:"category_b_income"` // taxable Category B income
+	TotalIncome     money.Cents `json:"total_income"`      // taxable income of every category
+	Withholding     money.Cents `json:"withholding"`       // withheld on the Category B income
+}
+
+// Instalment is one payment on account of a year's tax, estimated or, once
+// recorded, paid.
+type Instalment struct {
+	Amount money.Cents `json:"amount"`
+	Paid   bool        `json:"paid"`
+}
+
+// PaymentsOnAccount are the three instalments paid during a year on account
+// of its Category B tax: the basis's tax in the proportion of Category B to
+// total net income (art. 102 CIRS), less the withholding on that income, at
+// the year's rate and split evenly. Nothing is due when an instalment would
+// come to less than the minimum.
+func PaymentsOnAccount(config YearConfig, basis PaymentOnAccountBasis) [3]money.Cents {
+	var instalments [3]money.Cents
+
+	tax := basis.AssessedTax
+	if basis.TotalIncome > 0 && basis.CategoryBIncome < basis.TotalIncome {
+		tax = money.Cents(float64(tax) * float64(basis.CategoryBIncome) / float64(basis.TotalIncome))
+	}
+	total := applyRate(tax-basis.Withholding, config.PaymentOnAccountRate)
+	if total <= 0 || total/3 < config.PaymentOnAccountMinimumCents {
+		return instalments
+	}
+	for i := range instalments {
+		instalments[i] = total / 3
+	}
+	instalments[2] += total % 3
+	return instalments
+}
+
+// PaymentOnAccountDue is the date the instalment, numbered from 1, falls due
+// in a year.
+func PaymentOnAccountDue(year, instalment int, loc *time.Location) time.Time {
+	md := paymentsOnAccountDue[instalment-1]
+	return time.Date(year, time.Month(md[0]), md[1], 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,62 @@
+package tax
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+func TestPaymentsOnAccount(t *testing.T) {
+	config := Portugal2026Config()
+
+	// 76.5% of 10,000 EUR assessed less 4,000 withheld
+	got := PaymentsOnAccount(config, PaymentOnAccountBasis{AssessedTax: money.FromEuros(10000), Withholding: money.FromEuros(4000)})
+	if want := money.FromEuros(1530); got[0] != want || got[1] != want || got[2] != want {
+		t.Errorf("expected three instalments of %d, got %v", want, got)
+	}
+
+	// 4,590.01 does not split evenly; the last instalment takes the odd cent
+	got = PaymentsOnAccount(config, PaymentOnAccountBasis{AssessedTax: money.FromEuros(6000.01)})
+	if got[0]+got[1]+got[2] != money.FromEuros(4590.01) || got[2] != got[0]+1 {
+		t.Errorf("expected the remainder on the last instalment, got %v", got)
+	}
+
+	// Only the tax on Category B income is paid on account: 40,000 of
+	// 50,000 EUR leaves 8,000 of the 10,000 assessed
+	got = PaymentsOnAccount(config, PaymentOnAccountBasis{
+		AssessedTax:     money.FromEuros(10000),
+		CategoryBIncome: money.FromEuros(40000),
+		TotalIncome:     money.FromEuros(50000),
+		Withholding:     money.FromEuros(2000),
+	})
+	if want := money.FromEuros(1530); got[0] != want {
+		t.Errorf("expected instalments of %d on the Category B share, got %v", want, got)
+	}
+
+	// Instalments under 50 EUR are not due, nor is anything when
+	// withholding covered the tax
+	for _, withholding := range []money.Cents{money.FromEuros(9850), money.FromEuros(12000)} {
+		basis := PaymentOnAccountBasis{AssessedTax: money.FromEuros(10000), Withholding: withholding}
+		if got := PaymentsOnAccount(config, basis); got != [3]money.Cents{} {
+			t.Errorf("expected nothing due after %d withheld, got %v", withholding, got)
+		}
+	}
+
+	// Spain has no payments on account in the engine
+	if got := PaymentsOnAccount(Spain2026Config(), PaymentOnAccountBasis{AssessedTax: money.FromEuros(10000)}); got != [3]money.Cents{} {
+		t.Errorf("expected nothing due in Spain, got %v", got)
+	}
+}
+
+func TestPaymentOnAccountDue(t *testing.T) {
+	for i, want := range []time.Time{
+		time.Date(2026, 7, 20, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 9, 20, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 12, 20, 0, 0, 0, 0, time.UTC),
+	} {
+		if got := PaymentOnAccountDue(2026, i+1, time.UTC); !got.Equal(want) {
+			t.Errorf("instalment %d: expected %s, got %s", i+1, want, got)
+		}
+	}
+}
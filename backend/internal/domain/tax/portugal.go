@@ -14,76 +14,241 @@ func NewPortugalEngine() *PortugalEngine {
 	return &PortugalEngine{}
 }
 
-func (e *PortugalEngine) CalculateIRS(config YearConfig, annualGrossIncome money.Cents) IRSResult {
-	// Step 1: Determine taxable income (simplified regime)
-	taxableIncome := money.Cents(float64(annualGrossIncome) * config.SimplifiedCoefficient)
+func (e *PortugalEngine) CalculateIRS(config YearConfig, income Income) IRSResult {
+	annualGrossIncome := income.Gross + income.Employment.Gross
 
-	// Step 2: Apply progressive brackets
-	var totalTax money.Cents
+	// Step 1: Determine taxable income under the regime, plus any Category A
+	// income after its specific deduction
+	parts := e.taxableIncome(config, income)
+	taxableIncome := parts.taxable + parts.employment
+
+	var totalTax, ificiTax, solidarity money.Cents
 	var breakdown []BracketResult
-	remaining := taxableIncome
+	if income.IFICI {
+		// Step 2: IFICI income is taxed on its own at a flat rate and only
+		// Category A income goes through the brackets
+		var line BracketResult
+		ificiTax, line = flatRateTax(config, parts.taxable)
+		employmentTax, lines := collectTax(config, parts.employment)
+		solidarity, _ = solidarityTax(config, parts.employment)
+		totalTax = ificiTax + employmentTax
+		breakdown = append([]BracketResult{line}, lines...)
+	} else {
+		// Steps 2-3: Progressive brackets and solidarity surcharge
+		totalTax, breakdown = collectTax(config, taxableIncome)
+		solidarity, _ = solidarityTax(config, taxableIncome)
 
-	for _, bracket := range config.Brackets {
-		if remaining <= 0 {
-			break
+		// Step 4: Minimum existence check
+		postTaxIncome := annualGrossIncome - totalTax
+		if postTaxIncome < config.MinExistenceCents {
+			totalTax = annualGrossIncome - config.MinExistenceCents
+			if totalTax < 0 {
+				totalTax = 0
+			}
 		}
+	}
+
+	var effectiveRate float64
+	if taxableIncome > 0 {
+		effectiveRate = float64(totalTax) / float64(taxableIncome)
+	}
+
+	return IRSResult{
+		TaxableIncome:     taxableIncome,
+		IRSJovemExempt:    parts.exempt + parts.employmentExempt,
+		EmploymentTaxable: parts.employment,
+		IFICITax:          ificiTax,
+		SolidarityTax:     solidarity,
+		TotalTax:          totalTax,
+		EffectiveRate:     effectiveRate,
+		BracketBreakdown:  breakdown,
+	}
+}
+
+// taxableParts traces Category B income, and any Category A income, on
+// their way to taxable income.
+type taxableParts struct {
+	exempt        money.Cents         // IRS Jovem
+	justification JustificationResult // simplified regime
+	deductions    money.Cents         // organized regime
+	taxable       money.Cents
+
+	employmentExempt    money.Cents // IRS Jovem on Category A
+	employmentDeduction money.Cents // art. 25 specific deduction
+	employment          money.Cents // Category A taxable income
+}
+
+// taxableIncome determines Category B taxable income once any IRS Jovem
+// exemption is set aside. The simplified regime taxes the coefficient of
+// gross plus whatever part of the required expenses was not justified.
+// Organized accounting deducts activity expenses and the compulsory Social
+// Security contributions, and a loss is taxed as zero. Category A income is
+// taxed less the specific deduction, or the employee's contributions when
+// they are higher, which never takes it below zero (art. 25 CIRS).
+func (e *PortugalEngine) taxableIncome(config YearConfig, income Income) taxableParts {
+	var parts taxableParts
+	parts.exempt, parts.employmentExempt = irsJovemExemption(config, income)
+
+	employed := income.Employment.Gross - parts.employmentExempt
+	parts.employmentDeduction = min(max(config.SpecificDeductionCents, income.Employment.SocialSecurity), employed)
+	if income.Employment.Gross == 0 {
+		parts.employmentDeduction = 0
+	}
+	parts.employment = employed - parts.employmentDeduction
+
+	gross := income.Gross - parts.exempt
 
-		bracketWidth := bracket.UpperLimit - bracket.LowerLimit
-		taxableInBracket := remaining
-		if taxableInBracket > bracketWidth {
-			taxableInBracket = bracketWidth
+	if income.Organized() {
+		contributions := AnnualContributions(e, config, income)
+		parts.deductions = income.DeductibleExpenses + contributions
+		parts.taxable = gross - parts.deductions
+		if parts.taxable < 0 {
+			parts.taxable = 0
 		}
+		return parts
+	}
+
+	remaining := income
+	remaining.Gross = gross
+	parts.justification = e.CalculateExpenseJustification(config, remaining)
+	parts.taxable = money.Cents(float64(gross)*config.SimplifiedCoefficient) + parts.justification.Shortfall
+	return parts
+}
+
+// irsJovemExemption is the part of gross income IRS Jovem leaves untaxed in
+// the income's year of activity, shared between the Category B and Category
+// A income in proportion to each. It does not combine with IFICI.
+func irsJovemExemption(config YearConfig, income Income) (categoryB, categoryA money.Cents) {
+	if income.IFICI || income.IRSJovemYear < 1 || income.IRSJovemYear > len(config.IRSJovemExemptions) {
+		return 0, 0
+	}
+	gross := income.Gross + income.Employment.Gross
+	exempt := money.Cents(float64(gross) * config.IRSJovemExemptions[income.IRSJovemYear-1])
+	if limit := money.Cents(float64(config.IASValueCents) * config.IRSJovemCapIAS); exempt > limit {
+		exempt = limit
+	}
+	if gross > 0 {
+		categoryA = money.Cents(float64(exempt) * float64(income.Employment.Gross) / float64(gross))
+	}
+	return exempt - categoryA, categoryA
+}
+
+// flatRateTax taxes IFICI income at the flat rate, outside the brackets.
+func flatRateTax(config YearConfig, taxableIncome money.Cents) (money.Cents, BracketResult) {
+	tax := applyRate(taxableIncome, config.IFICIRate)
+	return tax, BracketResult{
+		BracketLabel:     fmt.Sprintf("IFICI %.1f%%", config.IFICIRate*100),
+		TaxableInBracket: taxableIncome,
+		Rate:             config.IFICIRate,
+		TaxAmount:        tax,
+	}
+}
 
-		tax := money.Cents(float64(taxableInBracket) * bracket.Rate)
-		totalTax += tax
-		remaining -= taxableInBracket
+// collectTax applies the progressive brackets and the solidarity surcharge
+// (taxa adicional de solidariedade) to a taxable income.
+//
+// Art. 68 CIRS taxes the part of the income up to the previous bracket's
+// limit at that limit's average rate and the excess at the bracket's normal
+// rate. That is the same as the normal rate on the whole income less the
+// bracket's parcela a abater, which is how the tax is computed here, to the
+// cent. The breakdown spreads the income over the brackets for display; its
+// top line absorbs the cents by which the published parcelas differ from
+// the sum of the slices, so the lines always add up to the tax.
+func collectTax(config YearConfig, taxableIncome money.Cents) (money.Cents, []BracketResult) {
+	if taxableIncome <= 0 || len(config.Brackets) == 0 {
+		return 0, nil
+	}
 
+	top := len(config.Brackets) - 1
+	for i, bracket := range config.Brackets {
+		if taxableIncome <= bracket.UpperLimit {
+			top = i
+			break
+		}
+	}
+	totalTax := applyRate(taxableIncome, config.Brackets[top].Rate) - config.Brackets[top].Deduction
+	if totalTax < 0 {
+		totalTax = 0
+	}
+
+	var breakdown []BracketResult
+	var sliced money.Cents
+	for _, bracket := range config.Brackets[:top+1] {
+		upper := bracket.UpperLimit
+		if taxableIncome < upper {
+			upper = taxableIncome
+		}
+		inBracket := upper - bracket.LowerLimit
+		tax := applyRate(inBracket, bracket.Rate)
+		sliced += tax
 		breakdown = append(breakdown, BracketResult{
 			BracketLabel:     fmt.Sprintf("%.1f%%", bracket.Rate*100),
-			TaxableInBracket: taxableInBracket,
+			TaxableInBracket: inBracket,
 			Rate:             bracket.Rate,
 			TaxAmount:        tax,
 		})
 	}
+	breakdown[len(breakdown)-1].TaxAmount += totalTax - sliced
 
-	// Step 3: Solidarity surcharge (taxa adicional de solidariedade)
-	taxableEuros := taxableIncome.Euros()
-	if taxableEuros > 80000 {
-		solidarityBase1 := math.Min(taxableEuros, 250000) - 80000
-		totalTax += money.FromEuros(solidarityBase1 * 0.025)
-	}
-	if taxableEuros > 250000 {
-		totalTax += money.FromEuros((taxableEuros - 250000) * 0.05)
-	}
+	surcharge, lines := solidarityTax(config, taxableIncome)
+	return totalTax + surcharge, append(breakdown, lines...)
+}
 
-	// Step 4: Minimum existence check
-	postTaxIncome := annualGrossIncome - totalTax
-	if postTaxIncome < config.MinExistenceCents {
-		totalTax = annualGrossIncome - config.MinExistenceCents
-		if totalTax < 0 {
-			totalTax = 0
+// solidarityTax is the art. 68-A surcharge, which applies to the parts of
+// the taxable income above each threshold.
+func solidarityTax(config YearConfig, taxableIncome money.Cents) (money.Cents, []BracketResult) {
+	var total money.Cents
+	var lines []BracketResult
+	for _, band := range config.SolidarityBrackets {
+		if taxableIncome <= band.LowerLimit {
+			break
 		}
+		upper := band.UpperLimit
+		if taxableIncome < upper {
+			upper = taxableIncome
+		}
+		surcharge := applyRate(upper-band.LowerLimit, band.Rate)
+		total += surcharge
+		lines = append(lines, BracketResult{
+			BracketLabel:     fmt.Sprintf("Solidarity %.1f%%", band.Rate*100),
+			TaxableInBracket: upper - band.LowerLimit,
+			Rate:             band.Rate,
+			TaxAmount:        surcharge,
+		})
 	}
+	return total, lines
+}
 
-	var effectiveRate float64
-	if taxableIncome > 0 {
-		effectiveRate = float64(totalTax) / float64(taxableIncome)
-	}
+// rateScale is the precision rates are applied at: four decimal places,
+// enough for every rate the law sets.
+const rateScale = 10000
 
-	return IRSResult{
-		TaxableIncome:    taxableIncome,
-		TotalTax:         totalTax,
-		EffectiveRate:    effectiveRate,
-		BracketBreakdown: breakdown,
+// applyRate multiplies an amount by a rate in integer arithmetic, rounding
+// half away from zero to the cent, so results do not depend on float64
+// representation.
+func applyRate(amount money.Cents, rate float64) money.Cents {
+	product := int64(amount) * int64(math.Round(rate*rateScale))
+	if product < 0 {
+		return -money.Cents((-product + rateScale/2) / rateScale)
 	}
+	return money.Cents((product + rateScale/2) / rateScale)
 }
 
 func (e *PortugalEngine) CalculateSocialSecurity(config YearConfig, quarterlyGrossIncome money.Cents) SSResult {
+	return e.CalculateSSDeclaration(config, quarterlyGrossIncome, 0)
+}
+
+// CalculateSSDeclaration works out a quarterly declaration with the base
+// moved by the adjustment the worker chose, before the floor and ceiling.
+func (e *PortugalEngine) CalculateSSDeclaration(config YearConfig, quarterlyGrossIncome money.Cents, baseAdjustment float64) SSResult {
 	// Relevant income = quarterly gross * 70%
 	relevantIncome := money.Cents(float64(quarterlyGrossIncome) * config.SSIncomeCoefficient)
 
-	// Monthly contributory base
+	// Monthly contributory base, adjusted by up to 25% either way
 	monthlyBase := relevantIncome / 3
+	if baseAdjustment != 0 {
+		monthlyBase = money.Cents(float64(monthlyBase) * (1 + baseAdjustment))
+	}
 
 	// Apply floor (IAS-based minimum)
 	if monthlyBase < config.IASValueCents {
@@ -104,6 +269,7 @@ func (e *PortugalEngine) CalculateSocialSecurity(config YearConfig, quarterlyGro
 
 	return SSResult{
 		RelevantIncome:      relevantIncome,
+		BaseAdjustment:      baseAdjustment,
 		MonthlyBase:         monthlyBase,
 		MonthlyContribution: monthlyContribution,
 		QuarterlyPayment:    quarterlyPayment,
@@ -115,25 +281,111 @@ func (e *PortugalEngine) CalculateWithholding(grossAmount money.Cents, rate floa
 	return money.Cents(float64(grossAmount) * rate)
 }
 
-func (e *PortugalEngine) CalculateAnnualSummary(config YearConfig, annualGrossIncome money.Cents) AnnualSummary {
-	irsResult := e.CalculateIRS(config, annualGrossIncome)
+// CalculateExpenseJustification applies art. 31(13) CIRS: 15% of gross income
+// must be justified by the automatic amount plus activity expenses on e-Fatura.
+// The automatic amount is the specific deduction, so it is lost once that is
+// taken against Category A income.
+func (e *PortugalEngine) CalculateExpenseJustification(config YearConfig, income Income) JustificationResult {
+	required := money.Cents(float64(income.Gross) * config.ExpenseJustificationRate)
+	automatic := config.AutomaticJustificationCents
+	if income.Employment.Gross > 0 {
+		automatic = 0
+	}
+	if automatic > required {
+		automatic = required
+	}
+
+	shortfall := required - automatic - income.JustifiedExpenses
+	if shortfall < 0 {
+		shortfall = 0
+	}
+
+	return JustificationResult{
+		Required:  required,
+		Automatic: automatic,
+		Justified: income.JustifiedExpenses,
+		Shortfall: shortfall,
+	}
+}
 
-	// Estimate SS based on annual income (assume even quarterly distribution)
-	quarterlyGross := annualGrossIncome / 4
-	ssResult := e.CalculateSocialSecurity(config, quarterlyGross)
+// CalculateAnnualSummary works out the year's taxes on both categories of
+// income. Category A adds its own withholding and contributions, as the
+// employer declares them.
+func (e *PortugalEngine) CalculateAnnualSummary(config YearConfig, income Income) AnnualSummary {
+	annualGrossIncome := income.Gross
+	irsResult := e.CalculateIRS(config, income)
+	parts := e.taxableIncome(config, income)
+	contributions := AnnualContributions(e, config, income)
 
-	withholdingTotal := e.CalculateWithholding(annualGrossIncome, config.DefaultWithholdingRate)
+	// Assume every client is an entity withholding at the year's rate
+	rate := WithholdingRate(config, Payer{}, Dispensed(config, income.WithholdingDispensed, annualGrossIncome))
+	withholdingTotal := e.CalculateWithholding(annualGrossIncome, rate) + income.Employment.Withholding
 
-	netIncome := annualGrossIncome - irsResult.TotalTax - ssResult.AnnualEstimate
+	employment := income.Employment
+	netIncome := annualGrossIncome + employment.Gross - irsResult.TotalTax - contributions - employment.SocialSecurity
 
 	return AnnualSummary{
-		GrossIncome:      annualGrossIncome,
+		GrossIncome:      annualGrossIncome + employment.Gross,
+		EmploymentIncome: employment.Gross,
 		TaxableIncome:    irsResult.TaxableIncome,
+		ExpenseShortfall: parts.justification.Shortfall,
+		IRSJovemExempt:   irsResult.IRSJovemExempt,
+		IFICITax:         irsResult.IFICITax,
 		IRSAmount:        irsResult.TotalTax,
 		IRSEffectiveRate: irsResult.EffectiveRate,
-		SSAnnual:         ssResult.AnnualEstimate,
+		SSAnnual:         contributions,
+		EmploymentSS:     employment.SocialSecurity,
 		WithholdingTotal: withholdingTotal,
 		NetIncome:        netIncome,
 		MonthlyNet:       netIncome / 12,
 	}
 }
+
+// CompareRegimes works out the year's IRS and Social Security under both
+// regimes for the same income and expenses.
+func (e *PortugalEngine) CompareRegimes(config YearConfig, income Income) RegimeComparison {
+	simplified, organized := income, income
+	simplified.Regime = RegimeSimplified
+	organized.Regime = RegimeOrganized
+
+	comparison := RegimeComparison{
+		FiscalYear:  config.FiscalYear,
+		GrossIncome: income.Gross,
+		Simplified:  e.regimeResult(config, simplified),
+		Organized:   e.regimeResult(config, organized),
+		Recommended: RegimeSimplified,
+	}
+
+	comparison.Savings = comparison.Simplified.TotalTax - comparison.Organized.TotalTax
+	if comparison.Savings > 0 {
+		comparison.Recommended = RegimeOrganized
+	} else {
+		comparison.Savings = -comparison.Savings
+	}
+
+	// Both regimes pay the same contributions, so organized accounting pays
+	// off once its taxable income drops below the simplified one
+	contributions := comparison.Organized.Deductions - income.DeductibleExpenses
+	parts := e.taxableIncome(config, simplified)
+	if breakEven := income.Gross - parts.exempt - contributions - parts.taxable; breakEven > 0 {
+		comparison.BreakEvenExpenses = breakEven
+	}
+
+	return comparison
+}
+
+func (e *PortugalEngine) regimeResult(config YearConfig, income Income) RegimeResult {
+	summary := e.CalculateAnnualSummary(config, income)
+	deductions := e.taxableIncome(config, income).deductions
+
+	return RegimeResult{
+		Regime:           income.Regime,
+		TaxableIncome:    summary.TaxableIncome,
+		ExpenseShortfall: summary.ExpenseShortfall,
+		Deductions:       deductions,
+		IRSAmount:        summary.IRSAmount,
+		SSAnnual:         summary.SSAnnual,
+		TotalTax:         summary.IRSAmount + summary.SSAnnual,
+		NetIncome:        summary.NetIncome,
+	}
+}
@@ -0,0 +1,234 @@
+package tax
+
+import "github.com/joao-moreira/doctor-tracker/pkg/money"
+
+// Household describes who files the return together (agregado familiar).
+type Household struct {
+	// Joint taxation (tributação conjunta, art. 69 CIRS) with a spouse or
+	// civil partner: the household's income is split in two before the
+	// brackets apply.
+	Joint               bool        `json:"joint"`
+	SpouseTaxableIncome money.Cents `json:"spouse_taxable_income"` // the spouse's own rendimento coletável
+	SpouseWithholding   money.Cents `json:"spouse_withholding"`
+	SingleParent        bool        `json:"single_parent"`
+	DependentAges       []int       `json:"dependent_ages"` // age on 31 December
+}
+
+// PersonalExpenses are the household's expenses communicated on e-Fatura
+// that give right to a deduction from the collected tax.
+type PersonalExpenses struct {
+	GeneralFamily money.Cents `json:"general_family"`
+	Health        money.Cents `json:"health"`
+	Education     money.Cents `json:"education"`
+}
+
+type SettlementInput struct {
+	Income      Income           `json:"income"`
+	Household   Household        `json:"household"`
+	Expenses    PersonalExpenses `json:"expenses"`
+	Withholding money.Cents      `json:"withholding"` // withheld on the taxpayer's Category B income
+	// Paid during the year on account of its tax (art. 102 CIRS)
+	PaymentsOnAccount money.Cents `json:"payments_on_account"`
+}
+
+// AnexoB mirrors the simplified regime fields of Anexo B for the taxpayer's
+// Category B income. Under organized accounting, declared on Anexo C, only
+// the income, the deducted expenses and the result are filled in.
+type AnexoB struct {
+	ProfessionalServices money.Cents         `json:"professional_services"` // Quadro 4, code 403: art. 151 activities
+	Coefficient          float64             `json:"coefficient"`
+	DeemedIncome         money.Cents         `json:"deemed_income"`
+	IRSJovemExempt       money.Cents         `json:"irs_jovem_exempt,omitempty"`
+	Justification        JustificationResult `json:"justification"`                 // art. 31(13) expenses; the shortfall is added back
+	DeductibleExpenses   money.Cents         `json:"deductible_expenses,omitempty"` // organized regime, including contributions
+	TaxableIncome        money.Cents         `json:"taxable_income"`
+	Withholding          money.Cents         `json:"withholding"` // IRS withheld by the paying entities
+}
+
+// AnexoA holds the taxpayer's Category A income as the employers declare it.
+type AnexoA struct {
+	Gross             money.Cents `json:"gross"`
+	Withholding       money.Cents `json:"withholding"`
+	SocialSecurity    money.Cents `json:"social_security"` // the employee's contributions
+	IRSJovemExempt    money.Cents `json:"irs_jovem_exempt,omitempty"`
+	SpecificDeduction money.Cents `json:"specific_deduction"` // art. 25, or the contributions when higher
+	TaxableIncome     money.Cents `json:"taxable_income"`
+}
+
+type DeductionBreakdown struct {
+	Dependents    money.Cents `json:"dependents"`
+	GeneralFamily money.Cents `json:"general_family"`
+	Health        money.Cents `json:"health"`
+	Education     money.Cents `json:"education"`
+	Ceiling       money.Cents `json:"ceiling,omitempty"` // art. 78(7) limit on health and education; 0 when none applies
+	Total         money.Cents `json:"total"`             // never more than the collected tax
+}
+
+// Settlement is the outcome of the annual IRS assessment (liquidação).
+// Balance is positive when tax is due and negative for a refund.
+type Settlement struct {
+	FiscalYear int     `json:"fiscal_year"`
+	AnexoA     *AnexoA `json:"anexo_a,omitempty"` // only with Category A income
+	AnexoB     AnexoB  `json:"anexo_b"`
+
+	Joint            bool            `json:"joint"`
+	TaxableIncome    money.Cents     `json:"taxable_income"` // household rendimento coletável
+	Quotient         int             `json:"quotient"`       // 2 under joint taxation
+	CollectedTax     money.Cents     `json:"collected_tax"`  // coleta total
+	IFICITax         money.Cents     `json:"ifici_tax,omitempty"`
+	BracketBreakdown []BracketResult `json:"bracket_breakdown"`
+
+	Deductions        DeductionBreakdown `json:"deductions"`
+	NetTax            money.Cents        `json:"net_tax"` // coleta líquida
+	Withholding       money.Cents        `json:"withholding"`
+	PaymentsOnAccount money.Cents        `json:"payments_on_account"`
+	Balance           money.Cents        `json:"balance"`
+	Refund            money.Cents        `json:"refund"`
+	AmountDue         money.Cents        `json:"amount_due"`
+}
+
+// SimulateSettlement assesses a year's IRS for the household: Category B
+// income under the taxpayer's regime and any Category A income after its
+// specific deduction, taxed together and split by the conjugal quotient when
+// filing jointly, less the personal deductions and all withholding and
+// payments on account already paid.
+func (e *PortugalEngine) SimulateSettlement(config YearConfig, input SettlementInput) Settlement {
+	household := input.Household
+	settlement := Settlement{FiscalYear: config.FiscalYear, Joint: household.Joint, Quotient: 1}
+
+	parts := e.taxableIncome(config, input.Income)
+	settlement.AnexoB = AnexoB{
+		ProfessionalServices: input.Income.Gross,
+		IRSJovemExempt:       parts.exempt,
+		DeductibleExpenses:   parts.deductions,
+		TaxableIncome:        parts.taxable,
+		Withholding:          input.Withholding,
+	}
+	if !input.Income.Organized() {
+		settlement.AnexoB.Coefficient = config.SimplifiedCoefficient
+		settlement.AnexoB.DeemedIncome = parts.taxable - parts.justification.Shortfall
+		settlement.AnexoB.Justification = parts.justification
+	}
+
+	employment := input.Income.Employment
+	if employment.Gross > 0 {
+		settlement.AnexoA = &AnexoA{
+			Gross:             employment.Gross,
+			Withholding:       employment.Withholding,
+			SocialSecurity:    employment.SocialSecurity,
+			IRSJovemExempt:    parts.employmentExempt,
+			SpecificDeduction: parts.employmentDeduction,
+			TaxableIncome:     parts.employment,
+		}
+	}
+
+	settlement.TaxableIncome = settlement.AnexoB.TaxableIncome + parts.employment
+	progressive, income := settlement.TaxableIncome, input.Income.Gross+employment.Gross
+	if input.Income.IFICI {
+		// Taxed on its own, so neither split nor counted for minimum existence
+		progressive, income = parts.employment, employment.Gross
+	}
+	settlement.Withholding = input.Withholding + employment.Withholding
+	if household.Joint {
+		settlement.Quotient = 2
+		settlement.TaxableIncome += household.SpouseTaxableIncome
+		progressive += household.SpouseTaxableIncome
+		income += household.SpouseTaxableIncome
+		settlement.Withholding += household.SpouseWithholding
+	}
+
+	quotient := money.Cents(settlement.Quotient)
+	perTaxpayer, breakdown := collectTax(config, progressive/quotient)
+	settlement.CollectedTax = perTaxpayer * quotient
+	settlement.BracketBreakdown = breakdown
+
+	// Minimum existence applies to each taxpayer
+	if limit := income - config.MinExistenceCents*quotient; settlement.CollectedTax > limit {
+		settlement.CollectedTax = limit
+		if settlement.CollectedTax < 0 {
+			settlement.CollectedTax = 0
+		}
+	}
+
+	if input.Income.IFICI {
+		tax, line := flatRateTax(config, parts.taxable)
+		settlement.IFICITax = tax
+		settlement.CollectedTax += tax
+		settlement.BracketBreakdown = append(settlement.BracketBreakdown, line)
+	}
+
+	settlement.Deductions = personalDeductions(config, household, input.Expenses, settlement.Quotient, settlement.TaxableIncome)
+	if settlement.Deductions.Total > settlement.CollectedTax {
+		settlement.Deductions.Total = settlement.CollectedTax
+	}
+	settlement.NetTax = settlement.CollectedTax - settlement.Deductions.Total
+
+	settlement.PaymentsOnAccount = input.PaymentsOnAccount
+	settlement.Balance = settlement.NetTax - settlement.Withholding - settlement.PaymentsOnAccount
+	if settlement.Balance > 0 {
+		settlement.AmountDue = settlement.Balance
+	} else {
+		settlement.Refund = -settlement.Balance
+	}
+
+	return settlement
+}
+
+// personalDeductions applies arts. 78-A to 78-D CIRS and the art. 78(7)
+// ceiling, which depends on the household's taxable income.
+func personalDeductions(config YearConfig, household Household, expenses PersonalExpenses, taxpayers int, taxableIncome money.Cents) DeductionBreakdown {
+	rules := config.Deductions
+	var d DeductionBreakdown
+
+	young := 0
+	for _, age := range household.DependentAges {
+		d.Dependents += rules.DependentCents
+		if age <= rules.YoungDependentMaxAge {
+			if young == 0 {
+				d.Dependents += rules.YoungDependentExtraCents
+			} else {
+				d.Dependents += rules.SubsequentYoungExtraCents
+			}
+			young++
+		}
+	}
+
+	familyRate, familyCap := rules.GeneralFamilyRate, rules.GeneralFamilyCapCents
+	if household.SingleParent {
+		familyRate, familyCap = rules.SingleParentFamilyRate, rules.SingleParentFamilyCapCents
+	}
+	d.GeneralFamily = capped(expenses.GeneralFamily, familyRate, familyCap*money.Cents(taxpayers))
+	d.Health = capped(expenses.Health, rules.HealthRate, rules.HealthCapCents)
+	d.Education = capped(expenses.Education, rules.EducationRate, rules.EducationCapCents)
+
+	limited := d.Health + d.Education
+	if ceiling, ok := deductionCeiling(config, taxableIncome); ok && limited > ceiling {
+		d.Ceiling = ceiling
+		limited = ceiling
+	}
+	d.Total = d.Dependents + d.GeneralFamily + limited
+	return d
+}
+
+// deductionCeiling is the art. 78(7) limit, or false within the first bracket
+// where no limit applies.
+func deductionCeiling(config YearConfig, taxableIncome money.Cents) (money.Cents, bool) {
+	rules := config.Deductions
+	if len(config.Brackets) == 0 || taxableIncome <= config.Brackets[0].UpperLimit {
+		return 0, false
+	}
+	if taxableIncome > rules.CeilingThresholdCents {
+		return rules.CeilingLowerCents, true
+	}
+	firstBracket := config.Brackets[0].UpperLimit
+	taper := float64(rules.CeilingThresholdCents-taxableIncome) / float64(rules.CeilingThresholdCents-firstBracket)
+	return rules.CeilingLowerCents + money.Cents(float64(rules.CeilingUpperCents-rules.CeilingLowerCents)*taper), true
+}
+
+func capped(expense money.Cents, rate float64, limit money.Cents) money.Cents {
+	deduction := money.Cents(float64(expense) * rate)
+	if deduction > limit {
+		return limit
+	}
+	return deduction
+}
@@ -0,0 +1,238 @@
+package tax
+
+import "github.com/joao-moreira/doctor-tracker/pkg/money"
+
+// SpainEngine implements the Engine interface for Spanish autónomos: IRPF on
+// the activity's net income under direct estimation, professional
+// withholding (retenciones) and the RETA quota. The simplified regime is the
+// simplified direct estimate and the organized one the normal estimate.
+// IRS Jovem and IFICI are Portuguese and ignored here.
+type SpainEngine struct{}
+
+func NewSpainEngine() *SpainEngine {
+	return &SpainEngine{}
+}
+
+// netIncome traces gross income to the taxable net income of the activity
+// (rendimiento neto): gross less deductible expenses and RETA contributions
+// and, under the simplified estimate, a share of what is left for expenses
+// hard to justify. A loss is taxed as zero.
+func (e *SpainEngine) netIncome(config YearConfig, income Income) (deductions, taxable money.Cents) {
+	contributions := AnnualContributions(e, config, income)
+	deductions = income.DeductibleExpenses + contributions
+
+	net := income.Gross - deductions
+	if !income.Organized() && net > 0 {
+		flat := applyRate(net, config.FlatExpenseRate)
+		if flat > config.FlatExpenseCapCents {
+			flat = config.FlatExpenseCapCents
+		}
+		deductions += flat
+		net -= flat
+	}
+	if net < 0 {
+		net = 0
+	}
+	return deductions, net
+}
+
+// employmentIncome is the net income from employment: gross less the
+// employee's contributions and the other expenses of employment, never
+// below zero. The reductions for low earnings are not modelled.
+func (e *SpainEngine) employmentIncome(config YearConfig, employment Employment) money.Cents {
+	if employment.Gross == 0 {
+		return 0
+	}
+	return max(employment.Gross-employment.SocialSecurity-config.SpecificDeductionCents, 0)
+}
+
+// CalculateIRS applies the IRPF scale to the net income of the activity and
+// of any employment together, and takes off the scale applied to the mínimo
+// personal, the part of the income taxed at zero.
+func (e *SpainEngine) CalculateIRS(config YearConfig, income Income) IRSResult {
+	_, activity := e.netIncome(config, income)
+	employment := e.employmentIncome(config, income.Employment)
+	taxable := activity + employment
+
+	totalTax, breakdown := collectTax(config, taxable)
+	if allowance := min(taxable, config.PersonalAllowanceCents); allowance > 0 {
+		relief, _ := collectTax(config, allowance)
+		totalTax -= relief
+		breakdown = append(breakdown, BracketResult{
+			BracketLabel:     "Mínimo personal",
+			TaxableInBracket: allowance,
+			Rate:             config.Brackets[0].Rate,
+			TaxAmount:        -relief,
+		})
+	}
+
+	var effectiveRate float64
+	if taxable > 0 {
+		effectiveRate = float64(totalTax) / float64(taxable)
+	}
+
+	return IRSResult{
+		TaxableIncome:     taxable,
+		EmploymentTaxable: employment,
+		TotalTax:          totalTax,
+		EffectiveRate:     effectiveRate,
+		BracketBreakdown:  breakdown,
+	}
+}
+
+func (e *SpainEngine) CalculateSocialSecurity(config YearConfig, quarterlyGrossIncome money.Cents) SSResult {
+	return e.CalculateSSDeclaration(config, quarterlyGrossIncome, 0)
+}
+
+// CalculateSSDeclaration places a quarter's monthly net income in its RETA
+// band and pays on the band's minimum base, or on a base moved by the
+// adjustment within the band's limits. Net income is taken as gross less the
+// generic deduction, since the quarter's expenses are not known here.
+func (e *SpainEngine) CalculateSSDeclaration(config YearConfig, quarterlyGrossIncome money.Cents, baseAdjustment float64) SSResult {
+	relevantIncome := money.Cents(float64(quarterlyGrossIncome) * config.SSIncomeCoefficient)
+	monthlyIncome := relevantIncome / 3
+
+	var band SSBaseBand
+	for _, band = range config.SSBaseBands {
+		if monthlyIncome <= band.UpperLimit {
+			break
+		}
+	}
+
+	monthlyBase := band.MinBase
+	if baseAdjustment != 0 {
+		monthlyBase = applyRate(monthlyBase, 1+baseAdjustment)
+		monthlyBase = max(band.MinBase, min(monthlyBase, band.MaxBase))
+	}
+	monthlyContribution := applyRate(monthlyBase, config.SSRate)
+
+	return SSResult{
+		RelevantIncome:      relevantIncome,
+		BaseAdjustment:      baseAdjustment,
+		MonthlyBase:         monthlyBase,
+		MonthlyContribution: monthlyContribution,
+		QuarterlyPayment:    monthlyContribution * 3,
+		AnnualEstimate:      monthlyContribution * 12,
+	}
+}
+
+func (e *SpainEngine) CalculateWithholding(grossAmount money.Cents, rate float64) money.Cents {
+	return applyRate(grossAmount, rate)
+}
+
+// CalculateExpenseJustification reports no requirement: Spanish direct
+// estimation deducts the expenses themselves rather than asking for a share
+// of gross income to be justified.
+func (e *SpainEngine) CalculateExpenseJustification(config YearConfig, income Income) JustificationResult {
+	return JustificationResult{Justified: income.JustifiedExpenses}
+}
+
+func (e *SpainEngine) CalculateAnnualSummary(config YearConfig, income Income) AnnualSummary {
+	irsResult := e.CalculateIRS(config, income)
+	contributions := AnnualContributions(e, config, income)
+
+	// Spain has no dispensation from withholding
+	rate := WithholdingRate(config, Payer{}, false)
+	withholdingTotal := e.CalculateWithholding(income.Gross, rate) + income.Employment.Withholding
+
+	employment := income.Employment
+	netIncome := income.Gross + employment.Gross - irsResult.TotalTax - contributions - employment.SocialSecurity
+
+	return AnnualSummary{
+		GrossIncome:      income.Gross + employment.Gross,
+		EmploymentIncome: employment.Gross,
+		TaxableIncome:    irsResult.TaxableIncome,
+		IRSAmount:        irsResult.TotalTax,
+		IRSEffectiveRate: irsResult.EffectiveRate,
+		SSAnnual:         contributions,
+		EmploymentSS:     employment.SocialSecurity,
+		WithholdingTotal: withholdingTotal,
+		NetIncome:        netIncome,
+		MonthlyNet:       netIncome / 12,
+	}
+}
+
+// SimulateSettlement assesses the year's IRPF (declaración de la renta) for
+// the taxpayer filing alone. Joint filing and the regional deductions are not
+// modelled, so the household and personal expenses are ignored; AnexoB holds
+// the activity's figures as they are declared on the return and AnexoA those
+// of any employment.
+func (e *SpainEngine) SimulateSettlement(config YearConfig, input SettlementInput) Settlement {
+	deductions, taxable := e.netIncome(config, input.Income)
+	irs := e.CalculateIRS(config, input.Income)
+	employment := input.Income.Employment
+
+	settlement := Settlement{
+		FiscalYear: config.FiscalYear,
+		AnexoB: AnexoB{
+			ProfessionalServices: input.Income.Gross,
+			DeductibleExpenses:   deductions,
+			TaxableIncome:        taxable,
+			Withholding:          input.Withholding,
+		},
+		TaxableIncome:     irs.TaxableIncome,
+		Quotient:          1,
+		CollectedTax:      irs.TotalTax,
+		BracketBreakdown:  irs.BracketBreakdown,
+		NetTax:            irs.TotalTax,
+		Withholding:       input.Withholding + employment.Withholding,
+		PaymentsOnAccount: input.PaymentsOnAccount,
+	}
+	if employment.Gross > 0 {
+		settlement.AnexoA = &AnexoA{
+			Gross:             employment.Gross,
+			Withholding:       employment.Withholding,
+			SocialSecurity:    employment.SocialSecurity,
+			SpecificDeduction: employment.Gross - employment.SocialSecurity - irs.EmploymentTaxable,
+			TaxableIncome:     irs.EmploymentTaxable,
+		}
+	}
+
+	settlement.Balance = settlement.NetTax - settlement.Withholding - settlement.PaymentsOnAccount
+	if settlement.Balance > 0 {
+		settlement.AmountDue = settlement.Balance
+	} else {
+		settlement.Refund = -settlement.Balance
+	}
+	return settlement
+}
+
+// CompareRegimes sets the simplified direct estimate against the normal one.
+// Both deduct the same expenses, so the normal estimate never costs less; it
+// becomes compulsory once turnover passes 600,000 EUR.
+func (e *SpainEngine) CompareRegimes(config YearConfig, income Income) RegimeComparison {
+	simplified, organized := income, income
+	simplified.Regime = RegimeSimplified
+	organized.Regime = RegimeOrganized
+
+	comparison := RegimeComparison{
+		FiscalYear:  config.FiscalYear,
+		GrossIncome: income.Gross,
+		Simplified:  e.regimeResult(config, simplified),
+		Organized:   e.regimeResult(config, organized),
+		Recommended: RegimeSimplified,
+	}
+
+	comparison.Savings = comparison.Simplified.TotalTax - comparison.Organized.TotalTax
+	if comparison.Savings > 0 {
+		comparison.Recommended = RegimeOrganized
+	} else {
+		comparison.Savings = -comparison.Savings
+	}
+	return comparison
+}
+
+func (e *SpainEngine) regimeResult(config YearConfig, income Income) RegimeResult {
+	summary := e.CalculateAnnualSummary(config, income)
+	deductions, _ := e.netIncome(config, income)
+
+	return RegimeResult{
+		Regime:        income.Regime,
+		TaxableIncome: summary.TaxableIncome,
+		Deductions:    deductions,
+		IRSAmount:     summary.IRSAmount,
+		SSAnnual:      summary.SSAnnual,
+		TotalTax:      summary.IRSAmount + summary.SSAnnual,
+		NetIncome:     summary.NetIncome,
+	}
+}
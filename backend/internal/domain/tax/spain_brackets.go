@@ -0,0 +1,75 @@
+package tax
+
+import "github.com/joao-moreira/doctor-tracker/pkg/money"
+
+// Spain2025Config returns the Spanish configuration for fiscal year 2025.
+// The IRPF scale is the state scale together with the matching regional
+// one, as applied when a region has not set its own.
+func Spain2025Config() YearConfig {
+	return YearConfig{
+		Country:    "ES",
+		FiscalYear: 2025,
+		Brackets: []IRSBracket{
+			{LowerLimit: 0, UpperLimit: money.FromEuros(12450), Rate: 0.19, Deduction: 0},
+			{LowerLimit: money.FromEuros(12450), UpperLimit: money.FromEuros(20200), Rate: 0.24, Deduction: money.FromEuros(622.50)},
+			{LowerLimit: money.FromEuros(20200), UpperLimit: money.FromEuros(35200), Rate: 0.30, Deduction: money.FromEuros(1834.50)},
+			{LowerLimit: money.FromEuros(35200), UpperLimit: money.FromEuros(60000), Rate: 0.37, Deduction: money.FromEuros(4298.50)},
+			{LowerLimit: money.FromEuros(60000), UpperLimit: money.FromEuros(300000), Rate: 0.45, Deduction: money.FromEuros(9098.50)},
+			{LowerLimit: money.FromEuros(300000), UpperLimit: money.Cents(999999999999), Rate: 0.47, Deduction: money.FromEuros(15098.50)},
+		},
+		SSRate:                 0.314, // including the 0.8% intergenerational equity surcharge
+		SSIncomeCoefficient:    0.93,  // net income less the 7% generic deduction
+		DefaultWithholdingRate: 0.15,  // 7% in the first three years of activity
+
+		PersonalAllowanceCents: money.FromEuros(5550),
+		FlatExpenseRate:        0.05,
+		FlatExpenseCapCents:    money.FromEuros(2000),
+		SpecificDeductionCents: money.FromEuros(2000),
+		SSBaseBands:            retaBands2025(),
+	}
+}
+
+// Spain2026Config returns the Spanish configuration for fiscal year 2026.
+// RETA keeps the 2025 bands until the 2026 order sets new ones.
+func Spain2026Config() YearConfig {
+	config := Spain2025Config()
+	config.FiscalYear = 2026
+	config.SSRate = 0.315
+	return config
+}
+
+// retaBands2025 returns the RETA contribution bases by monthly net income
+// (Orden PJC/178/2025), the reduced table followed by the general one.
+func retaBands2025() []SSBaseBand {
+	return []SSBaseBand{
+		{UpperLimit: money.FromEuros(670), MinBase: money.FromEuros(653.59), MaxBase: money.FromEuros(718.94)},
+		{UpperLimit: money.FromEuros(900), MinBase: money.FromEuros(718.95), MaxBase: money.FromEuros(900)},
+		{UpperLimit: money.FromEuros(1166.70), MinBase: money.FromEuros(849.67), MaxBase: money.FromEuros(1166.70)},
+		{UpperLimit: money.FromEuros(1300), MinBase: money.FromEuros(950.98), MaxBase: money.FromEuros(1300)},
+		{UpperLimit: money.FromEuros(1500), MinBase: money.FromEuros(960.78), MaxBase: money.FromEuros(1500)},
+		{UpperLimit: money.FromEuros(1700), MinBase: money.FromEuros(960.78), MaxBase: money.FromEuros(1700)},
+		{UpperLimit: money.FromEuros(1850), MinBase: money.FromEuros(1143.79), MaxBase: money.FromEuros(1850)},
+		{UpperLimit: money.FromEuros(2030), MinBase: money.FromEuros(1209.15), MaxBase: money.FromEuros(2030)},
+		{UpperLimit: money.FromEuros(2330), MinBase: money.FromEuros(1274.51), MaxBase: money.FromEuros(2330)},
+		{UpperLimit: money.FromEuros(2760), MinBase: money.FromEuros(1356.21), MaxBase: money.FromEuros(2760)},
+		{UpperLimit: money.FromEuros(3190), MinBase: money.FromEuros(1437.91), MaxBase: money.FromEuros(3190)},
+		{UpperLimit: money.FromEuros(3620), MinBase: money.FromEuros(1519.61), MaxBase: money.FromEuros(3620)},
+		{UpperLimit: money.FromEuros(4050), MinBase: money.FromEuros(1601.31), MaxBase: money.FromEuros(4050)},
+		{UpperLimit: money.FromEuros(6000), MinBase: money.FromEuros(1732.03), MaxBase: money.FromEuros(4909.50)},
+		{UpperLimit: money.Cents(999999999999), MinBase: money.FromEuros(1928.10), MaxBase: money.FromEuros(4909.50)},
+	}
+}
+
+// SpainConfigForYear returns the Spanish configuration for a fiscal year,
+// falling back to the most recent known year.
+func SpainConfigForYear(year int) YearConfig {
+	switch year {
+	case 2025:
+		return Spain2025Config()
+	case 2026:
+		return Spain2026Config()
+	}
+	config := Spain2026Config()
+	config.FiscalYear = year
+	return config
+}
@@ -0,0 +1,71 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/joao-moreira/doctor-tracker/pkg/money"
+)
+
+func TestSpainEngine_CalculateIRS(t *testing.T) {
+	engine := NewSpainEngine()
+	config := Spain2025Config()
+
+	tests := []struct {
+		name   string
+		income Income
+		want   float64
+	}{
+		// 60,000 less 5,000 of expenses, 12 x 543.86 of RETA in the top
+		// band but one and the 2,000 EUR cap on the flat deduction leave
+		// 46,473.68; the scale gives 12,896.76 and the mínimo personal
+		// takes off 1,054.50
+		{"simplified estimate", Income{Gross: money.FromEuros(60000), DeductibleExpenses: money.FromEuros(5000)}, 11842.26},
+		// 8,000 less 12 x 205.23 of RETA stays under the mínimo personal
+		{"under the mínimo personal", Income{Gross: money.FromEuros(8000), Regime: RegimeOrganized}, 0},
+		// The 5,537.24 above plus a 30,000 EUR salary less 1,905 EUR of
+		// contributions and 2,000 EUR of other expenses of employment: the
+		// scale on 31,632.24 gives 7,655.17, less the 1,054.50 of the mínimo
+		// personal
+		{"with a salary", Income{
+			Gross:      money.FromEuros(8000),
+			Regime:     RegimeOrganized,
+			Employment: Employment{Gross: money.FromEuros(30000), SocialSecurity: money.FromEuros(1905)},
+		}, 6600.67},
+	}
+	for _, tt := range tests {
+		got := engine.CalculateIRS(config, tt.income)
+		if want := money.FromEuros(tt.want); got.TotalTax != want {
+			t.Errorf("%s: expected %d, got %d", tt.name, want, got.TotalTax)
+		}
+	}
+}
+
+func TestSpainEngine_CalculateSSDeclaration(t *testing.T) {
+	engine := NewSpainEngine()
+	config := Spain2025Config()
+
+	// 4,650 EUR a month of net income falls in the 4,050-6,000 band
+	got := engine.CalculateSSDeclaration(config, money.FromEuros(15000), 0)
+	if got.MonthlyBase != money.FromEuros(1732.03) || got.MonthlyContribution != money.FromEuros(543.86) {
+		t.Errorf("expected 543.86 EUR on the band's minimum base, got %d on %d", got.MonthlyContribution, got.MonthlyBase)
+	}
+
+	// A higher base is chosen within the band
+	if got := engine.CalculateSSDeclaration(config, money.FromEuros(15000), 0.25); got.MonthlyBase != money.FromEuros(2165.04) {
+		t.Errorf("expected a 2165.04 EUR base, got %d", got.MonthlyBase)
+	}
+}
+
+func TestRegistry_Resolve(t *testing.T) {
+	registry := DefaultRegistry()
+
+	if j := registry.Resolve("ES"); j.Code != "ES" || j.Config(2025).Country != "ES" {
+		t.Errorf("expected Spain, got %s", j.Code)
+	}
+	if j := registry.Resolve(""); j.Code != "PT" {
+		t.Errorf("expected Portugal by default, got %s", j.Code)
+	}
+	if _, ok := registry.Lookup("FR"); ok {
+		t.Error("expected no engine for France")
+	}
+}
@@ -0,0 +1,33 @@
+package tax
+
+import "github.com/joao-moreira/doctor-tracker/pkg/money"
+
+// Payer is the client an invoice is issued to.
+type Payer struct {
+	// Private individuals do not withhold; only entities with organized
+	// accounting do (art. 101(1) CIRS).
+	Private bool
+	// Rate agreed with the client, which may be 0; nil applies the year's
+	// default.
+	Rate *float64
+}
+
+// WithholdingRate is the rate a payer withholds on a payment. It is the one
+// place the rules are applied, so invoices, forecasts and estimates agree.
+func WithholdingRate(config YearConfig, payer Payer, dispensed bool) float64 {
+	if payer.Private || dispensed {
+		return 0
+	}
+	if payer.Rate != nil {
+		return *payer.Rate
+	}
+	return config.DefaultWithholdingRate
+}
+
+// Dispensed reports whether a taxpayer who opted for the art. 101-B
+// dispensation is still covered once incomeToDate has been earned in the
+// year, counting the payment in question. The payment that takes the year
+// past the threshold is withheld, as is every one after it.
+func Dispensed(config YearConfig, optedIn bool, incomeToDate money.Cents) bool {
+	return optedIn && incomeToDate <= config.WithholdingDispensationCents
+}
@@ -31,6 +31,9 @@ var Weekdays = []DayOfWeek{Monday, Tuesday, Wednesday, Thursday, Friday}
 var Weekend = []DayOfWeek{Saturday, Sunday}
 var AllDays = []DayOfWeek{Monday, Tuesday, Wednesday, Thursday, Friday, Saturday, Sunday}
 
+// DefaultPaymentTermsDays is used when a workplace does not state its payment terms.
+const DefaultPaymentTermsDays = 30
+
 type Workplace struct {
 	ID       uuid.UUID `json:"id"`
 	UserID   uuid.UUID `json:"user_id"`
@@ -45,8 +48,13 @@ type Workplace struct {
 	MonthlyExpectedHours *float64 `json:"monthly_expected_hours,omitempty"`
 	HasConsultationPay   bool     `json:"has_consultation_pay"`
 	HasOutsideVisitPay   bool     `json:"has_outside_visit_pay"`
-	WithholdingRate      float64  `json:"withholding_rate"`
+	WithholdingRate      *float64 `json:"withholding_rate"`      // Unset applies the fiscal year's default rate
+	PrivateClient        bool     `json:"private_client"`        // Private individuals do not withhold IRS
+	PaymentTermsDays     int      `json:"payment_terms_days"`    // Days after issue until an invoice is due
+	CommuteMinutes       int      `json:"commute_minutes"`       // One-way travel time from home
+	TaxCountry           *string  `json:"tax_country,omitempty"` // Where its income is sourced, when not the user's tax residence
 
+	NIF          *string `json:"nif,omitempty"` // Client tax number, used to match bank transfers
 	ContactName  *string `json:"contact_name,omitempty"`
 	ContactPhone *string `json:"contact_phone,omitempty"`
 	ContactEmail *string `json:"contact_email,omitempty"`
@@ -89,7 +97,12 @@ type CreateWorkplaceInput struct {
 	MonthlyExpectedHours *float64 `json:"monthly_expected_hours"`
 	HasConsultationPay   *bool    `json:"has_consultation_pay"`
 	HasOutsideVisitPay   *bool    `json:"has_outside_visit_pay"`
-	WithholdingRate      *float64 `json:"withholding_rate"`
+	WithholdingRate      *float64 `json:"withholding_rate" validate:"omitempty,min=0,max=1"`
+	PrivateClient        *bool    `json:"private_client"`
+	NIF                  *string  `json:"nif" validate:"omitempty,len=9,numeric"`
+	PaymentTermsDays     *int     `json:"payment_terms_days" validate:"omitempty,min=0"`
+	CommuteMinutes       *int     `json:"commute_minutes" validate:"omitempty,min=0"`
+	TaxCountry           *string  `json:"tax_country" validate:"omitempty,oneof=PT ES"`
 	ContactName          *string  `json:"contact_name"`
 	ContactPhone         *string  `json:"contact_phone"`
 	ContactEmail         *string  `json:"contact_email"`
@@ -105,7 +118,13 @@ type UpdateWorkplaceInput struct {
 	MonthlyExpectedHours *float64  `json:"monthly_expected_hours"`
 	HasConsultationPay   *bool     `json:"has_consultation_pay"`
 	HasOutsideVisitPay   *bool     `json:"has_outside_visit_pay"`
-	WithholdingRate      *float64  `json:"withholding_rate"`
+	WithholdingRate      *float64  `json:"withholding_rate" validate:"omitempty,min=0,max=1"`
+	ClearWithholdingRate bool      `json:"clear_withholding_rate"` // Goes back to the fiscal year's default rate
+	PrivateClient        *bool     `json:"private_client"`
+	NIF                  *string   `json:"nif" validate:"omitempty,len=9,numeric"`
+	PaymentTermsDays     *int      `json:"payment_terms_days" validate:"omitempty,min=0"`
+	CommuteMinutes       *int      `json:"commute_minutes" validate:"omitempty,min=0"`
+	TaxCountry           *string   `json:"tax_country" validate:"omitempty,oneof=PT ES"` // empty clears
 	ContactName          *string   `json:"contact_name"`
 	ContactPhone         *string   `json:"contact_phone"`
 	ContactEmail         *string   `json:"contact_email"`
@@ -4,17 +4,34 @@ import (
 	"sort"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/joao-moreira/doctor-tracker/pkg/money"
 )
 
-// EarningSegment represents a time segment within a shift with its calculated earnings.
+type EarningKind string
+
+const (
+	EarningKindTime         EarningKind = "time"
+	EarningKindConsultation EarningKind = "consultation"
+	EarningKindOutsideVisit EarningKind = "outside_visit"
+)
+
+// EarningSegment represents a time segment within a shift with its calculated earnings,
+// or an add-on line item (consultations, outside visits) spanning the whole shift.
+// Add-ons carry no hours; Quantity is the number of patients or visits paid.
 type EarningSegment struct {
-	Start    time.Time    `json:"start"`
-	End      time.Time    `json:"end"`
-	Hours    float64      `json:"hours"`
-	Rate     money.Cents  `json:"rate_cents"`
-	Amount   money.Cents  `json:"amount_cents"`
-	RuleName string       `json:"rule_name,omitempty"`
+	Kind     EarningKind `json:"kind"`
+	Start    time.Time   `json:"start"`
+	End      time.Time   `json:"end"`
+	Hours    float64     `json:"hours"`
+	Rate     money.Cents `json:"rate_cents"`
+	Amount   money.Cents `json:"amount_cents"`
+	Quantity int         `json:"quantity,omitempty"`
+
+	// The pricing rule that set the rate, if any, and how it did
+	PricingRuleID  *uuid.UUID `json:"pricing_rule_id,omitempty"`
+	RuleName       string     `json:"rule_name,omitempty"`
+	RateMultiplier *float64   `json:"rate_multiplier,omitempty"`
 }
 
 // ResolveShiftEarnings calculates earnings for a shift based on the workplace's pricing rules.
@@ -45,6 +62,7 @@ func ResolveShiftEarnings(shiftStart, shiftEnd time.Time, wp *Workplace, rules [
 	}
 
 	var earnings []EarningSegment
+	consultations := make(map[*PricingRule]float64) // matched rule -> share of the shift
 	for _, seg := range segments {
 		rate, ruleName, matchedRule := resolveRateWithRule(seg.Start, wp, sortedRules)
 		hours := seg.End.Sub(seg.Start).Hours()
@@ -65,34 +83,63 @@ func ResolveShiftEarnings(shiftStart, shiftEnd time.Time, wp *Workplace, rules [
 			}
 		}
 
-		// Add consultation pay if enabled
-		if wp.HasConsultationPay && patientsSeen > 0 && totalHours > 0 {
-			consultRate := resolveConsultationRate(matchedRule)
-			if consultRate > 0 {
-				// Distribute consultation earnings proportionally across segments
-				consultAmount := money.Cents(float64(consultRate) * float64(patientsSeen) * hours / totalHours)
-				amount += consultAmount
-			}
-		}
-
-		// Add outside visit pay if enabled (flat total distributed proportionally)
-		if outsideVisitTotal > 0 && totalHours > 0 {
-			amount += money.Cents(float64(outsideVisitTotal) * hours / totalHours)
+		if totalHours > 0 && resolveConsultationRate(matchedRule) > 0 {
+			consultations[matchedRule] += hours / totalHours
 		}
 
-		earnings = append(earnings, EarningSegment{
+		segment := EarningSegment{
+			Kind:     EarningKindTime,
 			Start:    seg.Start,
 			End:      seg.End,
 			Hours:    hours,
 			Rate:     rate,
 			Amount:   amount,
 			RuleName: ruleName,
-		})
+		}
+		if matchedRule != nil {
+			segment.PricingRuleID = &matchedRule.ID
+			segment.RateMultiplier = matchedRule.RateMultiplier
+		}
+		earnings = append(earnings, segment)
+	}
+
+	// Consultation pay, one line per rule whose rate applied, weighted by the
+	// share of the shift that rule covered
+	if wp.HasConsultationPay && patientsSeen > 0 {
+		for _, rule := range sortedRules {
+			share, ok := consultations[rule]
+			if !ok {
+				continue
+			}
+			consultRate := resolveConsultationRate(rule)
+			earnings = append(earnings, addOnLine(EarningKindConsultation, shiftStart, shiftEnd, rule,
+				consultRate, patientsSeen, money.Cents(float64(consultRate)*float64(patientsSeen)*share)))
+		}
+	}
+
+	// Outside visit pay is flat for the shift
+	if outsideVisitTotal > 0 {
+		rule := resolveOutsideVisitRuleForShift(sortedRules)
+		earnings = append(earnings, addOnLine(EarningKindOutsideVisit, shiftStart, shiftEnd, rule,
+			*rule.OutsideVisitRateCents, outsideVisits, outsideVisitTotal))
 	}
 
 	return earnings
 }
 
+func addOnLine(kind EarningKind, start, end time.Time, rule *PricingRule, rate money.Cents, quantity int, amount money.Cents) EarningSegment {
+	return EarningSegment{
+		Kind:          kind,
+		Start:         start,
+		End:           end,
+		Rate:          rate,
+		Amount:        amount,
+		Quantity:      quantity,
+		PricingRuleID: &rule.ID,
+		RuleName:      rule.Name,
+	}
+}
+
 // resolveConsultationRate returns the consultation rate from a matched pricing rule, or 0 if not set.
 func resolveConsultationRate(rule *PricingRule) money.Cents {
 	if rule != nil && rule.ConsultationRateCents != nil {
@@ -104,15 +151,23 @@ func resolveConsultationRate(rule *PricingRule) money.Cents {
 // resolveOutsideVisitRateForShift picks the outside visit rate from the first active rule
 // (highest priority) that has it set. Returns 0 if no rule defines it.
 func resolveOutsideVisitRateForShift(rules []*PricingRule) money.Cents {
+	if rule := resolveOutsideVisitRuleForShift(rules); rule != nil {
+		return *rule.OutsideVisitRateCents
+	}
+	return 0
+}
+
+// resolveOutsideVisitRuleForShift returns the rule resolveOutsideVisitRateForShift takes the rate from.
+func resolveOutsideVisitRuleForShift(rules []*PricingRule) *PricingRule {
 	for _, rule := range rules {
 		if !rule.IsActive {
 			continue
 		}
 		if rule.OutsideVisitRateCents != nil {
-			return *rule.OutsideVisitRateCents
+			return rule
 		}
 	}
-	return 0
+	return nil
 }
 
 // TotalEarnings sums up all earning segments.
@@ -242,7 +297,7 @@ func ruleMatchesTime(rule *PricingRule, t time.Time) bool {
 
 	// Check day of week
 	if len(rule.DaysOfWeek) > 0 {
-		dow := timeToDayOfWeek(t)
+		dow := DayOfWeekOf(t)
 		matched := false
 		for _, d := range rule.DaysOfWeek {
 			if d == dow {
@@ -275,7 +330,8 @@ func matchTimeWindow(rule *PricingRule, t time.Time) bool {
 	return clock >= start || clock < end
 }
 
-func timeToDayOfWeek(t time.Time) DayOfWeek {
+// DayOfWeekOf is the day of the week t falls on, as pricing rules name it.
+func DayOfWeekOf(t time.Time) DayOfWeek {
 	switch t.Weekday() {
 	case time.Monday:
 		return Monday
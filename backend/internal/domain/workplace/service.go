@@ -33,9 +33,21 @@ func (s *Service) CreateWorkplace(ctx context.Context, userID uuid.UUID, input C
 	if input.HasOutsideVisitPay != nil {
 		hasOutsideVisitPay = *input.HasOutsideVisitPay
 	}
-	withholdingRate := 0.25
-	if input.WithholdingRate != nil {
-		withholdingRate = *input.WithholdingRate
+	privateClient := false
+	if input.PrivateClient != nil {
+		privateClient = *input.PrivateClient
+	}
+	paymentTermsDays := DefaultPaymentTermsDays
+	if input.PaymentTermsDays != nil {
+		paymentTermsDays = *input.PaymentTermsDays
+	}
+	commuteMinutes := 0
+	if input.CommuteMinutes != nil {
+		commuteMinutes = *input.CommuteMinutes
+	}
+	var taxCountry *string
+	if input.TaxCountry != nil && *input.TaxCountry != "" {
+		taxCountry = input.TaxCountry
 	}
 
 	w := &Workplace{
@@ -50,7 +62,12 @@ func (s *Service) CreateWorkplace(ctx context.Context, userID uuid.UUID, input C
 		MonthlyExpectedHours: input.MonthlyExpectedHours,
 		HasConsultationPay:   hasConsultationPay,
 		HasOutsideVisitPay:   hasOutsideVisitPay,
-		WithholdingRate:      withholdingRate,
+		WithholdingRate:      input.WithholdingRate,
+		PrivateClient:        privateClient,
+		NIF:                  input.NIF,
+		PaymentTermsDays:     paymentTermsDays,
+		CommuteMinutes:       commuteMinutes,
+		TaxCountry:           taxCountry,
 		ContactName:          input.ContactName,
 		ContactPhone:         input.ContactPhone,
 		ContactEmail:         input.ContactEmail,
@@ -109,7 +126,28 @@ func (s *Service) UpdateWorkplace(ctx context.Context, id uuid.UUID, input Updat
 		w.HasOutsideVisitPay = *input.HasOutsideVisitPay
 	}
 	if input.WithholdingRate != nil {
-		w.WithholdingRate = *input.WithholdingRate
+		w.WithholdingRate = input.WithholdingRate
+	}
+	if input.ClearWithholdingRate {
+		w.WithholdingRate = nil
+	}
+	if input.PrivateClient != nil {
+		w.PrivateClient = *input.PrivateClient
+	}
+	if input.NIF != nil {
+		w.NIF = input.NIF
+	}
+	if input.PaymentTermsDays != nil {
+		w.PaymentTermsDays = *input.PaymentTermsDays
+	}
+	if input.CommuteMinutes != nil {
+		w.CommuteMinutes = *input.CommuteMinutes
+	}
+	if input.TaxCountry != nil {
+		w.TaxCountry = input.TaxCountry
+		if *input.TaxCountry == "" {
+			w.TaxCountry = nil
+		}
 	}
 	if input.ContactName != nil {
 		w.ContactName = input.ContactName
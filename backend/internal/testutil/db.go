@@ -3,7 +3,12 @@ package testutil
 import (
 	"context"
 	"fmt"
+	"math"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -11,7 +16,28 @@ import (
 	"github.com/testcontainers/testcontainers-go/wait"
 )
 
-func NewTestDB(ctx context.Context) (*pgxpool.Pool, func(), error) {
+func NewTestDB(ctx context.Context) (pool *pgxpool.Pool, cleanup func(), err error) {
+	pool, cleanup, err = NewEmptyTestDB(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := Migrate(ctx, pool, 1, math.MaxInt); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return pool, cleanup, nil
+}
+
+// NewEmptyTestDB starts a database without running any migrations, for tests
+// that check a migration against data written under the schema before it.
+func NewEmptyTestDB(ctx context.Context) (pool *pgxpool.Pool, cleanup func(), err error) {
+	// testcontainers panics when no Docker host can be found
+	defer func() {
+		if r := recover(); r != nil {
+			pool, cleanup, err = nil, nil, fmt.Errorf("starting container: %v", r)
+		}
+	}()
+
 	req := testcontainers.ContainerRequest{
 		Image:        "postgres:16-alpine",
 		ExposedPorts: []string{"5432/tcp"},
@@ -36,34 +62,47 @@ func NewTestDB(ctx context.Context) (*pgxpool.Pool, func(), error) {
 
 	dsn := fmt.Sprintf("postgres://test_user:test_pass@%s:%s/test_db?sslmode=disable", host, port.Port())
 
-	pool, err := pgxpool.New(ctx, dsn)
+	pool, err = pgxpool.New(ctx, dsn)
 	if err != nil {
 		container.Terminate(ctx)
 		return nil, nil, fmt.Errorf("connecting to db: %w", err)
 	}
 
-	// Run migrations
-	migrationSQL, err := os.ReadFile("../../db/migrations/000001_initial_schema.up.sql")
-	if err != nil {
-		// Try alternative path
-		migrationSQL, err = os.ReadFile("../../../db/migrations/000001_initial_schema.up.sql")
-		if err != nil {
-			pool.Close()
-			container.Terminate(ctx)
-			return nil, nil, fmt.Errorf("reading migration: %w", err)
-		}
-	}
-
-	if _, err := pool.Exec(ctx, string(migrationSQL)); err != nil {
+	cleanup = func() {
 		pool.Close()
 		container.Terminate(ctx)
-		return nil, nil, fmt.Errorf("running migration: %w", err)
 	}
 
-	cleanup := func() {
-		pool.Close()
-		container.Terminate(ctx)
+	return pool, cleanup, nil
+}
+
+// Migrate runs the up migrations numbered first to last, inclusive, in order.
+func Migrate(ctx context.Context, pool *pgxpool.Pool, first, last int) error {
+	migrations, err := filepath.Glob("../../db/migrations/*.up.sql")
+	if err == nil && len(migrations) == 0 {
+		// Try alternative path
+		migrations, err = filepath.Glob("../../../db/migrations/*.up.sql")
+	}
+	if err != nil || len(migrations) == 0 {
+		return fmt.Errorf("finding migrations: %v", err)
 	}
+	sort.Strings(migrations)
 
-	return pool, cleanup, nil
+	for _, path := range migrations {
+		version, err := strconv.Atoi(strings.SplitN(filepath.Base(path), "_", 2)[0])
+		if err != nil {
+			return fmt.Errorf("numbering migration %s: %w", filepath.Base(path), err)
+		}
+		if version < first || version > last {
+			continue
+		}
+		migrationSQL, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading migration %s: %w", filepath.Base(path), err)
+		}
+		if _, err := pool.Exec(ctx, string(migrationSQL)); err != nil {
+			return fmt.Errorf("running migration %s: %w", filepath.Base(path), err)
+		}
+	}
+	return nil
 }
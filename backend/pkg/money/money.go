@@ -1,13 +1,18 @@
 package money
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
 
 // Cents represents a monetary value in cents (integer) to avoid floating-point errors.
 // 1 EUR = 100 cents. Example: EUR 25.50 = 2550 cents.
 type Cents int64
 
+// FromEuros converts an amount in euros, rounded to the nearest cent so that
+// values such as 261.77, which float64 cannot hold exactly, convert exactly.
 func FromEuros(euros float64) Cents {
-	return Cents(euros * 100)
+	return Cents(math.Round(euros * 100))
 }
 
 func (c Cents) Euros() float64 {
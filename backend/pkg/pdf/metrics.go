@@ -0,0 +1,52 @@
+package pdf
+
+// Glyph widths of the printable ASCII characters, 0x20 to 0x7E, in
+// thousandths of the font size, from the Adobe font metrics.
+var asciiWidths = [2][95]int{
+	Helvetica: {
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	},
+	HelveticaBold: {
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	},
+}
+
+// accentBase is the unaccented letter each accented Latin-1 letter takes the
+// width of; the metrics give accented letters the width of their base.
+var accentBase = map[byte]byte{
+	0xC0: 'A', 0xC1: 'A', 0xC2: 'A', 0xC3: 'A', 0xC4: 'A', 0xC5: 'A', 0xC7: 'C',
+	0xC8: 'E', 0xC9: 'E', 0xCA: 'E', 0xCB: 'E', 0xCC: 'I', 0xCD: 'I', 0xCE: 'I', 0xCF: 'I',
+	0xD1: 'N', 0xD2: 'O', 0xD3: 'O', 0xD4: 'O', 0xD5: 'O', 0xD6: 'O', 0xD9: 'U', 0xDA: 'U',
+	0xDB: 'U', 0xDC: 'U', 0xDD: 'Y',
+	0xE0: 'a', 0xE1: 'a', 0xE2: 'a', 0xE3: 'a', 0xE4: 'a', 0xE5: 'a', 0xE7: 'c',
+	0xE8: 'e', 0xE9: 'e', 0xEA: 'e', 0xEB: 'e', 0xEC: 'i', 0xED: 'i', 0xEE: 'i', 0xEF: 'i',
+	0xF1: 'n', 0xF2: 'o', 0xF3: 'o', 0xF4: 'o', 0xF5: 'o', 0xF6: 'o', 0xF9: 'u', 0xFA: 'u',
+	0xFB: 'u', 0xFC: 'u', 0xFD: 'y', 0xFF: 'y',
+}
+
+// glyphWidth is the width of a WinAnsiEncoding character. Characters beyond
+// ASCII and the accented letters are counted as wide as a digit.
+func glyphWidth(font Font, b byte) int {
+	if base, ok := accentBase[b]; ok {
+		b = base
+	}
+	switch {
+	case b >= 0x20 && b <= 0x7E:
+		return asciiWidths[font][b-0x20]
+	case b == 0x85: // ellipsis
+		return 1000
+	case b == 0xA0: // no-break space
+		return 278
+	}
+	return 556
+}
@@ -0,0 +1,233 @@
+// Package pdf writes simple PDF 1.4 documents: text in the standard
+// Helvetica fonts, lines and shaded boxes. The fonts are the base fonts every
+// viewer provides, so nothing is embedded, and text is encoded as
+// WinAnsiEncoding, which covers Portuguese and Spanish.
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// A4 page sizes in points.
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+)
+
+var baseFonts = []string{"Helvetica", "Helvetica-Bold"}
+
+// Document is a PDF being built a page at a time.
+type Document struct {
+	Title   string
+	Created time.Time
+
+	width, height float64
+	pages         []*Page
+}
+
+// Page holds the content stream of one page. The origin is the bottom left
+// corner and y grows upwards.
+type Page struct {
+	content bytes.Buffer
+}
+
+// New starts a document whose pages are width by height points.
+func New(title string, width, height float64) *Document {
+	return &Document{Title: title, Created: time.Now(), width: width, height: height}
+}
+
+// Size returns the page width and height.
+func (d *Document) Size() (float64, float64) {
+	return d.width, d.height
+}
+
+// AddPage appends an empty page and returns it.
+func (d *Document) AddPage() *Page {
+	p := &Page{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Pages returns how many pages the document has.
+func (d *Document) Pages() int {
+	return len(d.pages)
+}
+
+// Text draws s with its baseline starting at (x, y).
+func (p *Page) Text(x, y float64, font Font, size float64, s string) {
+	fmt.Fprintf(&p.content, "BT /F%d %s Tf %s %s Td (%s) Tj ET\n", font+1, num(size), num(x), num(y), escape(encode(s)))
+}
+
+// TextRight draws s so that it ends at x.
+func (p *Page) TextRight(x, y float64, font Font, size float64, s string) {
+	p.Text(x-TextWidth(font, size, s), y, font, size, s)
+}
+
+// Line draws a straight line width points thick.
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n", num(width), num(x1), num(y1), num(x2), num(y2))
+}
+
+// FillRect fills a rectangle in a shade of grey, from 0 (black) to 1
+// (white), and resets the colour for text drawn after it.
+func (p *Page) FillRect(x, y, width, height, gray float64) {
+	fmt.Fprintf(&p.content, "%s g %s %s %s %s re f 0 g\n", num(gray), num(x), num(y), num(width), num(height))
+}
+
+// TextWidth is how wide s is drawn in font at size, in points.
+func TextWidth(font Font, size float64, s string) float64 {
+	var units int
+	for _, b := range encode(s) {
+		units += glyphWidth(font, b)
+	}
+	return float64(units) * size / 1000
+}
+
+// Truncate shortens s with an ellipsis until it fits in width.
+func Truncate(font Font, size, width float64, s string) string {
+	if TextWidth(font, size, s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		if t := strings.TrimRight(string(runes), " ") + "…"; TextWidth(font, size, t) <= width {
+			return t
+		}
+	}
+	return ""
+}
+
+// Render writes the document. Objects 1 and 2 are the catalog and page tree,
+// the fonts follow, then each page and its compressed content stream, and the
+// document information last.
+func (d *Document) Render(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	out := &countingWriter{w: bw}
+	var offsets []int64
+
+	object := func(body string) {
+		offsets = append(offsets, out.n)
+		fmt.Fprintf(out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	pages := d.pages
+	if len(pages) == 0 {
+		pages = []*Page{{}}
+	}
+	firstPage := 3 + len(baseFonts)
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+
+	var fonts []string
+	for i, name := range baseFonts {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+		fonts = append(fonts, fmt.Sprintf("/F%d %d 0 R", i+1, 3+i))
+	}
+
+	for i, p := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+			num(d.width), num(d.height), strings.Join(fonts, " "), firstPage+2*i+1))
+
+		var stream bytes.Buffer
+		zw := zlib.NewWriter(&stream)
+		zw.Write(p.content.Bytes())
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", stream.Len(), stream.Bytes()))
+	}
+
+	object(fmt.Sprintf("<< /Title (%s) /Producer (doctor-tracker) /CreationDate (D:%s) >>",
+		escape(encode(d.Title)), d.Created.UTC().Format("20060102150405Z")))
+
+	xref := out.n
+	fmt.Fprintf(out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(out, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, len(offsets), xref)
+
+	if out.err != nil {
+		return out.err
+	}
+	return bw.Flush()
+}
+
+// countingWriter tracks the offset of each object for the cross-reference
+// table and keeps the first write error.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
+
+func (c *countingWriter) WriteString(s string) {
+	c.Write([]byte(s))
+}
+
+// num formats a coordinate with at most two decimals.
+func num(f float64) string {
+	s := strings.TrimRight(fmt.Sprintf("%.2f", f), "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// escape escapes a literal string's delimiters.
+func escape(b []byte) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`, "\r", `\r`, "\n", `\n`).Replace(string(b))
+}
+
+// winAnsi maps the characters WinAnsiEncoding places in 0x80-0x9F.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// encode converts s to WinAnsiEncoding, replacing characters it lacks
+// with '?'.
+func encode(s string) []byte {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			b = append(b, byte(r))
+		case winAnsi[r] != 0:
+			b = append(b, winAnsi[r])
+		default:
+			b = append(b, '?')
+		}
+	}
+	return b
+}
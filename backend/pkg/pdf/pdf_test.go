@@ -0,0 +1,90 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDocument_Render(t *testing.T) {
+	doc := New("Relatório (2025)", A4Width, A4Height)
+	doc.Created = time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	first := doc.AddPage()
+	first.Text(36, 800, HelveticaBold, 12, "Declaração (Anexo B) – 1.234,56 €")
+	first.Line(36, 790, 300, 790, 0.5)
+	doc.AddPage().FillRect(36, 700, 100, 12, 0.9)
+
+	var buf bytes.Buffer
+	if err := doc.Render(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.Bytes()
+
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Fatal("expected a PDF header and trailer")
+	}
+	if !bytes.Contains(out, []byte("/Count 2")) {
+		t.Error("expected two pages in the page tree")
+	}
+	if !bytes.Contains(out, []byte(`/Title (Relat`+"\xf3"+`rio \(2025\))`)) {
+		t.Error("expected the title encoded and escaped")
+	}
+
+	// Every cross-reference entry points at its object, and startxref at
+	// the table
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	if m == nil {
+		t.Fatal("missing startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(out[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the table", xref)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1)
+	if len(entries) != 9 {
+		t.Fatalf("expected catalog, pages, two fonts, two pages with contents and info, got %d entries", len(entries))
+	}
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !bytes.HasPrefix(out[off:], []byte(want)) {
+			t.Errorf("entry %d points at %q", i+1, out[off:off+10])
+		}
+	}
+
+	// The first page's content stream inflates to the drawing operators
+	start := bytes.Index(out, []byte("stream\n")) + len("stream\n")
+	zr, err := zlib.NewReader(bytes.NewReader(out[start:]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, _ := io.ReadAll(zr)
+	want := "BT /F2 12 Tf 36 800 Td (Declara\xe7\xe3o \\(Anexo B\\) \x96 1.234,56 \x80) Tj ET\n0.5 w 36 790 m 300 790 l S\n"
+	if string(content) != want {
+		t.Errorf("unexpected content stream %q", content)
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	if got := TextWidth(Helvetica, 10, "EUR 10"); math.Abs(got-35.01) > 0.001 {
+		t.Errorf("expected 35.01 points, got %.3f", got)
+	}
+	// Accented letters are as wide as their base letter
+	if TextWidth(HelveticaBold, 8, "ção") != TextWidth(HelveticaBold, 8, "cao") {
+		t.Error("expected accents not to change the width")
+	}
+
+	s := Truncate(Helvetica, 8, 60, "Hospital Universitário de Santa Maria")
+	if !strings.HasSuffix(s, "…") || TextWidth(Helvetica, 8, s) > 60 {
+		t.Errorf("expected the name cut to fit 60 points, got %q", s)
+	}
+	if got := Truncate(Helvetica, 8, 60, "Hospital"); got != "Hospital" {
+		t.Errorf("expected a short name kept, got %q", got)
+	}
+}
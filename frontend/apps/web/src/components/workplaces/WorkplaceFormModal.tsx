@@ -6,6 +6,7 @@ import { createWorkplaceSchema, type CreateWorkplaceFormData } from '@doctor-tra
 import { PAY_MODEL_OPTIONS, WORKPLACE_COLORS } from '@doctor-tracker/shared/constants/pay-models';
 import { useCreateWorkplace, useUpdateWorkplace } from '../../lib/api';
 import { centsToEuros, eurosToCents } from '@doctor-tracker/shared/utils/currency';
+import { DEFAULT_WITHHOLDING_RATE } from '@doctor-tracker/shared/constants/tax-tables';
 import type { Workplace } from '@doctor-tracker/shared/types/workplace';
 
 interface Props {
@@ -54,17 +55,22 @@ export function WorkplaceFormModal({ workplace, onClose, onSuccess }: Props) {
           currency: 'EUR',
           has_consultation_pay: false,
           has_outside_visit_pay: false,
-          withholding_rate: 0.25,
+          withholding_rate: null,
         },
   });
 
   const selectedColor = watch('color');
   const payModel = watch('pay_model');
 
-  const onSubmit = async (data: CreateWorkplaceFormData) => {
+  const onSubmit = async (formData: CreateWorkplaceFormData) => {
+    const { withholding_rate, ...rest } = formData;
+    const data = { ...rest, withholding_rate: withholding_rate ?? undefined };
     try {
       if (isEditing) {
-        const result = await updateMutation.mutateAsync({ id: workplace.id, data });
+        const result = await updateMutation.mutateAsync({
+          id: workplace.id,
+          data: { ...data, clear_withholding_rate: withholding_rate === null },
+        });
         toast.success(t('workplaces.editWorkplace'));
         onSuccess(result);
       } else {
@@ -190,8 +196,9 @@ export function WorkplaceFormModal({ workplace, onClose, onSuccess }: Props) {
                 min="0"
                 max="100"
                 className="w-full px-3 py-2 pr-8 border border-gray-300 dark:border-gray-700 rounded-lg bg-white dark:bg-gray-800 focus:ring-2 focus:ring-primary-500 focus:border-primary-500"
-                value={Math.round(watch('withholding_rate') * 1000) / 10}
-                onChange={(e) => setValue('withholding_rate', (parseFloat(e.target.value) || 0) / 100)}
+                placeholder={String(DEFAULT_WITHHOLDING_RATE * 100)}
+                value={watch('withholding_rate') == null ? '' : Math.round(watch('withholding_rate')! * 1000) / 10}
+                onChange={(e) => setValue('withholding_rate', e.target.value === '' ? null : (parseFloat(e.target.value) || 0) / 100)}
               />
               <span className="absolute right-3 top-1/2 -translate-y-1/2 text-gray-400 text-sm">%</span>
             </div>
@@ -26,6 +26,7 @@ export const {
 
 export const {
   useShiftsInRange,
+  usePendingReview,
   useShift,
   useCreateShift,
   useUpdateShift,
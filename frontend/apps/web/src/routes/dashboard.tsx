@@ -2,7 +2,7 @@ import { useMemo, useState } from 'react';
 import { useNavigate } from 'react-router';
 import { useTranslation } from 'react-i18next';
 import { toast } from 'sonner';
-import { useShiftsInRange, useUpdateShift, useWorkplaces, useProjections } from '../lib/api';
+import { useShiftsInRange, usePendingReview, useUpdateShift, useWorkplaces, useProjections } from '../lib/api';
 import { formatEuros } from '@doctor-tracker/shared/utils/currency';
 import { calculatePortugueseTax } from '@doctor-tracker/shared/utils/tax';
 import { ShiftFormModal } from '../components/shifts/ShiftFormModal';
@@ -26,14 +26,11 @@ export function DashboardPage() {
     return [ninetyDaysAgo.toISOString(), twoWeeksLater.toISOString(), now.toISOString()];
   }, []);
   const { data: allShiftsRaw, isLoading: shiftsLoading } = useShiftsInRange(rangeStartISO, rangeEndISO);
+  const { data: pendingReview, isLoading: pendingLoading } = usePendingReview();
 
   const isLoading = wpLoading || shiftsLoading;
 
-  // Derive past/upcoming splits from the single query
-  const pastShifts = useMemo(() =>
-    allShiftsRaw?.filter((s) => new Date(s.start_time).getTime() < new Date(nowISO).getTime()),
-    [allShiftsRaw, nowISO],
-  );
+  // Derive upcoming shifts from the single query
   const upcomingShifts = useMemo(() =>
     allShiftsRaw?.filter((s) => new Date(s.start_time).getTime() >= new Date(nowISO).getTime() && s.status !== 'cancelled'),
     [allShiftsRaw, nowISO],
@@ -110,9 +107,8 @@ export function DashboardPage() {
     return mins > 0 ? `${whole}h ${mins}m` : `${whole}h`;
   };
 
-  // Past confirmed shifts that need completion
-  const shiftsToComplete = pastShifts
-    ?.filter((s) => s.status === 'confirmed')
+  // Past shifts still scheduled or confirmed, however long ago they were
+  const shiftsToComplete = [...(pendingReview ?? [])]
     .sort((a, b) => new Date(a.start_time).getTime() - new Date(b.start_time).getTime());
 
   // Only non-cancelled, sorted by start time
@@ -177,7 +173,7 @@ export function DashboardPage() {
       </div>
 
       {/* Shifts to Complete */}
-      {!shiftsLoading && shiftsToComplete && shiftsToComplete.length > 0 && (
+      {!pendingLoading && shiftsToComplete.length > 0 && (
         <div className="bg-amber-50 dark:bg-amber-950/30 rounded-xl border border-amber-200 dark:border-amber-800 p-6 mb-6">
           <h2 className="text-lg font-semibold mb-4">{t('dashboard.shiftsToComplete')} ({shiftsToComplete.length})</h2>
           <div className="space-y-2">
@@ -4,6 +4,7 @@ import { Link } from 'react-router';
 import { useMonthlyBreakdown, useWorkplaces } from '../lib/api';
 import { formatEuros } from '@doctor-tracker/shared/utils/currency';
 import type { WorkplaceEarnings } from '@doctor-tracker/shared/types/finance';
+import { DEFAULT_WITHHOLDING_RATE } from '@doctor-tracker/shared/constants/tax-tables';
 import type { Workplace } from '@doctor-tracker/shared/types/workplace';
 
 const currentYear = new Date().getFullYear();
@@ -27,7 +28,7 @@ export function WorkplaceEarningsPage() {
         if (!map.has(wp.workplace_id)) {
           const fullWp = workplaces.find((w) => w.id === wp.workplace_id);
           map.set(wp.workplace_id, {
-            workplace: fullWp || { id: wp.workplace_id, withholding_rate: 0.25, name: wp.workplace_name, color: wp.color } as Workplace,
+            workplace: fullWp || { id: wp.workplace_id, withholding_rate: null, name: wp.workplace_name, color: wp.color } as Workplace,
             months: new Map(),
           });
         }
@@ -114,7 +115,7 @@ function WorkplaceCard({
 }) {
   const { t, i18n } = useTranslation();
   const euroLocale = i18n.language === 'pt' ? 'pt-PT' : 'en-US';
-  const rate = workplace.withholding_rate;
+  const rate = workplace.withholding_rate ?? DEFAULT_WITHHOLDING_RATE;
   const isMonthly = workplace.pay_model === 'monthly';
   const showConsult = workplace.has_consultation_pay;
   const showVisits = workplace.has_outside_visit_pay;
@@ -10,6 +10,9 @@ export function createShiftsApi(client: KyInstance) {
       return client.get('api/v1/shifts', { searchParams }).json<ApiResponse<Shift[]>>();
     },
 
+    getPendingReview: () =>
+      client.get('api/v1/shifts/pending-review').json<ApiResponse<Shift[]>>(),
+
     get: (id: string) =>
       client.get(`api/v1/shifts/${id}`).json<ApiResponse<Shift>>(),
 
@@ -81,7 +81,7 @@ export function createFinanceHooks(client: KyInstance) {
         const res = await api.getTaxEstimate(year);
         return res.data!;
       },
-      staleTime: 1000 * 60 * 10,
+      staleTime: 1000 * 60 * 5,
     });
   }
 
@@ -105,6 +105,8 @@ export function createFinanceHooks(client: KyInstance) {
       },
       onSuccess: () => {
         queryClient.invalidateQueries({ queryKey: ['finance', 'invoices'] });
+        // Invoiced withholding feeds the estimate's settlement
+        queryClient.invalidateQueries({ queryKey: ['finance', 'tax-estimate'] });
       },
     });
   }
@@ -117,6 +119,8 @@ export function createFinanceHooks(client: KyInstance) {
       },
       onSuccess: () => {
         queryClient.invalidateQueries({ queryKey: ['finance', 'invoices'] });
+        // Invoiced withholding feeds the estimate's settlement
+        queryClient.invalidateQueries({ queryKey: ['finance', 'tax-estimate'] });
       },
     });
   }
@@ -6,6 +6,7 @@ import type { CreateShiftInput, UpdateShiftInput } from '../types/shift';
 export const shiftKeys = {
   all: ['shifts'] as const,
   byRange: (start: string, end: string) => ['shifts', { start, end }] as const,
+  pendingReview: ['shifts', 'pending-review'] as const,
   detail: (id: string) => ['shifts', id] as const,
   earnings: (id: string) => ['shifts', id, 'earnings'] as const,
 };
@@ -25,6 +26,18 @@ export function createShiftHooks(client: KyInstance) {
     });
   }
 
+  // Past shifts still scheduled or confirmed, as the nightly review finds them
+  function usePendingReview() {
+    return useQuery({
+      queryKey: shiftKeys.pendingReview,
+      queryFn: async () => {
+        const res = await api.getPendingReview();
+        return res.data ?? [];
+      },
+      staleTime: 1000 * 60 * 5,
+    });
+  }
+
   function useShift(id: string) {
     return useQuery({
       queryKey: shiftKeys.detail(id),
@@ -85,5 +98,5 @@ export function createShiftHooks(client: KyInstance) {
     });
   }
 
-  return { useShiftsInRange, useShift, useCreateShift, useUpdateShift, useDeleteShift, useShiftEarnings };
+  return { useShiftsInRange, usePendingReview, useShift, useCreateShift, useUpdateShift, useDeleteShift, useShiftEarnings };
 }
@@ -19,14 +19,30 @@ export interface WorkplaceEarnings {
 }
 
 export interface TaxEstimate {
-  gross_income: number;
+  fiscal_year: number;
+  gross_annual_income: number;
   taxable_income: number;
+  expense_shortfall: number;
+  employment_income?: number;
+  employment_taxable?: number;
+  irs_jovem_exempt?: number;
+  ifici_tax?: number;
   irs_amount: number;
   irs_effective_rate: number;
-  ss_annual: number;
+  social_security: number;
+  ss_monthly_base: number;
+  ss_quarterly_payment: number;
+  withholding_invoiced: number;
   withholding_total: number;
-  net_income: number;
+  employment_withheld?: number;
+  employment_ss?: number;
+  payments_on_account: number;
+  settlement_balance: number;
+  refund: number;
+  amount_due: number;
+  net_annual_income: number;
   monthly_net: number;
+  bracket_breakdown: BracketDetail[];
 }
 
 export interface BracketDetail {
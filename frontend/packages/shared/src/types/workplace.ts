@@ -14,7 +14,7 @@ export interface Workplace {
   monthly_expected_hours?: number;
   has_consultation_pay: boolean;
   has_outside_visit_pay: boolean;
-  withholding_rate: number;
+  withholding_rate: number | null; // null applies the fiscal year's default
   contact_name?: string;
   contact_phone?: string;
   contact_email?: string;
@@ -69,6 +69,7 @@ export interface UpdateWorkplaceInput {
   has_consultation_pay?: boolean;
   has_outside_visit_pay?: boolean;
   withholding_rate?: number;
+  clear_withholding_rate?: boolean;
   contact_name?: string;
   contact_phone?: string;
   contact_email?: string;